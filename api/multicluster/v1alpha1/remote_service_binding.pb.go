@@ -10,6 +10,7 @@
 
 	It has these top-level messages:
 		RemoteServiceBinding
+		RoutePolicy
 		ServiceExpositionPolicy
 */
 package v1alpha1
@@ -31,6 +32,36 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 
+// How a bound service is realized when the local cluster already runs a
+// K8s Service of the same name.
+type RemoteServiceBinding_Mode int32
+
+const (
+	// The binding replaces the local service: all the requests go to the
+	// remote clusters.
+	RemoteServiceBinding_REPLACE RemoteServiceBinding_Mode = 0
+	// The local Service is kept and receives the requests while it has ready
+	// endpoints. The remote clusters are only used as a fallback once it has
+	// none.
+	RemoteServiceBinding_FALLBACK RemoteServiceBinding_Mode = 1
+)
+
+var RemoteServiceBinding_Mode_name = map[int32]string{
+	0: "REPLACE",
+	1: "FALLBACK",
+}
+var RemoteServiceBinding_Mode_value = map[string]int32{
+	"REPLACE":  0,
+	"FALLBACK": 1,
+}
+
+func (x RemoteServiceBinding_Mode) String() string {
+	return proto.EnumName(RemoteServiceBinding_Mode_name, int32(x))
+}
+func (RemoteServiceBinding_Mode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptorRemoteServiceBinding, []int{0, 0}
+}
+
 // `RemoteServiceBinding` describes an the remote clusters that the local
 // cluster can access along with the remote services exposed by those remote
 // clusters. The information in this model allows binding a remote service for
@@ -65,6 +96,8 @@ type RemoteServiceBinding struct {
 	// remote service from each cluster that will be binded to local mesh
 	// services.
 	Remote []*RemoteServiceBinding_RemoteCluster `protobuf:"bytes,1,rep,name=remote" json:"remote,omitempty"`
+	// The mode of the binding, REPLACE by default.
+	Mode RemoteServiceBinding_Mode `protobuf:"varint,2,opt,name=mode,proto3,enum=istio.multicluster.v1alpha1.RemoteServiceBinding_Mode" json:"mode,omitempty"`
 }
 
 func (m *RemoteServiceBinding) Reset()         { *m = RemoteServiceBinding{} }
@@ -81,6 +114,13 @@ func (m *RemoteServiceBinding) GetRemote() []*RemoteServiceBinding_RemoteCluster
 	return nil
 }
 
+func (m *RemoteServiceBinding) GetMode() RemoteServiceBinding_Mode {
+	if m != nil {
+		return m.Mode
+	}
+	return RemoteServiceBinding_REPLACE
+}
+
 // Each remote cluster has an entry in the `RemoteServiceBinding`. As cluster
 // IDs are unique we don't expect two entries with the same name in a single
 // binding resource.
@@ -90,6 +130,10 @@ type RemoteServiceBinding_RemoteCluster struct {
 	// A list of remote service from the donor cluster to be binded into local
 	// services.
 	Services []*RemoteServiceBinding_RemoteCluster_RemoteService `protobuf:"bytes,2,rep,name=services" json:"services,omitempty"`
+	// The relative share of the requests sent to this cluster when the same
+	// service is bound from several clusters. The shares are normalized to
+	// percentages. If no cluster sets a weight the requests are split evenly.
+	Weight uint32 `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`
 }
 
 func (m *RemoteServiceBinding_RemoteCluster) Reset()         { *m = RemoteServiceBinding_RemoteCluster{} }
@@ -113,6 +157,13 @@ func (m *RemoteServiceBinding_RemoteCluster) GetServices() []*RemoteServiceBindi
 	return nil
 }
 
+func (m *RemoteServiceBinding_RemoteCluster) GetWeight() uint32 {
+	if m != nil {
+		return m.Weight
+	}
+	return 0
+}
+
 // Each exposed service by the donor cluster has a `RemoteService` entry.
 type RemoteServiceBinding_RemoteCluster_RemoteService struct {
 	// REQUIRED: The name of the exposed remote service.
@@ -128,6 +179,10 @@ type RemoteServiceBinding_RemoteCluster_RemoteService struct {
 	// The port of the exposed service.
 	// TODO: consider adding support for multiple ports, their types and names.
 	Port uint32 `protobuf:"varint,4,opt,name=port,proto3" json:"port,omitempty"`
+	// The route-level behavior the donor cluster applies to the requests
+	// to the service, as published by its agent. It is applied to the
+	// requests of the local clients.
+	Policy *RoutePolicy `protobuf:"bytes,5,opt,name=policy" json:"policy,omitempty"`
 }
 
 func (m *RemoteServiceBinding_RemoteCluster_RemoteService) Reset() {
@@ -169,10 +224,176 @@ func (m *RemoteServiceBinding_RemoteCluster_RemoteService) GetPort() uint32 {
 	return 0
 }
 
+func (m *RemoteServiceBinding_RemoteCluster_RemoteService) GetPolicy() *RoutePolicy {
+	if m != nil {
+		return m.Policy
+	}
+	return nil
+}
+
+// `RoutePolicy` is the route-level behavior of the VirtualService of an
+// exposed service. Durations are written like "1.5s" or "300ms".
+type RoutePolicy struct {
+	// Timeout for the requests. No timeout if empty.
+	Timeout string `protobuf:"bytes,1,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// Retries of failed requests. No retries if empty.
+	Retries *RoutePolicy_Retries `protobuf:"bytes,2,opt,name=retries" json:"retries,omitempty"`
+	// Faults injected into the requests.
+	Fault *RoutePolicy_Fault `protobuf:"bytes,3,opt,name=fault" json:"fault,omitempty"`
+}
+
+func (m *RoutePolicy) Reset()                    { *m = RoutePolicy{} }
+func (m *RoutePolicy) String() string            { return proto.CompactTextString(m) }
+func (*RoutePolicy) ProtoMessage()               {}
+func (*RoutePolicy) Descriptor() ([]byte, []int) { return fileDescriptorRemoteServiceBinding, []int{1} }
+
+func (m *RoutePolicy) GetTimeout() string {
+	if m != nil {
+		return m.Timeout
+	}
+	return ""
+}
+
+func (m *RoutePolicy) GetRetries() *RoutePolicy_Retries {
+	if m != nil {
+		return m.Retries
+	}
+	return nil
+}
+
+func (m *RoutePolicy) GetFault() *RoutePolicy_Fault {
+	if m != nil {
+		return m.Fault
+	}
+	return nil
+}
+
+// Describes the retries of failed requests.
+type RoutePolicy_Retries struct {
+	// Number of retries of a request.
+	Attempts int32 `protobuf:"varint,1,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	// Timeout per retry attempt.
+	PerTryTimeout string `protobuf:"bytes,2,opt,name=per_try_timeout,json=perTryTimeout,proto3" json:"per_try_timeout,omitempty"`
+}
+
+func (m *RoutePolicy_Retries) Reset()         { *m = RoutePolicy_Retries{} }
+func (m *RoutePolicy_Retries) String() string { return proto.CompactTextString(m) }
+func (*RoutePolicy_Retries) ProtoMessage()    {}
+func (*RoutePolicy_Retries) Descriptor() ([]byte, []int) {
+	return fileDescriptorRemoteServiceBinding, []int{1, 0}
+}
+
+func (m *RoutePolicy_Retries) GetAttempts() int32 {
+	if m != nil {
+		return m.Attempts
+	}
+	return 0
+}
+
+func (m *RoutePolicy_Retries) GetPerTryTimeout() string {
+	if m != nil {
+		return m.PerTryTimeout
+	}
+	return ""
+}
+
+// Describes the faults injected into the requests.
+type RoutePolicy_Fault struct {
+	// Delays injected into the requests.
+	Delay *RoutePolicy_Fault_Delay `protobuf:"bytes,1,opt,name=delay" json:"delay,omitempty"`
+	// Aborts of the requests.
+	Abort *RoutePolicy_Fault_Abort `protobuf:"bytes,2,opt,name=abort" json:"abort,omitempty"`
+}
+
+func (m *RoutePolicy_Fault) Reset()         { *m = RoutePolicy_Fault{} }
+func (m *RoutePolicy_Fault) String() string { return proto.CompactTextString(m) }
+func (*RoutePolicy_Fault) ProtoMessage()    {}
+func (*RoutePolicy_Fault) Descriptor() ([]byte, []int) {
+	return fileDescriptorRemoteServiceBinding, []int{1, 1}
+}
+
+func (m *RoutePolicy_Fault) GetDelay() *RoutePolicy_Fault_Delay {
+	if m != nil {
+		return m.Delay
+	}
+	return nil
+}
+
+func (m *RoutePolicy_Fault) GetAbort() *RoutePolicy_Fault_Abort {
+	if m != nil {
+		return m.Abort
+	}
+	return nil
+}
+
+// Describes the delays injected before forwarding the requests.
+type RoutePolicy_Fault_Delay struct {
+	// Percentage of requests to delay, 0 to 100.
+	Percent int32 `protobuf:"varint,1,opt,name=percent,proto3" json:"percent,omitempty"`
+	// The delay.
+	FixedDelay string `protobuf:"bytes,2,opt,name=fixed_delay,json=fixedDelay,proto3" json:"fixed_delay,omitempty"`
+}
+
+func (m *RoutePolicy_Fault_Delay) Reset()         { *m = RoutePolicy_Fault_Delay{} }
+func (m *RoutePolicy_Fault_Delay) String() string { return proto.CompactTextString(m) }
+func (*RoutePolicy_Fault_Delay) ProtoMessage()    {}
+func (*RoutePolicy_Fault_Delay) Descriptor() ([]byte, []int) {
+	return fileDescriptorRemoteServiceBinding, []int{1, 1, 0}
+}
+
+func (m *RoutePolicy_Fault_Delay) GetPercent() int32 {
+	if m != nil {
+		return m.Percent
+	}
+	return 0
+}
+
+func (m *RoutePolicy_Fault_Delay) GetFixedDelay() string {
+	if m != nil {
+		return m.FixedDelay
+	}
+	return ""
+}
+
+// Describes the aborts of the requests.
+type RoutePolicy_Fault_Abort struct {
+	// Percentage of requests to abort, 0 to 100.
+	Percent int32 `protobuf:"varint,1,opt,name=percent,proto3" json:"percent,omitempty"`
+	// HTTP status code returned to the aborted requests.
+	HttpStatus int32 `protobuf:"varint,2,opt,name=http_status,json=httpStatus,proto3" json:"http_status,omitempty"`
+}
+
+func (m *RoutePolicy_Fault_Abort) Reset()         { *m = RoutePolicy_Fault_Abort{} }
+func (m *RoutePolicy_Fault_Abort) String() string { return proto.CompactTextString(m) }
+func (*RoutePolicy_Fault_Abort) ProtoMessage()    {}
+func (*RoutePolicy_Fault_Abort) Descriptor() ([]byte, []int) {
+	return fileDescriptorRemoteServiceBinding, []int{1, 1, 1}
+}
+
+func (m *RoutePolicy_Fault_Abort) GetPercent() int32 {
+	if m != nil {
+		return m.Percent
+	}
+	return 0
+}
+
+func (m *RoutePolicy_Fault_Abort) GetHttpStatus() int32 {
+	if m != nil {
+		return m.HttpStatus
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*RemoteServiceBinding)(nil), "istio.multicluster.v1alpha1.RemoteServiceBinding")
 	proto.RegisterType((*RemoteServiceBinding_RemoteCluster)(nil), "istio.multicluster.v1alpha1.RemoteServiceBinding.RemoteCluster")
 	proto.RegisterType((*RemoteServiceBinding_RemoteCluster_RemoteService)(nil), "istio.multicluster.v1alpha1.RemoteServiceBinding.RemoteCluster.RemoteService")
+	proto.RegisterType((*RoutePolicy)(nil), "istio.multicluster.v1alpha1.RoutePolicy")
+	proto.RegisterType((*RoutePolicy_Retries)(nil), "istio.multicluster.v1alpha1.RoutePolicy.Retries")
+	proto.RegisterType((*RoutePolicy_Fault)(nil), "istio.multicluster.v1alpha1.RoutePolicy.Fault")
+	proto.RegisterType((*RoutePolicy_Fault_Delay)(nil), "istio.multicluster.v1alpha1.RoutePolicy.Fault.Delay")
+	proto.RegisterType((*RoutePolicy_Fault_Abort)(nil), "istio.multicluster.v1alpha1.RoutePolicy.Fault.Abort")
+	proto.RegisterEnum("istio.multicluster.v1alpha1.RemoteServiceBinding_Mode", RemoteServiceBinding_Mode_name, RemoteServiceBinding_Mode_value)
 }
 func (m *RemoteServiceBinding) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
@@ -201,6 +422,11 @@ func (m *RemoteServiceBinding) MarshalTo(dAtA []byte) (int, error) {
 			i += n
 		}
 	}
+	if m.Mode != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintRemoteServiceBinding(dAtA, i, uint64(m.Mode))
+	}
 	return i, nil
 }
 
@@ -237,6 +463,11 @@ func (m *RemoteServiceBinding_RemoteCluster) MarshalTo(dAtA []byte) (int, error)
 			i += n
 		}
 	}
+	if m.Weight != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintRemoteServiceBinding(dAtA, i, uint64(m.Weight))
+	}
 	return i, nil
 }
 
@@ -278,6 +509,184 @@ func (m *RemoteServiceBinding_RemoteCluster_RemoteService) MarshalTo(dAtA []byte
 		i++
 		i = encodeVarintRemoteServiceBinding(dAtA, i, uint64(m.Port))
 	}
+	if m.Policy != nil {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintRemoteServiceBinding(dAtA, i, uint64(m.Policy.Size()))
+		n1, err := m.Policy.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	return i, nil
+}
+
+func (m *RoutePolicy) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RoutePolicy) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Timeout) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintRemoteServiceBinding(dAtA, i, uint64(len(m.Timeout)))
+		i += copy(dAtA[i:], m.Timeout)
+	}
+	if m.Retries != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintRemoteServiceBinding(dAtA, i, uint64(m.Retries.Size()))
+		n2, err := m.Retries.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	if m.Fault != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintRemoteServiceBinding(dAtA, i, uint64(m.Fault.Size()))
+		n3, err := m.Fault.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n3
+	}
+	return i, nil
+}
+
+func (m *RoutePolicy_Retries) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RoutePolicy_Retries) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Attempts != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintRemoteServiceBinding(dAtA, i, uint64(m.Attempts))
+	}
+	if len(m.PerTryTimeout) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintRemoteServiceBinding(dAtA, i, uint64(len(m.PerTryTimeout)))
+		i += copy(dAtA[i:], m.PerTryTimeout)
+	}
+	return i, nil
+}
+
+func (m *RoutePolicy_Fault) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RoutePolicy_Fault) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Delay != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintRemoteServiceBinding(dAtA, i, uint64(m.Delay.Size()))
+		n4, err := m.Delay.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
+	if m.Abort != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintRemoteServiceBinding(dAtA, i, uint64(m.Abort.Size()))
+		n5, err := m.Abort.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n5
+	}
+	return i, nil
+}
+
+func (m *RoutePolicy_Fault_Delay) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RoutePolicy_Fault_Delay) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Percent != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintRemoteServiceBinding(dAtA, i, uint64(m.Percent))
+	}
+	if len(m.FixedDelay) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintRemoteServiceBinding(dAtA, i, uint64(len(m.FixedDelay)))
+		i += copy(dAtA[i:], m.FixedDelay)
+	}
+	return i, nil
+}
+
+func (m *RoutePolicy_Fault_Abort) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RoutePolicy_Fault_Abort) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Percent != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintRemoteServiceBinding(dAtA, i, uint64(m.Percent))
+	}
+	if m.HttpStatus != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintRemoteServiceBinding(dAtA, i, uint64(m.HttpStatus))
+	}
 	return i, nil
 }
 
@@ -299,6 +708,9 @@ func (m *RemoteServiceBinding) Size() (n int) {
 			n += 1 + l + sovRemoteServiceBinding(uint64(l))
 		}
 	}
+	if m.Mode != 0 {
+		n += 1 + sovRemoteServiceBinding(uint64(m.Mode))
+	}
 	return n
 }
 
@@ -315,6 +727,9 @@ func (m *RemoteServiceBinding_RemoteCluster) Size() (n int) {
 			n += 1 + l + sovRemoteServiceBinding(uint64(l))
 		}
 	}
+	if m.Weight != 0 {
+		n += 1 + sovRemoteServiceBinding(uint64(m.Weight))
+	}
 	return n
 }
 
@@ -336,31 +751,105 @@ func (m *RemoteServiceBinding_RemoteCluster_RemoteService) Size() (n int) {
 	if m.Port != 0 {
 		n += 1 + sovRemoteServiceBinding(uint64(m.Port))
 	}
+	if m.Policy != nil {
+		l = m.Policy.Size()
+		n += 1 + l + sovRemoteServiceBinding(uint64(l))
+	}
 	return n
 }
 
-func sovRemoteServiceBinding(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
-		}
+func (m *RoutePolicy) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Timeout)
+	if l > 0 {
+		n += 1 + l + sovRemoteServiceBinding(uint64(l))
+	}
+	if m.Retries != nil {
+		l = m.Retries.Size()
+		n += 1 + l + sovRemoteServiceBinding(uint64(l))
+	}
+	if m.Fault != nil {
+		l = m.Fault.Size()
+		n += 1 + l + sovRemoteServiceBinding(uint64(l))
 	}
 	return n
 }
-func sozRemoteServiceBinding(x uint64) (n int) {
-	return sovRemoteServiceBinding(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+
+func (m *RoutePolicy_Retries) Size() (n int) {
+	var l int
+	_ = l
+	if m.Attempts != 0 {
+		n += 1 + sovRemoteServiceBinding(uint64(m.Attempts))
+	}
+	l = len(m.PerTryTimeout)
+	if l > 0 {
+		n += 1 + l + sovRemoteServiceBinding(uint64(l))
+	}
+	return n
 }
-func (m *RemoteServiceBinding) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowRemoteServiceBinding
+
+func (m *RoutePolicy_Fault) Size() (n int) {
+	var l int
+	_ = l
+	if m.Delay != nil {
+		l = m.Delay.Size()
+		n += 1 + l + sovRemoteServiceBinding(uint64(l))
+	}
+	if m.Abort != nil {
+		l = m.Abort.Size()
+		n += 1 + l + sovRemoteServiceBinding(uint64(l))
+	}
+	return n
+}
+
+func (m *RoutePolicy_Fault_Delay) Size() (n int) {
+	var l int
+	_ = l
+	if m.Percent != 0 {
+		n += 1 + sovRemoteServiceBinding(uint64(m.Percent))
+	}
+	l = len(m.FixedDelay)
+	if l > 0 {
+		n += 1 + l + sovRemoteServiceBinding(uint64(l))
+	}
+	return n
+}
+
+func (m *RoutePolicy_Fault_Abort) Size() (n int) {
+	var l int
+	_ = l
+	if m.Percent != 0 {
+		n += 1 + sovRemoteServiceBinding(uint64(m.Percent))
+	}
+	if m.HttpStatus != 0 {
+		n += 1 + sovRemoteServiceBinding(uint64(m.HttpStatus))
+	}
+	return n
+}
+
+func sovRemoteServiceBinding(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozRemoteServiceBinding(x uint64) (n int) {
+	return sovRemoteServiceBinding(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *RemoteServiceBinding) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRemoteServiceBinding
 			}
 			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
@@ -412,6 +901,25 @@ func (m *RemoteServiceBinding) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mode", wireType)
+			}
+			m.Mode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemoteServiceBinding
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Mode |= (RemoteServiceBinding_Mode(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRemoteServiceBinding(dAtA[iNdEx:])
@@ -522,6 +1030,25 @@ func (m *RemoteServiceBinding_RemoteCluster) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Weight", wireType)
+			}
+			m.Weight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemoteServiceBinding
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Weight |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRemoteServiceBinding(dAtA[iNdEx:])
@@ -678,6 +1205,584 @@ func (m *RemoteServiceBinding_RemoteCluster_RemoteService) Unmarshal(dAtA []byte
 					break
 				}
 			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Policy", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemoteServiceBinding
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRemoteServiceBinding
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Policy == nil {
+				m.Policy = &RoutePolicy{}
+			}
+			if err := m.Policy.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRemoteServiceBinding(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRemoteServiceBinding
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RoutePolicy) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRemoteServiceBinding
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RoutePolicy: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RoutePolicy: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timeout", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemoteServiceBinding
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRemoteServiceBinding
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Timeout = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Retries", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemoteServiceBinding
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRemoteServiceBinding
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Retries == nil {
+				m.Retries = &RoutePolicy_Retries{}
+			}
+			if err := m.Retries.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Fault", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemoteServiceBinding
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRemoteServiceBinding
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Fault == nil {
+				m.Fault = &RoutePolicy_Fault{}
+			}
+			if err := m.Fault.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRemoteServiceBinding(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRemoteServiceBinding
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RoutePolicy_Retries) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRemoteServiceBinding
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Retries: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Retries: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attempts", wireType)
+			}
+			m.Attempts = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemoteServiceBinding
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Attempts |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PerTryTimeout", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemoteServiceBinding
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRemoteServiceBinding
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PerTryTimeout = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRemoteServiceBinding(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRemoteServiceBinding
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RoutePolicy_Fault) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRemoteServiceBinding
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Fault: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Fault: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Delay", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemoteServiceBinding
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRemoteServiceBinding
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Delay == nil {
+				m.Delay = &RoutePolicy_Fault_Delay{}
+			}
+			if err := m.Delay.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Abort", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemoteServiceBinding
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRemoteServiceBinding
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Abort == nil {
+				m.Abort = &RoutePolicy_Fault_Abort{}
+			}
+			if err := m.Abort.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRemoteServiceBinding(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRemoteServiceBinding
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RoutePolicy_Fault_Delay) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRemoteServiceBinding
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Delay: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Delay: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Percent", wireType)
+			}
+			m.Percent = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemoteServiceBinding
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Percent |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FixedDelay", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemoteServiceBinding
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRemoteServiceBinding
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FixedDelay = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRemoteServiceBinding(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRemoteServiceBinding
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RoutePolicy_Fault_Abort) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRemoteServiceBinding
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Abort: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Abort: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Percent", wireType)
+			}
+			m.Percent = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemoteServiceBinding
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Percent |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HttpStatus", wireType)
+			}
+			m.HttpStatus = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemoteServiceBinding
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.HttpStatus |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRemoteServiceBinding(dAtA[iNdEx:])
@@ -809,24 +1914,42 @@ func init() {
 }
 
 var fileDescriptorRemoteServiceBinding = []byte{
-	// 302 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x92, 0xb1, 0x4e, 0xf3, 0x30,
-	0x14, 0x85, 0xe5, 0xb6, 0x7f, 0x7f, 0x6a, 0xd4, 0xc5, 0xea, 0x60, 0x15, 0x14, 0x45, 0x4c, 0x59,
-	0x6a, 0xab, 0xe5, 0x01, 0x90, 0xca, 0x0a, 0x4b, 0x18, 0x90, 0xba, 0x54, 0x4e, 0x6a, 0x35, 0x16,
-	0x71, 0x6c, 0xd9, 0x4e, 0x77, 0xde, 0x8e, 0x91, 0x47, 0x40, 0x79, 0x05, 0x5e, 0x00, 0xc5, 0x71,
-	0xa0, 0x95, 0x2a, 0x16, 0xb6, 0x7b, 0xef, 0xb9, 0xe7, 0xd3, 0xb9, 0xb2, 0xe1, 0x4a, 0xd6, 0xa5,
-	0x13, 0x79, 0x59, 0x5b, 0xc7, 0x0d, 0x3d, 0x2c, 0x59, 0xa9, 0x0b, 0xb6, 0xa4, 0x86, 0x4b, 0xe5,
-	0xf8, 0xd6, 0x72, 0x73, 0x10, 0x39, 0xdf, 0x66, 0xa2, 0xda, 0x89, 0x6a, 0x4f, 0xb4, 0x51, 0x4e,
-	0xa1, 0x2b, 0x61, 0x9d, 0x50, 0xe4, 0xd8, 0x49, 0x7a, 0xe7, 0xcd, 0xe7, 0x00, 0xce, 0x52, 0xef,
-	0x7e, 0xea, 0xcc, 0xeb, 0xce, 0x8b, 0x9e, 0xe1, 0xb8, 0xa3, 0x62, 0x10, 0x0f, 0x93, 0xcb, 0xd5,
-	0x1d, 0xf9, 0x05, 0x43, 0xce, 0x21, 0xc2, 0xf0, 0xbe, 0xdb, 0x4d, 0x03, 0x6e, 0xfe, 0x3a, 0x80,
-	0xd3, 0x13, 0x05, 0x61, 0xf8, 0x3f, 0x00, 0x31, 0x88, 0x41, 0x32, 0x49, 0xfb, 0x16, 0x09, 0x78,
-	0x11, 0x6e, 0xb2, 0x78, 0xe0, 0x63, 0x3c, 0xfe, 0x31, 0xc6, 0xe9, 0x4a, 0xfa, 0x8d, 0x9f, 0xbf,
-	0xf4, 0xa9, 0x82, 0x84, 0x10, 0x1c, 0x55, 0x4c, 0xf2, 0x10, 0xc9, 0xd7, 0x68, 0x06, 0xff, 0xb1,
-	0x52, 0xb0, 0x36, 0x4c, 0x3b, 0xec, 0x1a, 0x74, 0x0d, 0x27, 0xad, 0x6a, 0x35, 0xcb, 0x39, 0x1e,
-	0x7a, 0xe5, 0x67, 0xd0, 0x72, 0xb4, 0x32, 0x0e, 0x8f, 0x62, 0x90, 0x4c, 0x53, 0x5f, 0xaf, 0x37,
-	0x6f, 0x4d, 0x04, 0xde, 0x9b, 0x08, 0x7c, 0x34, 0x11, 0xd8, 0x3c, 0xec, 0x85, 0x2b, 0xea, 0x8c,
-	0x88, 0x4c, 0x92, 0x5c, 0x49, 0xea, 0x2f, 0x5c, 0x18, 0x6e, 0x39, 0x33, 0x79, 0x41, 0x8f, 0x4f,
-	0x5d, 0x18, 0xc5, 0x76, 0x92, 0x69, 0xca, 0xb4, 0xa0, 0x67, 0xff, 0x42, 0x36, 0xf6, 0xaf, 0x7e,
-	0xfb, 0x15, 0x00, 0x00, 0xff, 0xff, 0xf8, 0xaf, 0x0c, 0x2e, 0x2b, 0x02, 0x00, 0x00,
+	// 586 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x94, 0x4f, 0x6f, 0xd3, 0x3e,
+	0x18, 0xc7, 0x7f, 0xe9, 0x92, 0x76, 0x7d, 0xf2, 0x2b, 0x4c, 0xd6, 0x84, 0xa2, 0x80, 0x46, 0xd9,
+	0x01, 0xe5, 0xb2, 0x84, 0x05, 0xc4, 0x15, 0xda, 0xfd, 0x39, 0x8c, 0x4e, 0x9a, 0xbc, 0x49, 0x48,
+	0xbb, 0x44, 0x6e, 0xe2, 0xad, 0x96, 0x92, 0xda, 0x72, 0x9c, 0x41, 0x5f, 0x11, 0x27, 0x2e, 0xdc,
+	0xb9, 0x73, 0xe4, 0x05, 0x70, 0x40, 0x7b, 0x25, 0x28, 0x76, 0x32, 0x36, 0x69, 0xaa, 0x56, 0x71,
+	0xcb, 0xf3, 0xc4, 0x9f, 0x8f, 0xfd, 0x7d, 0x2c, 0x19, 0xe2, 0xa2, 0xca, 0x15, 0x4b, 0xf3, 0xaa,
+	0x54, 0x54, 0x46, 0x57, 0xbb, 0x24, 0x17, 0x33, 0xb2, 0x1b, 0x49, 0x5a, 0x70, 0x45, 0x93, 0x92,
+	0xca, 0x2b, 0x96, 0xd2, 0x64, 0xca, 0xe6, 0x19, 0x9b, 0x5f, 0x86, 0x42, 0x72, 0xc5, 0xd1, 0x53,
+	0x56, 0x2a, 0xc6, 0xc3, 0xdb, 0x64, 0xd8, 0x92, 0xdb, 0xdf, 0x6d, 0xd8, 0xc4, 0x9a, 0x3e, 0x35,
+	0xf0, 0xd8, 0xb0, 0xe8, 0x23, 0x74, 0x8d, 0xd5, 0xb3, 0x86, 0x6b, 0x81, 0x1b, 0xbf, 0x0b, 0x97,
+	0x68, 0xc2, 0xfb, 0x14, 0x4d, 0x73, 0xcf, 0xac, 0xc5, 0x8d, 0x0e, 0x1d, 0x81, 0x5d, 0xf0, 0x8c,
+	0x7a, 0x9d, 0xa1, 0x15, 0x3c, 0x8a, 0xdf, 0xae, 0xae, 0x3d, 0xe6, 0x19, 0xc5, 0xda, 0xe1, 0xff,
+	0xea, 0xc0, 0xe0, 0xce, 0x2e, 0xc8, 0x83, 0x5e, 0x63, 0xf1, 0xac, 0xa1, 0x15, 0xf4, 0x71, 0x5b,
+	0x22, 0x06, 0xeb, 0xcd, 0x7c, 0x4a, 0xaf, 0xa3, 0x23, 0x1d, 0xff, 0x63, 0xa4, 0xbb, 0x4b, 0xf0,
+	0x8d, 0x1e, 0x3d, 0x81, 0xee, 0x27, 0xca, 0x2e, 0x67, 0xca, 0x5b, 0x1b, 0x5a, 0xc1, 0x00, 0x37,
+	0x95, 0xff, 0xd5, 0x82, 0xc1, 0x1d, 0x06, 0x21, 0xb0, 0xe7, 0xa4, 0xa0, 0xcd, 0x59, 0xf5, 0x37,
+	0xda, 0x04, 0x87, 0xe4, 0x8c, 0x94, 0x7a, 0x42, 0x7d, 0x6c, 0x0a, 0xf4, 0x0c, 0xfa, 0xf5, 0xdf,
+	0x52, 0x90, 0x94, 0x6a, 0x6d, 0x1f, 0xff, 0x6d, 0xd4, 0x1e, 0xc1, 0xa5, 0xf2, 0x6c, 0xbd, 0x9f,
+	0xfe, 0x46, 0xef, 0xa1, 0x2b, 0x78, 0xce, 0xd2, 0x85, 0xe7, 0x0c, 0xad, 0xc0, 0x8d, 0x83, 0xe5,
+	0x71, 0x79, 0xa5, 0xe8, 0x89, 0x5e, 0x8f, 0x1b, 0x6e, 0xfb, 0x05, 0xd8, 0xf5, 0xb0, 0x91, 0x0b,
+	0x3d, 0x7c, 0x70, 0x32, 0x19, 0xed, 0x1d, 0x6c, 0xfc, 0x87, 0xfe, 0x87, 0xf5, 0xc3, 0xd1, 0x64,
+	0x32, 0x1e, 0xed, 0x7d, 0xd8, 0xb0, 0xb6, 0xbf, 0xd9, 0xe0, 0xde, 0x42, 0xeb, 0xf9, 0x2b, 0x56,
+	0x50, 0x5e, 0xa9, 0x76, 0xfe, 0x4d, 0x89, 0x8e, 0xa0, 0x27, 0xa9, 0x92, 0x8c, 0x9a, 0x60, 0x6e,
+	0xfc, 0xea, 0xa1, 0xe7, 0x09, 0xb1, 0xe1, 0x70, 0x2b, 0x40, 0xfb, 0xe0, 0x5c, 0x90, 0x2a, 0x37,
+	0xf3, 0x75, 0xe3, 0xf0, 0xc1, 0xa6, 0xc3, 0x9a, 0xc2, 0x06, 0xf6, 0x8f, 0xa1, 0xd7, 0x98, 0x91,
+	0x0f, 0xeb, 0x44, 0x29, 0x5a, 0x08, 0x55, 0xea, 0x73, 0x3b, 0xf8, 0xa6, 0x46, 0x2f, 0xe1, 0xb1,
+	0xa0, 0x32, 0x51, 0x72, 0x91, 0xb4, 0xd1, 0xcc, 0xcd, 0x0c, 0x04, 0x95, 0x67, 0x72, 0x71, 0x66,
+	0x9a, 0xfe, 0x97, 0x0e, 0x38, 0xda, 0x8f, 0x8e, 0xc0, 0xc9, 0x68, 0x4e, 0x16, 0x5a, 0xe5, 0xc6,
+	0x6f, 0x56, 0x3b, 0x5e, 0xb8, 0x5f, 0xb3, 0xd8, 0x28, 0x6a, 0x17, 0x99, 0x72, 0x69, 0xf6, 0x5c,
+	0xdd, 0x35, 0xaa, 0x59, 0x6c, 0x14, 0xfe, 0x18, 0x1c, 0xed, 0xae, 0x6f, 0x49, 0x50, 0x99, 0xd2,
+	0xb9, 0x6a, 0xd2, 0xb6, 0x25, 0x7a, 0x0e, 0xee, 0x05, 0xfb, 0x4c, 0xb3, 0xc4, 0x04, 0x30, 0x41,
+	0x41, 0xb7, 0x34, 0x5a, 0x3b, 0xb4, 0x73, 0xb9, 0x63, 0xa6, 0x94, 0x48, 0x4a, 0x45, 0x54, 0x65,
+	0x6e, 0xdb, 0xc1, 0x50, 0xb7, 0x4e, 0x75, 0x67, 0x7c, 0xfe, 0xe3, 0x7a, 0xcb, 0xfa, 0x79, 0xbd,
+	0x65, 0xfd, 0xbe, 0xde, 0xb2, 0xce, 0x27, 0x97, 0x4c, 0xcd, 0xaa, 0x69, 0xc8, 0xa6, 0x45, 0x98,
+	0xf2, 0x22, 0xd2, 0x01, 0x77, 0x24, 0x2d, 0x29, 0x91, 0xe9, 0x2c, 0xba, 0x9d, 0x74, 0x47, 0x72,
+	0x92, 0x15, 0x44, 0x44, 0x44, 0xb0, 0xe8, 0xde, 0xa7, 0x70, 0xda, 0xd5, 0x8f, 0xde, 0xeb, 0x3f,
+	0x03, 0x00, 0xba, 0xcf, 0xe9, 0xc7, 0x2a, 0x05, 0x00, 0x00,
 }
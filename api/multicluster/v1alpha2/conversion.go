@@ -0,0 +1,186 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha2
+
+import (
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha1"
+)
+
+// Conversions between the v1alpha1 and v1alpha2 specs. Every v1alpha1 spec
+// converts to v1alpha2 and back unchanged. A v1alpha2 spec using what v1alpha1
+// can't express (several ports, named ports or protocols, selectors and
+// per-service weights) loses it when converted to v1alpha1: the ToV1alpha1
+// functions then return false, so that the caller can keep the v1alpha2 spec
+// aside.
+
+// ServiceExpositionPolicyFromV1alpha1 converts a v1alpha1 ServiceExpositionPolicy
+func ServiceExpositionPolicyFromV1alpha1(in *v1alpha1.ServiceExpositionPolicy) *ServiceExpositionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := &ServiceExpositionPolicy{}
+	for _, es := range in.Exposed {
+		out.Exposed = append(out.Exposed, &ServiceExpositionPolicy_ExposedService{
+			Name:     es.Name,
+			Alias:    es.Alias,
+			Subset:   es.Subset,
+			Ports:    PortsFromV1alpha1(es.Port),
+			Clusters: copyStrings(es.Clusters),
+		})
+	}
+	return out
+}
+
+// ServiceExpositionPolicyToV1alpha1 converts a ServiceExpositionPolicy to
+// v1alpha1. It returns false if the v1alpha1 policy lost some of the spec.
+func ServiceExpositionPolicyToV1alpha1(in *ServiceExpositionPolicy) (*v1alpha1.ServiceExpositionPolicy, bool) {
+	if in == nil {
+		return nil, true
+	}
+	out := &v1alpha1.ServiceExpositionPolicy{}
+	for _, es := range in.Exposed {
+		out.Exposed = append(out.Exposed, &v1alpha1.ServiceExpositionPolicy_ExposedService{
+			Name:     es.Name,
+			Alias:    es.Alias,
+			Subset:   es.Subset,
+			Port:     PrimaryPortNumber(es.Ports),
+			Clusters: copyStrings(es.Clusters),
+		})
+	}
+	return out, proto.Equal(ServiceExpositionPolicyFromV1alpha1(out), in)
+}
+
+// RemoteServiceBindingFromV1alpha1 converts a v1alpha1 RemoteServiceBinding
+func RemoteServiceBindingFromV1alpha1(in *v1alpha1.RemoteServiceBinding) *RemoteServiceBinding {
+	if in == nil {
+		return nil
+	}
+	out := &RemoteServiceBinding{Mode: RemoteServiceBinding_Mode(in.Mode)}
+	for _, remote := range in.Remote {
+		cluster := &RemoteServiceBinding_RemoteCluster{
+			Cluster: remote.Cluster,
+			Weight:  remote.Weight,
+		}
+		for _, svc := range remote.Services {
+			cluster.Services = append(cluster.Services, &RemoteServiceBinding_RemoteCluster_RemoteService{
+				Name:      svc.Name,
+				Alias:     svc.Alias,
+				Namespace: svc.Namespace,
+				Ports:     PortsFromV1alpha1(svc.Port),
+				Policy:    RoutePolicyFromV1alpha1(svc.Policy),
+			})
+		}
+		out.Remote = append(out.Remote, cluster)
+	}
+	return out
+}
+
+// RemoteServiceBindingToV1alpha1 converts a RemoteServiceBinding to v1alpha1.
+// It returns false if the v1alpha1 binding lost some of the spec.
+func RemoteServiceBindingToV1alpha1(in *RemoteServiceBinding) (*v1alpha1.RemoteServiceBinding, bool) {
+	if in == nil {
+		return nil, true
+	}
+	out := &v1alpha1.RemoteServiceBinding{Mode: v1alpha1.RemoteServiceBinding_Mode(in.Mode)}
+	for _, remote := range in.Remote {
+		cluster := &v1alpha1.RemoteServiceBinding_RemoteCluster{
+			Cluster: remote.Cluster,
+			Weight:  remote.Weight,
+		}
+		for _, svc := range remote.Services {
+			cluster.Services = append(cluster.Services, &v1alpha1.RemoteServiceBinding_RemoteCluster_RemoteService{
+				Name:      svc.Name,
+				Alias:     svc.Alias,
+				Namespace: svc.Namespace,
+				Port:      PrimaryPortNumber(svc.Ports),
+				Policy:    RoutePolicyToV1alpha1(svc.Policy),
+			})
+		}
+		out.Remote = append(out.Remote, cluster)
+	}
+	return out, proto.Equal(RemoteServiceBindingFromV1alpha1(out), in)
+}
+
+// RoutePolicyFromV1alpha1 converts a v1alpha1 RoutePolicy, which has the
+// same fields
+func RoutePolicyFromV1alpha1(in *v1alpha1.RoutePolicy) *RoutePolicy {
+	if in == nil {
+		return nil
+	}
+	out := &RoutePolicy{Timeout: in.Timeout}
+	if in.Retries != nil {
+		out.Retries = &RoutePolicy_Retries{Attempts: in.Retries.Attempts, PerTryTimeout: in.Retries.PerTryTimeout}
+	}
+	if in.Fault != nil {
+		out.Fault = &RoutePolicy_Fault{}
+		if in.Fault.Delay != nil {
+			out.Fault.Delay = &RoutePolicy_Fault_Delay{Percent: in.Fault.Delay.Percent, FixedDelay: in.Fault.Delay.FixedDelay}
+		}
+		if in.Fault.Abort != nil {
+			out.Fault.Abort = &RoutePolicy_Fault_Abort{Percent: in.Fault.Abort.Percent, HttpStatus: in.Fault.Abort.HttpStatus}
+		}
+	}
+	return out
+}
+
+// RoutePolicyToV1alpha1 converts a RoutePolicy to v1alpha1
+func RoutePolicyToV1alpha1(in *RoutePolicy) *v1alpha1.RoutePolicy {
+	if in == nil {
+		return nil
+	}
+	out := &v1alpha1.RoutePolicy{Timeout: in.Timeout}
+	if in.Retries != nil {
+		out.Retries = &v1alpha1.RoutePolicy_Retries{Attempts: in.Retries.Attempts, PerTryTimeout: in.Retries.PerTryTimeout}
+	}
+	if in.Fault != nil {
+		out.Fault = &v1alpha1.RoutePolicy_Fault{}
+		if in.Fault.Delay != nil {
+			out.Fault.Delay = &v1alpha1.RoutePolicy_Fault_Delay{Percent: in.Fault.Delay.Percent, FixedDelay: in.Fault.Delay.FixedDelay}
+		}
+		if in.Fault.Abort != nil {
+			out.Fault.Abort = &v1alpha1.RoutePolicy_Fault_Abort{Percent: in.Fault.Abort.Percent, HttpStatus: in.Fault.Abort.HttpStatus}
+		}
+	}
+	return out
+}
+
+// PortsFromV1alpha1 converts the single port of a v1alpha1 service, no ports
+// if 0
+func PortsFromV1alpha1(port uint32) []*ServicePort {
+	if port == 0 {
+		return nil
+	}
+	return []*ServicePort{{Number: port}}
+}
+
+// PrimaryPortNumber returns the number of the first port, the single port of
+// a v1alpha1 service, 0 if none
+func PrimaryPortNumber(ports []*ServicePort) uint32 {
+	if len(ports) == 0 {
+		return 0
+	}
+	return ports[0].Number
+}
+
+func copyStrings(in []string) []string {
+	if in == nil {
+		return nil
+	}
+	out := make([]string, len(in))
+	copy(out, in)
+	return out
+}
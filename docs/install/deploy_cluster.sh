@@ -86,5 +86,6 @@ data:
 EOF
 set -e
 	
-# Deploy the MC agent service
+# Install or upgrade the Multi-Cluster CRDs, then deploy the MC agent service
+kubectl --context $CLIENT_NAME apply -f crds.yaml
 kubectl --context $CLIENT_NAME apply -f deploy.yaml
@@ -17,9 +17,11 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"sync"
 	"syscall"
 	"time"
 
@@ -30,6 +32,7 @@ import (
 	"github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/agent"
 	mccrd "github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/config/kube/crd"
 	mcmodel "github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/model"
+	"github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/webhook"
 
 	"istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pilot/pkg/serviceregistry/kube"
@@ -38,10 +41,15 @@ import (
 	// Importing the API messages so that when resource events are fired the
 	// resource will be parsed into a message object
 	_ "github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha1"
+	_ "github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha2"
 )
 
 const (
 	resyncPeriod = 1 * time.Second
+
+	// The K8s Service of the webhook deployed with docs/install/webhook.yaml
+	webhookService   = "mc-agent-webhook"
+	webhookNamespace = "istio-system"
 )
 
 var (
@@ -50,12 +58,21 @@ var (
 	context    string
 	config     string
 
+	webhookPort uint
+	webhookCert string
+	webhookKey  string
+
+	registerCRDs     bool
+	conversionCACert string
+
 	mcStore       mcmodel.MCConfigStore
 	istioStore    model.ConfigStore
 	clusterConfig *agent.ClusterConfig
-	configWatcher *fsnotify.Watcher
-	clientsCfgCh  map[string](chan agent.ClusterConfig)
-	stopCh        chan struct{}
+	// clusterConfigMutex guards clusterConfig, replaced when its file changes
+	clusterConfigMutex sync.RWMutex
+	configWatcher      *fsnotify.Watcher
+	clientsCfgCh       map[string](chan agent.ClusterConfig)
+	stopCh             chan struct{}
 
 	configsMgmt *agent.ConfigsManagement
 )
@@ -63,13 +80,32 @@ var (
 func main() {
 	flag.Parse()
 
+	// Install or upgrade the Multi-Cluster CRDs and exit
+	if registerCRDs {
+		cl, err := mccrd.NewClient(kubeconfig, context, mcmodel.MultiClusterConfigTypes, namespace)
+		var conversion *mccrd.ConversionWebhook
+		if err == nil && conversionCACert != "" {
+			conversion, err = conversionWebhook(conversionCACert)
+		}
+		if err == nil {
+			err = cl.RegisterResources(conversion)
+		}
+		if err != nil {
+			log.Errorf("Could not register the Multi-Cluster CRDs: %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Load the cluster config from the provided as a yaml file
 	var err error
+	var cc *agent.ClusterConfig
 	if config != "" {
-		clusterConfig, err = agent.LoadConfig(config)
+		cc, err = loadConfig(config)
 		if err != nil {
 			log.Errorf("Could not load config: %v", err)
 		}
+		setClusterConfig(cc)
 		configWatcher = launchConfigWatcher(config)
 	} else {
 		err = fmt.Errorf("cluster configuration file must be provided with the -config flag")
@@ -81,7 +117,7 @@ func main() {
 	if configWatcher != nil {
 		defer configWatcher.Close()
 	}
-	log.Debugf("Cluster Configuration: %#v", clusterConfig)
+	log.Debugf("Cluster Configuration: %#v", cc)
 
 	// Set up a Kubernetes API ConfigStore for Istio configs
 	istioStore, err := makeKubeConfigIstioController()
@@ -90,30 +126,22 @@ func main() {
 		return
 	}
 
-	configsMgmt = agent.NewConfigsManagement(kubeconfig, context, istioStore, clusterConfig)
-	if configsMgmt == nil {
-		log.Error("Failed to create an instance of ConfigsManagement")
-		return
-	}
-
 	// Set up a store wrapper for the Multi-Cluster controller
-	desc := model.ConfigDescriptor{mcmodel.ServiceExpositionPolicy, mcmodel.RemoteServiceBinding}
-	cl, err := mccrd.NewClient(kubeconfig, context, desc, namespace)
+	cl, err := mccrd.NewClient(kubeconfig, context, mcmodel.MultiClusterConfigTypes, namespace)
 	if err != nil {
 		log.Errorf("Could not create MC CRD Client: %v", err)
 		return
 	}
 
-	// Register the Multi-Cluster CRDs if they aren't already registered
-	// err = cl.RegisterResources()
-	// if err != nil {
-	// 	log.Errora(err)
-	// 	return
-	// }
-
 	// Setting up a controller for the configured namespace to periodically watch for changes
 	ctl := mccrd.NewController(cl, kube.ControllerOptions{WatchedNamespace: namespace, ResyncPeriod: resyncPeriod})
 
+	configsMgmt = agent.NewConfigsManagement(kubeconfig, context, istioStore, ctl, cc)
+	if configsMgmt == nil {
+		log.Error("Failed to create an instance of ConfigsManagement")
+		return
+	}
+
 	// Register model configs event handler that will update the config store accordingly
 	// for ServiceExpositionPolicy resources
 	ctl.RegisterEventHandler(mcmodel.ServiceExpositionPolicy.Type, func(config model.Config, ev model.Event) {
@@ -131,6 +159,24 @@ func main() {
 		log.Debugf("Config store now has %d ServiceExpositionPolicy entries", len(mcStore.ServiceExpositionPolicies()))
 	})
 
+	// Register model configs event handler that will update the config store accordingly
+	// for ClusterServiceExpositionPolicy resources
+	ctl.RegisterEventHandler(mcmodel.ClusterServiceExpositionPolicy.Type, func(config model.Config, ev model.Event) {
+		switch ev {
+		case model.EventAdd:
+			log.Debugf("ClusterServiceExpositionPolicy resource was added. Name: %s", config.Name)
+			configsMgmt.McConfigAdded(config)
+		case model.EventDelete:
+			log.Debugf("ClusterServiceExpositionPolicy resource was deleted. Name: %s", config.Name)
+			configsMgmt.McConfigDeleted(config)
+		case model.EventUpdate:
+			log.Debugf("ClusterServiceExpositionPolicy resource was updated. Name: %s", config.Name)
+			configsMgmt.McConfigModified(config)
+		}
+		log.Debugf("Config store now has %d ClusterServiceExpositionPolicy entries",
+			len(mcStore.ClusterServiceExpositionPolicies()))
+	})
+
 	// Register model configs event handler that will update the config store accordingly
 	// for RemoteServiceBinding resources
 	ctl.RegisterEventHandler(mcmodel.RemoteServiceBinding.Type, func(config model.Config, ev model.Event) {
@@ -156,11 +202,13 @@ func main() {
 		log.Debugf("Config store now has %d RemoteServiceBinding entries", len(mcStore.RemoteServiceBindings()))
 	})
 
-	if os.Getenv(mcmodel.IstioConversionStyleKey) == mcmodel.DirectIngressStyle {
+	switch cc.ConversionStyle {
+	case "", mcmodel.DirectIngressStyle:
 		log.Info("Using Direct Ingress Style")
-	} else {
-		// This style is not fully supported!
-		log.Warn("Using Egress/Ingress Style")
+	case mcmodel.EgressIngressStyle:
+		log.Info("Using Egress/Ingress Style")
+	default:
+		log.Infof("Using %s Style", cc.ConversionStyle)
 	}
 
 	// Set up a store wrapper for the Multi-Cluster controller
@@ -174,20 +222,36 @@ func main() {
 	log.Debug("Starting Istio controller..")
 	go istioStore.Run(stopCh)
 
+	log.Debug("Starting Multi-Cluster configs management..")
+	go configsMgmt.Run(stopCh)
+
 	log.Debug("Starting Multi-Cluster controller..")
 	go ctl.Run(stopCh)
 
-	log.Debugf("Starting agent listener on port %d..", clusterConfig.AgentPort)
-	server, err := agent.NewServer(clusterConfig, mcStore)
+	log.Debugf("Starting agent listener on port %d..", cc.AgentPort)
+	server, err := agent.NewServer(cc, mcStore, istioStore, configsMgmt.Services(),
+		configsMgmt.ClusterPolicies())
 	if err != nil {
 		log.Errora(err)
 		return
 	}
 	go server.Run()
 
-	log.Debugf("Starting agent clients. Number of peers: %d", len(clusterConfig.WatchedPeers))
+	var wh *webhook.Webhook
+	if webhookPort != 0 {
+		if _, err = os.Stat(webhookCert); err != nil {
+			log.Warnf("Not starting the validating webhook: %v", err)
+		} else {
+			log.Debugf("Starting validating webhook on port %d..", webhookPort)
+			wh = webhook.NewWebhook(uint16(webhookPort), webhookCert, webhookKey, currentClusterConfig{},
+				ctl, istioStore, configsMgmt.Services())
+			go wh.Run()
+		}
+	}
+
+	log.Debugf("Starting agent clients. Number of peers: %d", len(cc.WatchedPeers))
 	clientsCfgCh = map[string]chan agent.ClusterConfig{}
-	for _, peer := range clusterConfig.WatchedPeers {
+	for _, peer := range cc.WatchedPeers {
 		launchPeerClient(peer)
 	}
 
@@ -196,13 +260,42 @@ func main() {
 
 	close(stopCh)
 	server.Close()
+	if wh != nil {
+		wh.Close()
+	}
 	// configWatcher will be closed by defer'ed func above
 
 	_ = log.Sync()
 }
 
+// currentClusterConfig is a model.ClusterRegistry for the cluster
+// configuration, as reloaded when its file changes
+type currentClusterConfig struct{}
+
+func (currentClusterConfig) IsPeer(id string) bool {
+	return getClusterConfig().IsPeer(id)
+}
+
+func (currentClusterConfig) ConversionStyleName(config model.Config) (string, error) {
+	return getClusterConfig().ConversionStyleName(config)
+}
+
+// getClusterConfig returns the current cluster configuration
+func getClusterConfig() *agent.ClusterConfig {
+	clusterConfigMutex.RLock()
+	defer clusterConfigMutex.RUnlock()
+	return clusterConfig
+}
+
+// setClusterConfig replaces the current cluster configuration
+func setClusterConfig(cc *agent.ClusterConfig) {
+	clusterConfigMutex.Lock()
+	defer clusterConfigMutex.Unlock()
+	clusterConfig = cc
+}
+
 func launchPeerClient(peer agent.ClusterConfig) {
-	client, err := agent.NewClient(clusterConfig, &peer, &mcStore, istioStore)
+	client, err := agent.NewClient(getClusterConfig(), &peer, &mcStore, istioStore)
 	if err != nil {
 		log.Errorf("Failed to create an agent client to peer: %s", peer.ID)
 		return
@@ -224,6 +317,39 @@ func makeKubeConfigIstioController() (model.ConfigStoreCache, error) {
 	return ctl, nil
 }
 
+// loadConfig loads the cluster configuration. The deprecated MC_STYLE
+// environment variable sets the conversion style if the configuration does not.
+func loadConfig(file string) (*agent.ClusterConfig, error) {
+	cc, err := agent.LoadConfig(file)
+	if err != nil {
+		return nil, err
+	}
+	if style := os.Getenv(mcmodel.IstioConversionStyleKey); style != "" && cc.ConversionStyle == "" {
+		log.Warnf("The %s environment variable is deprecated, set ConversionStyle in the cluster configuration",
+			mcmodel.IstioConversionStyleKey)
+		if _, err = mcmodel.GetConversionStyle(style); err != nil {
+			return nil, err
+		}
+		cc.ConversionStyle = style
+	}
+	return cc, nil
+}
+
+// conversionWebhook returns the conversion webhook served by the agent
+// through its webhook Service, trusted with the CA certificate file
+func conversionWebhook(caFile string) (*mccrd.ConversionWebhook, error) {
+	caBundle, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	return &mccrd.ConversionWebhook{
+		Service:   webhookService,
+		Namespace: webhookNamespace,
+		Path:      webhook.ConvertPath,
+		CABundle:  caBundle,
+	}, nil
+}
+
 // launchConfigWatcher will launch a watcher to determine changes in the config
 // file and notify relevant objects about those changes
 func launchConfigWatcher(file string) *fsnotify.Watcher {
@@ -236,19 +362,19 @@ func launchConfigWatcher(file string) *fsnotify.Watcher {
 	onFileModified := func() {
 		//Config file modified
 		log.Debug("Config file modified. Reloading.")
-		newClusterConfig, lderr := agent.LoadConfig(file)
+		newClusterConfig, lderr := loadConfig(file)
 		if lderr != nil {
 			log.Error("Failed to reload the config file")
 			return
 		}
 
-		clusterConfig = newClusterConfig
+		setClusterConfig(newClusterConfig)
 
 		handled := map[string]bool{}
-		for _, peer := range clusterConfig.WatchedPeers {
+		for _, peer := range newClusterConfig.WatchedPeers {
 			// Update client with updated configuration
 			if clientsCfgCh[peer.ID] != nil {
-				clientsCfgCh[peer.ID] <- *clusterConfig
+				clientsCfgCh[peer.ID] <- *newClusterConfig
 			} else {
 				//This is a new peer. Launch a new client for it
 				launchPeerClient(peer)
@@ -305,4 +431,11 @@ func init() {
 	flag.StringVar(&kubeconfig, "kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
 	flag.StringVar(&context, "context", "", "Kubeconfig context to be used. Only required if out-of-cluster.")
 	flag.StringVar(&namespace, "namespace", "", "Namespace to watch. Default (or empty string) is all namespaces.")
+	flag.UintVar(&webhookPort, "webhook-port", 0, "Port of the validating admission webhook for the Multi-Cluster configs. Disabled if 0.")
+	flag.StringVar(&webhookCert, "webhook-cert", "/etc/webhook/certs/cert.pem", "Certificate file of the validating webhook.")
+	flag.StringVar(&webhookKey, "webhook-key", "/etc/webhook/certs/key.pem", "Key file of the validating webhook.")
+	flag.BoolVar(&registerCRDs, "register-crds", false, "Create or upgrade the Multi-Cluster CRDs, then exit.")
+	flag.StringVar(&conversionCACert, "conversion-ca-cert", "", "CA certificate file of the webhook converting "+
+		"the Multi-Cluster configs between API versions. The CRDs registered by --register-crds serve v1alpha2 "+
+		"through the webhook if set, only the v1alpha1 storage version otherwise.")
 }
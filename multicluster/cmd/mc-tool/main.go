@@ -41,7 +41,7 @@ import (
 
 	// Importing the API messages so that when resource events are fired the
 	// resource will be parsed into a message object
-	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha1"
+	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha2"
 	clientsetscheme "k8s.io/client-go/kubernetes/scheme"
 )
 
@@ -64,8 +64,15 @@ var (
 	// genbinding should match an acceptor cluster; if it is set the tool generates as if a binding for gencluster is the input
 	genbinding string
 
-	// mcStyle defines the output as DIRECT_INGRESS or EGRESS_INGRESS
+	// mcStyle names the conversion style of the output, e.g. DIRECT_INGRESS or EGRESS_INGRESS.
+	// It overrides the style of the cluster configuration.
 	mcStyle string
+
+	// listPending lists the Remote Service Bindings of the input awaiting approval
+	listPending bool
+
+	// approve names the Remote Service Bindings of the input to approve, separated by ','
+	approve string
 )
 
 func main() {
@@ -74,13 +81,9 @@ func main() {
 }
 
 func tool() {
-	if mcStyle == "" {
-		// If the user did not specify --mc-style, and there is no MC_STYLE var, default to direct ingress style
-		if os.Getenv(mcmodel.IstioConversionStyleKey) == "" {
-			os.Setenv(mcmodel.IstioConversionStyleKey, mcmodel.DirectIngressStyle) // nolint: errcheck
-		}
-	} else {
-		os.Setenv(mcmodel.IstioConversionStyleKey, mcStyle) // nolint: errcheck
+	if listPending || approve != "" {
+		approvalTool()
+		return
 	}
 
 	if filename == "" || cmFilename == "" {
@@ -101,6 +104,16 @@ func tool() {
 		os.Exit(2)
 	}
 
+	styleName := ci.ConversionStyle
+	if mcStyle != "" {
+		styleName = mcStyle
+	}
+	style, err := mcmodel.GetConversionStyle(styleName)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(2)
+	}
+
 	var store istiomodel.ConfigStore
 	if baselineFilename != "" {
 		store, err = createConfigStoreFromFile(baselineFilename)
@@ -116,9 +129,9 @@ func tool() {
 	svcStore := []kube_v1.Service{}
 
 	if gengo {
-		err = convertToGo(ci, store, svcStore, in, os.Stdout)
+		err = convertToGo(style, ci, store, svcStore, in, os.Stdout)
 	} else {
-		err = readAndConvert(ci, store, svcStore, in, os.Stdout)
+		err = readAndConvert(style, ci, store, svcStore, in, os.Stdout)
 	}
 
 	if err != nil {
@@ -132,26 +145,132 @@ func init() {
 	flag.StringVar(&cmFilename, "mc-conf-filename", "", "Path to YAML file containing Multicluster ConfigMap")
 	flag.StringVar(&baselineFilename, "initial-conf-filename", "", "Path to YAML file containing baseline Istio configuration")
 	flag.StringVar(&clusters, "cluster", "", "DEPRECATED; e.g. cluster=host:port[,cluster2=host2:port2]")
-	flag.StringVar(&mcStyle, "mc-style", "", "Generation style: DIRECT_INGRESS|EGRESS_INGRESS")
+	flag.StringVar(&mcStyle, "mc-style", "", "Generation style: "+strings.Join(mcmodel.ConversionStyles(), "|"))
 	flag.BoolVar(&gengo, "gengo", false, "Generate Go code instead of YAML (for generating Go tests)")
 	flag.StringVar(&genbinding, "genbinding", "", "Generate Remote Service Binding for cluster")
+	flag.BoolVar(&listPending, "list-pending", false, "List the Remote Service Bindings awaiting approval")
+	flag.StringVar(&approve, "approve", "", "Output the named Remote Service Bindings approved, e.g. approve=name[,name2]")
+}
+
+// approvalTool lists or approves the Remote Service Bindings of the input
+// awaiting approval
+func approvalTool() {
+	if filename == "" {
+		fmt.Printf("usage: mc-tool --filename <filename> --list-pending|--approve <name>[,<name2>]\n")
+		os.Exit(1)
+	}
+
+	in, err := os.Open(filename)
+	if err != nil {
+		fmt.Printf("could not open %q: %v\n", filename, err)
+		os.Exit(2)
+	}
+	defer in.Close() // nolint: errcheck
+
+	if listPending {
+		err = listPendingBindings(in, os.Stdout)
+	} else {
+		err = approveBindings(strings.Split(approve, ","), in, os.Stdout)
+	}
+
+	if err != nil {
+		fmt.Printf("Error %v\n", err)
+		os.Exit(3)
+	}
+}
+
+// listPendingBindings writes a line for each Remote Service Binding of the
+// input awaiting approval, with the services it binds from each cluster
+func listPendingBindings(reader io.Reader, writer io.Writer) error {
+	configs, err := readConfigs(reader)
+	if err != nil {
+		return err
+	}
+
+	for _, config := range configs {
+		rsb, ok := config.Spec.(*v1alpha2.RemoteServiceBinding)
+		if !ok || mcmodel.BindingApproved(config) {
+			continue
+		}
+		remotes := make([]string, 0, len(rsb.Remote))
+		for _, remote := range rsb.Remote {
+			services := make([]string, 0, len(remote.Services))
+			for _, svc := range remote.Services {
+				services = append(services, svc.Name)
+			}
+			remotes = append(remotes, fmt.Sprintf("%s: %s", remote.Cluster, strings.Join(services, ",")))
+		}
+		namespace := config.Namespace
+		if namespace == "" {
+			namespace = kube_v1.NamespaceDefault
+		}
+		fmt.Fprintf(writer, "%s/%s\t%s\t%s\n", namespace, config.Name, // nolint: errcheck
+			config.Annotations[mcmodel.ApprovalAnnotationKey], strings.Join(remotes, "; "))
+	}
+	return nil
+}
+
+// approveBindings writes the named Remote Service Bindings of the input as
+// approved, for applying them to the cluster
+func approveBindings(names []string, reader io.Reader, writer io.Writer) error {
+	configs, err := readConfigs(reader)
+	if err != nil {
+		return err
+	}
+
+	approved := make([]istiomodel.Config, 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, config := range configs {
+			if config.Type != mcmodel.RemoteServiceBinding.Type || config.Name != name {
+				continue
+			}
+			annotations := make(map[string]string, len(config.Annotations)+1)
+			for k, v := range config.Annotations {
+				annotations[k] = v
+			}
+			annotations[mcmodel.ApprovalAnnotationKey] = mcmodel.ApprovalApproved
+			config.Annotations = annotations
+			approved = append(approved, config)
+			found = true
+		}
+		if !found {
+			return fmt.Errorf("no Remote Service Binding %q", name)
+		}
+	}
+
+	for i, config := range approved {
+		obj, err := mccrd.ConvertConfig(mcmodel.RemoteServiceBinding, config)
+		if err != nil {
+			return err
+		}
+		bytes, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			writer.Write([]byte("---\n")) // nolint: errcheck
+		}
+		writer.Write(bytes) // nolint: errcheck
+	}
+	return nil
 }
 
 // readAndConvert converts a .yaml file of ServiceExposurePolicy and RemoteServiceBinding to Istio config .yaml file
-func readAndConvert(cc agent.ClusterConfig, store istiomodel.ConfigStore, svcs []kube_v1.Service, reader io.Reader, writer io.Writer) error {
+func readAndConvert(style mcmodel.ConversionStyle, cc agent.ClusterConfig, store istiomodel.ConfigStore, svcs []kube_v1.Service, reader io.Reader, writer io.Writer) error {
 	configs, err := readConfigs(reader)
 	if err != nil {
 		return err
 	}
 
 	if genbinding != "" {
-		configs, err = sepToRsb(genbinding, cc.ID, configs)
+		configs, err = sepToRsb(genbinding, cc, configs, svcs)
 		if err != nil {
 			return err
 		}
 	}
 
-	istioConfig, k8sSvcs, err := mcmodel.ConvertBindingsAndExposures2(configs, cc, store, svcs)
+	istioConfig, k8sSvcs, err := mcmodel.ConvertBindingsAndExposures2(style, configs, cc, store, svcs, cc.ConversionOptions())
 	if err != nil {
 		return err
 	}
@@ -161,6 +280,8 @@ func readAndConvert(cc agent.ClusterConfig, store istiomodel.ConfigStore, svcs [
 		istiomodel.Gateway,
 		istiomodel.DestinationRule,
 		istiomodel.ServiceEntry,
+		istiomodel.ServiceRole,
+		istiomodel.ServiceRoleBinding,
 	}
 	err = writeIstioYAMLOutput(configDescriptor, istioConfig, writer)
 	if err != nil {
@@ -180,20 +301,20 @@ func readAndConvert(cc agent.ClusterConfig, store istiomodel.ConfigStore, svcs [
 
 // convertToGo converts a .yaml file of ServiceExposurePolicy and RemoteServiceBinding to
 // Istio config Go source code fragment.
-func convertToGo(cc agent.ClusterConfig, store istiomodel.ConfigStore, svcs []kube_v1.Service, reader io.Reader, writer io.Writer) error {
+func convertToGo(style mcmodel.ConversionStyle, cc agent.ClusterConfig, store istiomodel.ConfigStore, svcs []kube_v1.Service, reader io.Reader, writer io.Writer) error {
 	configs, err := readConfigs(reader)
 	if err != nil {
 		return err
 	}
 
 	if genbinding != "" {
-		configs, err = sepToRsb(genbinding, cc.ID, configs)
+		configs, err = sepToRsb(genbinding, cc, configs, svcs)
 		if err != nil {
 			return err
 		}
 	}
 
-	istioConfig, k8sSvcs, err := mcmodel.ConvertBindingsAndExposures2(configs, cc, store, svcs)
+	istioConfig, k8sSvcs, err := mcmodel.ConvertBindingsAndExposures2(style, configs, cc, store, svcs, cc.ConversionOptions())
 	if err != nil {
 		return err
 	}
@@ -370,21 +491,26 @@ func createConfigStore(configs []istiomodel.Config) (istiomodel.ConfigStore, err
 	return out, nil
 }
 
-// sepToRsb does the same work as agent.createRemoteServiceBinding()
-func sepToRsb(clientID string, serverID string, svcs []istiomodel.Config) ([]istiomodel.Config, error) {
+// sepToRsb binds the exposed services the way the agent's client does when a
+// peer exposes them. The services selected by labels are looked up in the K8s
+// Services 'k8sSvcs', and the clusters of the policies matched with the groups
+// of the server's configuration.
+func sepToRsb(clientID string, server agent.ClusterConfig, svcs []istiomodel.Config, k8sSvcs []kube_v1.Service) ([]istiomodel.Config, error) {
+	serverID := server.ID
 	out := make([]istiomodel.Config, 0)
 	for _, svc := range svcs {
-		sep, ok := svc.Spec.(*v1alpha1.ServiceExpositionPolicy)
+		sep, ok := svc.Spec.(*v1alpha2.ServiceExpositionPolicy)
 		if ok {
 			name := strings.ToLower(serverID) + "-services"
-			services := make([]*v1alpha1.RemoteServiceBinding_RemoteCluster_RemoteService, len(sep.Exposed))
-			for i, exposed := range sep.Exposed {
-				if len(exposed.Clusters) == 0 || contains(exposed.Clusters, clientID) {
-					services[i] = &v1alpha1.RemoteServiceBinding_RemoteCluster_RemoteService{
+			exposedSvcs := mcmodel.ExposedServices(sep, svc.Namespace, k8sSvcs)
+			services := make([]*v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService, len(exposedSvcs))
+			for i, exposed := range exposedSvcs {
+				if mcmodel.ExposedToCluster(exposed, clientID, server.ClusterGroups) {
+					services[i] = &v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService{
 						Name:      exposed.Name,
 						Alias:     exposed.Name,
 						Namespace: svc.Namespace,
-						Port:      exposed.Port,
+						Ports:     exposed.Ports,
 					}
 				}
 			}
@@ -397,9 +523,9 @@ func sepToRsb(clientID string, serverID string, svcs []istiomodel.Config) ([]ist
 					Name:    name,
 					// Namespace: ns,
 				},
-				Spec: &v1alpha1.RemoteServiceBinding{
-					Remote: []*v1alpha1.RemoteServiceBinding_RemoteCluster{
-						&v1alpha1.RemoteServiceBinding_RemoteCluster{
+				Spec: &v1alpha2.RemoteServiceBinding{
+					Remote: []*v1alpha2.RemoteServiceBinding_RemoteCluster{
+						&v1alpha2.RemoteServiceBinding_RemoteCluster{
 							Cluster:  serverID,
 							Services: services,
 						},
@@ -410,13 +536,3 @@ func sepToRsb(clientID string, serverID string, svcs []istiomodel.Config) ([]ist
 	}
 	return out, nil
 }
-
-func contains(a []string, s string) bool {
-	for _, candidate := range a {
-		if candidate == s {
-			return true
-		}
-	}
-
-	return false
-}
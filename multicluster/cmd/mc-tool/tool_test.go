@@ -27,6 +27,9 @@ func TestTool(t *testing.T) {
 		filename   string // Service Exposition Policy or Remote Service Binding
 		cmFilename string // Config of cluster
 		out        string // Filename of .golden file (to be used for comparison in future)
+
+		listPending bool   // List the bindings awaiting approval instead
+		approve     string // Approve the named bindings instead
 	}{
 		{filename: "reviews-binding.yaml",
 			cmFilename: "cluster1_cm.yaml",
@@ -34,6 +37,12 @@ func TestTool(t *testing.T) {
 		{filename: "reviews-exposure.yaml",
 			cmFilename: "cluster1_cm.yaml",
 			out:        "reviews-exposure.yaml"},
+		{filename: "bindings-pending-approval.yaml",
+			listPending: true,
+			out:         "bindings-pending-approval.txt"},
+		{filename: "bindings-pending-approval.yaml",
+			approve: "ratings",
+			out:     "bindings-pending-approval.yaml"},
 	}
 
 	for _, tc := range tt {
@@ -41,6 +50,12 @@ func TestTool(t *testing.T) {
 			// Set the globals the CLI tool uses
 			filename = "../../pkg/test/expose-binding/" + tc.filename
 			cmFilename = "../../pkg/test/mc-agent/" + tc.cmFilename
+			listPending = tc.listPending
+			approve = tc.approve
+			defer func() {
+				listPending = false
+				approve = ""
+			}()
 
 			outFilename := "../../pkg/test/cli-tool/" + tc.out
 			outFile, err := os.Create(outFilename)
@@ -0,0 +1,323 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"fmt"
+
+	multierror "github.com/hashicorp/go-multierror"
+
+	"github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/reconcile"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/log"
+
+	kube_v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// k8sServiceStore is the subset of the K8s Services client used for applying changes
+type k8sServiceStore interface {
+	Get(name string, options metav1.GetOptions) (*kube_v1.Service, error)
+	Create(*kube_v1.Service) (*kube_v1.Service, error)
+	Update(*kube_v1.Service) (*kube_v1.Service, error)
+	Delete(name string, options *metav1.DeleteOptions) error
+}
+
+type applyOp string
+
+const (
+	opCreate applyOp = "Created"
+	opUpdate applyOp = "Updated"
+	opDelete applyOp = "Deleted"
+)
+
+// istioTypeTiers orders Istio config types by dependency. Configs of a tier
+// may reference configs of lower tiers (e.g. a VirtualService routing to a
+// DestinationRule subset), so lower tiers are written first and removed last.
+// Types not listed have no dependencies and are treated as tier 0.
+var istioTypeTiers = map[string]int{
+	model.DestinationRule.Type:    0,
+	model.ServiceEntry.Type:       0,
+	model.ServiceRole.Type:        0,
+	model.Gateway.Type:            1,
+	model.ServiceRoleBinding.Type: 1,
+	model.VirtualService.Type:     2,
+}
+
+const maxIstioTier = 2
+
+// applyStep is a single write of either an Istio config or a K8s Service
+type applyStep struct {
+	op      applyOp
+	config  *model.Config
+	service *kube_v1.Service
+
+	// State of the resource before the write, nil if it did not exist
+	priorConfig  *model.Config
+	priorService *kube_v1.Service
+
+	// Resource version produced by the write, needed to undo updates
+	revision string
+
+	result StepResult
+	err    error
+}
+
+func (s *applyStep) String() string {
+	if s.service != nil {
+		return fmt.Sprintf("Service %s.%s", s.service.Name, s.service.Namespace)
+	}
+	return fmt.Sprintf("%s %s.%s", s.config.Type, s.config.Name, s.config.Namespace)
+}
+
+// status describes the step for the Multi-cluster config status
+func (s *applyStep) status() StepStatus {
+	out := StepStatus{
+		Operation: string(s.op),
+		Result:    s.result,
+	}
+	if s.service != nil {
+		out.Kind, out.Name, out.Namespace = "service", s.service.Name, s.service.Namespace
+	} else {
+		out.Kind, out.Name, out.Namespace = s.config.Type, s.config.Name, s.config.Namespace
+	}
+	if s.err != nil {
+		out.Error = s.err.Error()
+	}
+	return out
+}
+
+// planChanges orders the changes into stages. All the writes of a stage
+// depend only on the writes of previous stages. Creations and updates come
+// first (K8s Services, then Istio configs from the lowest tier up) so that
+// new configuration is in place before anything is removed. Deletions follow
+// in the reverse order.
+func planChanges(changes *reconcile.ConfigChanges) [][]*applyStep {
+	if changes == nil {
+		return nil
+	}
+	k8s := changes.Kubernetes
+	if k8s == nil {
+		k8s = &reconcile.KubernetesChanges{}
+	}
+
+	stages := make([][]*applyStep, 0)
+	stages = append(stages, append(serviceSteps(opUpdate, k8s.Modifications),
+		serviceSteps(opCreate, k8s.Additions)...))
+	for tier := 0; tier <= maxIstioTier; tier++ {
+		stages = append(stages, append(configSteps(opUpdate, changes.Modifications, tier),
+			configSteps(opCreate, changes.Additions, tier)...))
+	}
+	for tier := maxIstioTier; tier >= 0; tier-- {
+		stages = append(stages, configSteps(opDelete, changes.Deletions, tier))
+	}
+	stages = append(stages, serviceSteps(opDelete, k8s.Deletions))
+
+	return stages
+}
+
+func configSteps(op applyOp, configs []model.Config, tier int) []*applyStep {
+	steps := make([]*applyStep, 0)
+	for i := range configs {
+		if istioTypeTiers[configs[i].Type] == tier {
+			steps = append(steps, &applyStep{op: op, config: &configs[i]})
+		}
+	}
+	return steps
+}
+
+func serviceSteps(op applyOp, svcs []kube_v1.Service) []*applyStep {
+	steps := make([]*applyStep, 0)
+	for i := range svcs {
+		steps = append(steps, &applyStep{op: op, service: &svcs[i]})
+	}
+	return steps
+}
+
+// applyResult holds the outcome of every planned write
+type applyResult struct {
+	steps []*applyStep
+	phase ReconcilePhase
+}
+
+// status summarizes the result for the Multi-cluster config status
+func (r *applyResult) status() *ReconcileStatus {
+	out := &ReconcileStatus{
+		Phase: r.phase,
+		Steps: make([]StepStatus, 0, len(r.steps)),
+	}
+	for _, step := range r.steps {
+		out.Steps = append(out.Steps, step.status())
+	}
+	return out
+}
+
+// applyChanges writes the changes to Istio and K8s stage by stage. The prior
+// state of every resource is captured before it is written. If any write of a
+// stage fails the later stages, which may depend on it, are skipped and the
+// writes already done are rolled back. If the rollback fails as well the
+// result is ReconcilePartial, describing exactly what was left applied.
+func applyChanges(store model.ConfigStore, svcStore k8sServiceStore, changes *reconcile.ConfigChanges) (*applyResult, error) {
+	stages := planChanges(changes)
+	result := &applyResult{phase: ReconcileApplied}
+	for _, stage := range stages {
+		result.steps = append(result.steps, stage...)
+	}
+
+	var errs error
+	for i, stage := range stages {
+		for _, step := range stage {
+			if err := writeStep(store, svcStore, step); err != nil {
+				log.Warnf("\t%s [Error: %v]", step, err)
+				step.result, step.err = StepFailed, err
+				errs = multierror.Append(errs, fmt.Errorf("%s: %v", step, err))
+				continue
+			}
+			step.result = StepApplied
+			log.Debugf("\t%s [%s]", step, step.op)
+		}
+		if errs == nil {
+			continue
+		}
+
+		skipped := 0
+		for _, rest := range stages[i+1:] {
+			for _, step := range rest {
+				step.result = StepSkipped
+				skipped++
+			}
+		}
+		if skipped > 0 {
+			log.Warnf("\tSkipping %d dependent changes", skipped)
+		}
+
+		result.phase = ReconcileRolledBack
+		if rollbackErr := rollback(store, svcStore, result.steps); rollbackErr != nil {
+			result.phase = ReconcilePartial
+			errs = multierror.Append(errs, rollbackErr)
+		}
+		return result, errs
+	}
+	return result, nil
+}
+
+// writeStep captures the prior state of the resource and then writes it
+func writeStep(store model.ConfigStore, svcStore k8sServiceStore, step *applyStep) error {
+	if step.service != nil {
+		prior, err := svcStore.Get(step.service.Name, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		if err == nil {
+			step.priorService = prior
+		}
+
+		var svc *kube_v1.Service
+		switch step.op {
+		case opCreate:
+			svc, err = svcStore.Create(step.service)
+		case opUpdate:
+			update := *step.service
+			if update.ResourceVersion == "" && prior != nil {
+				update.ResourceVersion = prior.ResourceVersion
+			}
+			svc, err = svcStore.Update(&update)
+		case opDelete:
+			err = svcStore.Delete(step.service.Name, &metav1.DeleteOptions{})
+		}
+		if err == nil && svc != nil {
+			step.revision = svc.ResourceVersion
+		}
+		return err
+	}
+
+	prior, exists := store.Get(step.config.Type, step.config.Name, step.config.Namespace)
+	if exists {
+		step.priorConfig = prior
+	}
+
+	var err error
+	switch step.op {
+	case opCreate:
+		step.revision, err = store.Create(*step.config)
+	case opUpdate:
+		update := *step.config
+		if update.ResourceVersion == "" && prior != nil {
+			update.ResourceVersion = prior.ResourceVersion
+		}
+		step.revision, err = store.Update(update)
+	case opDelete:
+		err = store.Delete(step.config.Type, step.config.Name, step.config.Namespace)
+	}
+	return err
+}
+
+// rollback undoes the applied steps in reverse order, restoring the captured
+// prior state of each resource
+func rollback(store model.ConfigStore, svcStore k8sServiceStore, steps []*applyStep) error {
+	var errs error
+	for i := len(steps) - 1; i >= 0; i-- {
+		step := steps[i]
+		if step.result != StepApplied {
+			continue
+		}
+		if err := undoStep(store, svcStore, step); err != nil {
+			log.Warnf("\t%s [Rollback error: %v]", step, err)
+			step.result, step.err = StepRollbackFailed, err
+			errs = multierror.Append(errs, fmt.Errorf("rollback of %s: %v", step, err))
+			continue
+		}
+		log.Debugf("\t%s [Rolled back]", step)
+		step.result = StepRolledBack
+	}
+	return errs
+}
+
+func undoStep(store model.ConfigStore, svcStore k8sServiceStore, step *applyStep) error {
+	if step.service != nil {
+		switch {
+		case step.priorService == nil:
+			return svcStore.Delete(step.service.Name, &metav1.DeleteOptions{})
+		case step.op == opDelete:
+			restore := *step.priorService
+			restore.ResourceVersion = ""
+			restore.UID = ""
+			_, err := svcStore.Create(&restore)
+			return err
+		default:
+			restore := *step.priorService
+			restore.ResourceVersion = step.revision
+			_, err := svcStore.Update(&restore)
+			return err
+		}
+	}
+
+	switch {
+	case step.priorConfig == nil:
+		return store.Delete(step.config.Type, step.config.Name, step.config.Namespace)
+	case step.op == opDelete:
+		restore := *step.priorConfig
+		restore.ResourceVersion = ""
+		_, err := store.Create(restore)
+		return err
+	default:
+		restore := *step.priorConfig
+		restore.ResourceVersion = step.revision
+		_, err := store.Update(restore)
+		return err
+	}
+}
@@ -0,0 +1,338 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"istio.io/api/networking/v1alpha3"
+	"istio.io/istio/pilot/pkg/config/memory"
+	istiomodel "istio.io/istio/pilot/pkg/model"
+
+	kube_v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/reconcile"
+)
+
+// recordingStore records the writes to an in-memory Istio config store and
+// fails the writes listed in failures
+type recordingStore struct {
+	istiomodel.ConfigStore
+	writes   *[]string
+	failures map[string]bool
+}
+
+func (s recordingStore) record(op, name string) error {
+	write := fmt.Sprintf("%s %s", op, name)
+	if s.failures[write] {
+		return fmt.Errorf("injected failure")
+	}
+	*s.writes = append(*s.writes, write)
+	return nil
+}
+
+func (s recordingStore) Create(config istiomodel.Config) (string, error) {
+	if err := s.record("create", config.Name); err != nil {
+		return "", err
+	}
+	return s.ConfigStore.Create(config)
+}
+
+func (s recordingStore) Update(config istiomodel.Config) (string, error) {
+	if err := s.record("update", config.Name); err != nil {
+		return "", err
+	}
+	return s.ConfigStore.Update(config)
+}
+
+func (s recordingStore) Delete(typ, name, namespace string) error {
+	if err := s.record("delete", name); err != nil {
+		return err
+	}
+	return s.ConfigStore.Delete(typ, name, namespace)
+}
+
+// recordingServices records the writes of K8s Services to an in-memory map
+type recordingServices struct {
+	services map[string]kube_v1.Service
+	writes   *[]string
+}
+
+func (s recordingServices) Get(name string, options metav1.GetOptions) (*kube_v1.Service, error) {
+	svc, ok := s.services[name]
+	if !ok {
+		return nil, apierrors.NewNotFound(kube_v1.Resource("services"), name)
+	}
+	return &svc, nil
+}
+
+func (s recordingServices) Create(svc *kube_v1.Service) (*kube_v1.Service, error) {
+	*s.writes = append(*s.writes, "create svc-"+svc.Name)
+	s.services[svc.Name] = *svc
+	return svc, nil
+}
+
+func (s recordingServices) Update(svc *kube_v1.Service) (*kube_v1.Service, error) {
+	*s.writes = append(*s.writes, "update svc-"+svc.Name)
+	s.services[svc.Name] = *svc
+	return svc, nil
+}
+
+func (s recordingServices) Delete(name string, options *metav1.DeleteOptions) error {
+	*s.writes = append(*s.writes, "delete svc-"+name)
+	delete(s.services, name)
+	return nil
+}
+
+func testIstioConfig(schema istiomodel.ProtoSchema, name string) istiomodel.Config {
+	config := istiomodel.Config{
+		ConfigMeta: istiomodel.ConfigMeta{
+			Type:      schema.Type,
+			Group:     schema.Group + istiomodel.IstioAPIGroupDomain,
+			Version:   schema.Version,
+			Name:      name,
+			Namespace: "default",
+		},
+	}
+	switch schema.Type {
+	case istiomodel.VirtualService.Type:
+		config.Spec = &v1alpha3.VirtualService{
+			Hosts: []string{"foo.default.svc.cluster.local"},
+			Http: []*v1alpha3.HTTPRoute{{
+				Route: []*v1alpha3.DestinationWeight{{
+					Destination: &v1alpha3.Destination{Host: "foo.default.svc.cluster.local"},
+				}},
+			}},
+		}
+	case istiomodel.Gateway.Type:
+		config.Spec = &v1alpha3.Gateway{
+			Servers: []*v1alpha3.Server{{
+				Port:  &v1alpha3.Port{Number: 80, Protocol: "HTTP", Name: "http"},
+				Hosts: []string{"foo.default.svc.cluster.local"},
+			}},
+		}
+	case istiomodel.DestinationRule.Type:
+		config.Spec = &v1alpha3.DestinationRule{Host: "foo.default.svc.cluster.local"}
+	case istiomodel.ServiceEntry.Type:
+		config.Spec = &v1alpha3.ServiceEntry{
+			Hosts:      []string{"foo.default.svc.cluster.local"},
+			Ports:      []*v1alpha3.Port{{Number: 80, Protocol: "HTTP", Name: "http"}},
+			Resolution: v1alpha3.ServiceEntry_DNS,
+		}
+	}
+	return config
+}
+
+func testService(name string) kube_v1.Service {
+	return kube_v1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+}
+
+func TestApplyChangesOrder(t *testing.T) {
+	tt := []struct {
+		name     string
+		existing []istiomodel.Config
+		changes  *reconcile.ConfigChanges
+		failures map[string]bool
+		writes   []string
+		wantErr  bool
+	}{
+		{name: "create",
+			changes: &reconcile.ConfigChanges{
+				Additions: []istiomodel.Config{
+					testIstioConfig(istiomodel.VirtualService, "vs"),
+					testIstioConfig(istiomodel.Gateway, "gw"),
+					testIstioConfig(istiomodel.DestinationRule, "dr"),
+					testIstioConfig(istiomodel.ServiceEntry, "se"),
+				},
+				Kubernetes: &reconcile.KubernetesChanges{
+					Additions: []kube_v1.Service{testService("foo")},
+				},
+			},
+			writes: []string{"create svc-foo", "create dr", "create se", "create gw", "create vs"}},
+		{name: "delete",
+			existing: []istiomodel.Config{
+				testIstioConfig(istiomodel.DestinationRule, "dr"),
+				testIstioConfig(istiomodel.ServiceEntry, "se"),
+				testIstioConfig(istiomodel.VirtualService, "vs"),
+			},
+			changes: &reconcile.ConfigChanges{
+				Deletions: []istiomodel.Config{
+					testIstioConfig(istiomodel.ServiceEntry, "se"),
+					testIstioConfig(istiomodel.DestinationRule, "dr"),
+					testIstioConfig(istiomodel.VirtualService, "vs"),
+				},
+				Kubernetes: &reconcile.KubernetesChanges{
+					Deletions: []kube_v1.Service{testService("foo")},
+				},
+			},
+			writes: []string{"delete vs", "delete se", "delete dr", "delete svc-foo"}},
+		{name: "make before break",
+			existing: []istiomodel.Config{
+				testIstioConfig(istiomodel.VirtualService, "old-vs"),
+			},
+			changes: &reconcile.ConfigChanges{
+				Additions: []istiomodel.Config{
+					testIstioConfig(istiomodel.VirtualService, "new-vs"),
+				},
+				Deletions: []istiomodel.Config{
+					testIstioConfig(istiomodel.VirtualService, "old-vs"),
+				},
+			},
+			writes: []string{"create new-vs", "delete old-vs"}},
+		{name: "dependency fails",
+			changes: &reconcile.ConfigChanges{
+				Additions: []istiomodel.Config{
+					testIstioConfig(istiomodel.VirtualService, "vs"),
+					testIstioConfig(istiomodel.DestinationRule, "dr"),
+					testIstioConfig(istiomodel.ServiceEntry, "se"),
+				},
+			},
+			failures: map[string]bool{"create dr": true},
+			writes:   []string{"create se", "delete se"},
+			wantErr:  true},
+		{name: "dependent not deleted",
+			existing: []istiomodel.Config{
+				testIstioConfig(istiomodel.DestinationRule, "dr"),
+				testIstioConfig(istiomodel.VirtualService, "vs"),
+			},
+			changes: &reconcile.ConfigChanges{
+				Deletions: []istiomodel.Config{
+					testIstioConfig(istiomodel.DestinationRule, "dr"),
+					testIstioConfig(istiomodel.VirtualService, "vs"),
+				},
+				Kubernetes: &reconcile.KubernetesChanges{
+					Deletions: []kube_v1.Service{testService("foo")},
+				},
+			},
+			failures: map[string]bool{"delete vs": true},
+			writes:   []string{},
+			wantErr:  true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			mem := memory.Make(istiomodel.IstioConfigTypes)
+			for _, config := range tc.existing {
+				if _, err := mem.Create(config); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			writes := []string{}
+			store := recordingStore{ConfigStore: mem, writes: &writes, failures: tc.failures}
+			svcs := recordingServices{services: map[string]kube_v1.Service{}, writes: &writes}
+			_, err := applyChanges(store, svcs, tc.changes)
+			if tc.wantErr && err == nil {
+				t.Error("expected an error")
+			}
+			if !tc.wantErr && err != nil {
+				t.Error(err)
+			}
+			if !reflect.DeepEqual(writes, tc.writes) {
+				t.Errorf("got writes %v, expected %v", writes, tc.writes)
+			}
+		})
+	}
+}
+
+func TestApplyChangesRollback(t *testing.T) {
+	mem := memory.Make(istiomodel.IstioConfigTypes)
+	for _, config := range []istiomodel.Config{
+		testIstioConfig(istiomodel.DestinationRule, "dr"),
+		testIstioConfig(istiomodel.Gateway, "old-gw"),
+	} {
+		if _, err := mem.Create(config); err != nil {
+			t.Fatal(err)
+		}
+	}
+	priorDR, _ := mem.Get(istiomodel.DestinationRule.Type, "dr", "default")
+
+	newDR := testIstioConfig(istiomodel.DestinationRule, "dr")
+	newDR.Spec = &v1alpha3.DestinationRule{Host: "bar.default.svc.cluster.local"}
+	changes := &reconcile.ConfigChanges{
+		Additions: []istiomodel.Config{
+			testIstioConfig(istiomodel.ServiceEntry, "se"),
+			testIstioConfig(istiomodel.VirtualService, "vs"),
+		},
+		Modifications: []istiomodel.Config{newDR},
+		Deletions: []istiomodel.Config{
+			testIstioConfig(istiomodel.Gateway, "old-gw"),
+		},
+		Kubernetes: &reconcile.KubernetesChanges{
+			Additions: []kube_v1.Service{testService("foo")},
+		},
+	}
+
+	writes := []string{}
+	store := recordingStore{ConfigStore: mem, writes: &writes, failures: map[string]bool{"create vs": true}}
+	svcs := recordingServices{services: map[string]kube_v1.Service{}, writes: &writes}
+	result, err := applyChanges(store, svcs, changes)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	expectedWrites := []string{
+		"create svc-foo", "update dr", "create se",
+		"delete se", "update dr", "delete svc-foo",
+	}
+	if !reflect.DeepEqual(writes, expectedWrites) {
+		t.Errorf("got writes %v, expected %v", writes, expectedWrites)
+	}
+
+	status := result.status()
+	if status.Phase != ReconcileRolledBack {
+		t.Errorf("got phase %q, expected %q", status.Phase, ReconcileRolledBack)
+	}
+	results := map[string]StepResult{}
+	for _, step := range status.Steps {
+		results[step.Name] = step.Result
+	}
+	expectedResults := map[string]StepResult{
+		"foo":    StepRolledBack,
+		"dr":     StepRolledBack,
+		"se":     StepRolledBack,
+		"vs":     StepFailed,
+		"old-gw": StepSkipped,
+	}
+	if !reflect.DeepEqual(results, expectedResults) {
+		t.Errorf("got step results %v, expected %v", results, expectedResults)
+	}
+
+	dr, _ := mem.Get(istiomodel.DestinationRule.Type, "dr", "default")
+	if !reflect.DeepEqual(dr.Spec, priorDR.Spec) {
+		t.Errorf("DestinationRule not restored: %v", dr.Spec)
+	}
+	if _, exists := mem.Get(istiomodel.Gateway.Type, "old-gw", "default"); !exists {
+		t.Error("Gateway deleted although its deletion was skipped")
+	}
+	if len(svcs.services) != 0 {
+		t.Errorf("K8s Services not restored: %v", svcs.services)
+	}
+
+	// A failing rollback leaves a partial state
+	writes = []string{}
+	store.failures = map[string]bool{"create vs": true, "delete se": true}
+	result, err = applyChanges(store, svcs, changes)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if result.phase != ReconcilePartial {
+		t.Errorf("got phase %q, expected %q", result.phase, ReconcilePartial)
+	}
+}
@@ -7,7 +7,8 @@ import (
 	"strings"
 	"time"
 
-	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha1"
+	"github.com/gogo/protobuf/proto"
+	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha2"
 	mcmodel "github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/model"
 	"istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pkg/log"
@@ -15,6 +16,10 @@ import (
 
 const (
 	pollInterval = 5 * time.Second
+
+	// pendingBindingSuffix names the RemoteServiceBinding of the services of
+	// a peer awaiting approval after the binding of the peer
+	pendingBindingSuffix = "-pending"
 )
 
 // Client is an agent client meant to connect to an agent server on a peered
@@ -88,6 +93,14 @@ func (c *Client) update() {
 		c.connected = true
 	}
 
+	c.exposuresUpdated(exposed)
+}
+
+// exposuresUpdated updates the RemoteServiceBindings of the peer when the
+// services it exposes changed. For a peer whose bindings need approval, the
+// new services and those exposed differently are bound by a pending binding
+// of their own, and the binding of the peer keeps the approved ones.
+func (c *Client) exposuresUpdated(exposed *ExposedServices) {
 	// Get the connection mode for the peer. Can either be live or potential.
 	// In live mode the Istio Configs will be created and deleted.
 	connMode := c.peer.ConnectionMode
@@ -95,72 +108,82 @@ func (c *Client) update() {
 		connMode = ConnectionModeLive
 	}
 
-	// If returned query response is 0 exposed services it can either be that
-	// all exposed services were removed or there weren't any in the first
-	// place.
-	// if len(exposed.Services) == 0 {
-	// 	// If all previously exposed services were removed we cleanup all
-	// 	// related Istio configs and the relevant RemoteServiceBinding
-	// 	if rsb := c.remoteServiceBinding(); rsb != nil {
-	// 		log.Debugf("Peer [%s] removed all exposed services", c.peer.ID)
-
-	// 		// Peer removed all exposed services therefore no need for the RSB
-	// 		c.store.Delete(rsb.Type, rsb.Name, rsb.Namespace)
-	// 		if err != nil {
-	// 			log.Errora(err)
-	// 		}
-	// 		log.Debugf("RemoteServiceBinding for cluser [%s] deleted", c.peer.ID)
-
-	// 		// Delete Istio configs if in live mode
-	// 		if connMode == ConnectionModeLive {
-	// 			c.reconcile(nil, rsb)
-	// 		}
-	// 	}
-	// 	return
-	// }
-
-	// TODO: currently just checking if something has changed by comparing the number of services.
-	// This needs to be revisited to compare existing and incoming services and figure what are
-	// the added, updated and deleted services.
-	if !c.needsUpdate(exposed) {
-		// Nothing changed on peered cluster since last check
+	var services []*v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService
+	ns := ""
+	if exposed != nil && len(exposed.Services) > 0 {
+		services = remoteServices(exposed)
+		ns = exposed.Services[len(exposed.Services)-1].Namespace
+	}
+	name := c.bindingName()
+	if c.peer.Approval != ApprovalManual {
+		c.bindingUpdated(name, ns, services, "", connMode)
+		c.bindingUpdated(name+pendingBindingSuffix, ns, nil, "", connMode)
 		return
 	}
 
-	// TODO: Below is an unefficient implementation to handle update which first delete the RSB
-	// and its Istio configs and then create new ones with the updated info.
-
-	oldRsb := c.remoteServiceBinding()
-	if oldRsb != nil {
-		c.store.Delete(mcmodel.RemoteServiceBinding.Type, oldRsb.Name, oldRsb.Namespace)
-		log.Debug("Old RemoteServiceBinding deleted for the exposed remote service(s)")
-	}
+	// The approved services are bound first, so that none is unbound while
+	// the pending binding approved by an operator is folded into the binding
+	approved, pending := c.approvalSplit(services)
+	c.bindingUpdated(name, ns, approved, mcmodel.ApprovalApproved, connMode)
+	c.bindingUpdated(name+pendingBindingSuffix, ns, pending, mcmodel.ApprovalPending, connMode)
+}
 
-	newRsb := c.createRemoteServiceBinding(exposed, connMode)
-	if newRsb != nil {
-		// Add it to the config store
-		c.store.Create(*newRsb)
-		log.Debug("RemoteServiceBinding created for the exposed remote service(s)")
+// bindingUpdated creates, updates or deletes the named RemoteServiceBinding
+// of the peer for it to bind the services, in the approval phase if not
+// empty. The binding is left untouched if it already binds them.
+func (c *Client) bindingUpdated(name, ns string,
+	services []*v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService, phase, connMode string) {
+	oldRsb := c.remoteServiceBinding(name)
+	if len(services) == 0 {
+		if oldRsb != nil {
+			if err := c.store.Delete(mcmodel.RemoteServiceBinding.Type, oldRsb.Name, oldRsb.Namespace); err != nil {
+				log.Errora(err)
+				return
+			}
+			log.Debugf("RemoteServiceBinding %s.%s deleted", oldRsb.Namespace, oldRsb.Name)
+		}
+		return
 	}
-}
 
-// Create a RemoteServiceBinding object for the exposed services
-func (c *Client) createRemoteServiceBinding(exposed *ExposedServices, connectionMode string) *model.Config {
-	if exposed == nil || len(exposed.Services) == 0 {
-		return nil
+	newRsb := c.newRemoteServiceBinding(name, ns, services, connMode)
+	if phase != "" {
+		newRsb.Annotations = map[string]string{mcmodel.ApprovalAnnotationKey: phase}
 	}
-	services := make([]*v1alpha1.RemoteServiceBinding_RemoteCluster_RemoteService, len(exposed.Services))
-	ns := ""
-	for i, service := range exposed.Services {
-		services[i] = &v1alpha1.RemoteServiceBinding_RemoteCluster_RemoteService{
-			Name:      service.Name,
-			Alias:     service.Name,
-			Namespace: service.Namespace,
-			Port:      service.Port,
+	var err error
+	if oldRsb != nil && oldRsb.Namespace == ns {
+		if oldRsb.Annotations[mcmodel.ApprovalAnnotationKey] == phase && oldRsb.Labels[ConnectionModeKey] == connMode &&
+			equalServices(c.peerServices(oldRsb), services) {
+			// Nothing changed on peered cluster since last check
+			return
 		}
-		ns = service.Namespace
+		// Updated rather than recreated, for the services it still binds
+		// to stay realized
+		newRsb.ResourceVersion = oldRsb.ResourceVersion
+		_, err = c.store.Update(*newRsb)
+	} else {
+		if oldRsb != nil {
+			if err = c.store.Delete(mcmodel.RemoteServiceBinding.Type, oldRsb.Name, oldRsb.Namespace); err != nil {
+				log.Errora(err)
+				return
+			}
+		}
+		_, err = c.store.Create(*newRsb)
+	}
+	if err != nil {
+		log.Errora(err)
+		return
 	}
-	name := strings.ToLower(c.peer.ID) + "-services"
+	if phase == mcmodel.ApprovalPending {
+		log.Infof("RemoteServiceBinding %s.%s for peer [%s] is pending approval", newRsb.Namespace, newRsb.Name, c.peer.ID)
+	} else {
+		log.Debugf("RemoteServiceBinding %s.%s updated for the exposed remote service(s)", newRsb.Namespace, newRsb.Name)
+	}
+}
+
+// newRemoteServiceBinding creates the named RemoteServiceBinding object
+// binding the services of the peer
+func (c *Client) newRemoteServiceBinding(name, ns string,
+	services []*v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService, connectionMode string) *model.Config {
 	return &model.Config{
 		ConfigMeta: model.ConfigMeta{
 			Type:      mcmodel.RemoteServiceBinding.Type,
@@ -172,9 +195,9 @@ func (c *Client) createRemoteServiceBinding(exposed *ExposedServices, connection
 				ConnectionModeKey: connectionMode,
 			},
 		},
-		Spec: &v1alpha1.RemoteServiceBinding{
-			Remote: []*v1alpha1.RemoteServiceBinding_RemoteCluster{
-				&v1alpha1.RemoteServiceBinding_RemoteCluster{
+		Spec: &v1alpha2.RemoteServiceBinding{
+			Remote: []*v1alpha2.RemoteServiceBinding_RemoteCluster{
+				&v1alpha2.RemoteServiceBinding_RemoteCluster{
 					Cluster:  c.peer.ID,
 					Services: services,
 				},
@@ -183,6 +206,83 @@ func (c *Client) createRemoteServiceBinding(exposed *ExposedServices, connection
 	}
 }
 
+// bindingName returns the name of the RemoteServiceBinding of the services
+// of the peer
+func (c *Client) bindingName() string {
+	return strings.ToLower(c.peer.ID) + "-services"
+}
+
+// approvalSplit splits the services exposed by a peer whose bindings need
+// approval into the services to bind and those awaiting approval. The
+// approved services are those of the binding of the peer, and of its
+// pending binding once an operator approved it. A service is bound as it was
+// approved; if it is new, or exposed differently, it awaits approval while
+// its approved version, if any, stays bound.
+func (c *Client) approvalSplit(services []*v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService) (
+	approved, pending []*v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService) {
+	approvedServices := make(map[string]*v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService)
+	for _, name := range []string{c.bindingName(), c.bindingName() + pendingBindingSuffix} {
+		if rsb := c.remoteServiceBinding(name); rsb != nil && mcmodel.BindingApproved(*rsb) {
+			for _, svc := range c.peerServices(rsb) {
+				approvedServices[svc.Namespace+"/"+svc.Name] = svc
+			}
+		}
+	}
+	for _, svc := range services {
+		was, ok := approvedServices[svc.Namespace+"/"+svc.Name]
+		if ok {
+			approved = append(approved, was)
+		}
+		if !ok || !proto.Equal(was, svc) {
+			pending = append(pending, svc)
+		}
+	}
+	return approved, pending
+}
+
+// peerServices returns the services the binding binds from the peer
+func (c *Client) peerServices(rsb *model.Config) []*v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService {
+	var services []*v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService
+	spec, _ := rsb.Spec.(*v1alpha2.RemoteServiceBinding)
+	for _, remote := range spec.Remote {
+		if remote.Cluster == c.peer.ID {
+			services = append(services, remote.Services...)
+		}
+	}
+	return services
+}
+
+func equalServices(a, b []*v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !proto.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// The RemoteServiceBinding entries binding the exposed services
+func remoteServices(exposed *ExposedServices) []*v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService {
+	services := make([]*v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService, len(exposed.Services))
+	for i, service := range exposed.Services {
+		ports := service.Ports
+		if len(ports) == 0 {
+			ports = v1alpha2.PortsFromV1alpha1(service.Port)
+		}
+		services[i] = &v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService{
+			Name:      service.Name,
+			Alias:     service.Name,
+			Namespace: service.Namespace,
+			Ports:     ports,
+			Policy:    service.Policy,
+		}
+	}
+	return services
+}
+
 // Function will call the peer of this client and fetch the current state of
 // exposed services.
 func (c *Client) callPeer() (*ExposedServices, error) {
@@ -216,32 +316,14 @@ func (c *Client) callPeer() (*ExposedServices, error) {
 	return exposed, nil
 }
 
-// Go through the provided list of exposed services and determine whether there
-// are any updates/changes from the current list. Organize the changes and
-// return those.
-func (c *Client) needsUpdate(exposed *ExposedServices) bool {
-	current := c.store.RemoteServiceBindings()
-	for _, rsb := range current {
-		spec, _ := rsb.Spec.(*v1alpha1.RemoteServiceBinding)
-		for _, remote := range spec.Remote {
-			if remote.Cluster == c.peer.ID { // found it
-				if len(remote.Services) != len(exposed.Services) {
-					return true
-				}
-				// TODO go through both lists and see if there are any differences
-				return false
-			}
-		}
-	}
-	return true
-}
-
-// Go through the RemoteServiceBindings in the store and find the one
-// relevant for the peered cluster.
-// TODO handle more than one RemoteServiceBinding for the cluster
-func (c *Client) remoteServiceBinding() *model.Config {
+// remoteServiceBinding returns the named RemoteServiceBinding of the peer
+// from the store, if any
+func (c *Client) remoteServiceBinding(name string) *model.Config {
 	for _, rsb := range c.store.RemoteServiceBindings() {
-		spec, _ := rsb.Spec.(*v1alpha1.RemoteServiceBinding)
+		if rsb.Name != name {
+			continue
+		}
+		spec, _ := rsb.Spec.(*v1alpha2.RemoteServiceBinding)
 		for _, remote := range spec.Remote {
 			if remote.Cluster == c.peer.ID { // found it
 				return &rsb
@@ -1,198 +1,425 @@
 package agent
 
 import (
+	"encoding/json"
+	"reflect"
+	"sync"
+
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha2"
+	mcmodel "github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/model"
 	"github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/reconcile"
 
+	"istio.io/api/networking/v1alpha3"
 	"istio.io/istio/pilot/pkg/model"
 	kubecfg "istio.io/istio/pkg/kube"
 	"istio.io/istio/pkg/log"
 
+	kube_v1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
-
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
 )
 
 // ConfigsManagement provides functions for handling changes in Multi-cluster
 // configs. Managing the life-cycle of MC configs by calling the functions here
 // will make sure all reconciled resources will also be handled accordingly.
+//
+// Changes are queued and reconciled by Run(). Failed reconciliations are
+// retried with backoff and changes for the same config are never reconciled
+// concurrently. The outcome of each reconciliation is reported on the MC
+// config with the StatusAnnotationKey annotation; the updates of a config that
+// only change that annotation are not reconciled.
+//
+// Existing K8s Services are read from a local cache kept up to date by an
+// informer rather than being listed from the API server for every change.
+// The K8s Endpoints are cached likewise; FALLBACK bindings are reconciled
+// again when the local Service they fall back from changes readiness. The
+// namespaces are cached as well, and the ClusterServiceExpositionPolicies are
+// reconciled again when the namespaces, Services or policies they depend on
+// change.
 type ConfigsManagement struct {
 	istioStore    model.ConfigStore
-	kubeconfig    string
-	context       string
+	mcStore       model.ConfigStore
+	clientset     kubernetes.Interface
+	services      *serviceCache
+	endpoints     *endpointsCache
+	namespaces    *namespaceCache
 	clusterConfig *ClusterConfig
+	vips          *vipAllocator
+	queue         *eventQueue
+
+	// seen holds the last version notified of each MC config, by event key,
+	// for telling the updates that only record a status
+	seenLock sync.Mutex
+	seen     map[string]model.Config
 }
 
-// NewConfigsManagement creates a new instance for the configs management
-func NewConfigsManagement(kubeconfig, context string, istioStore model.ConfigStore, clusterConfig *ClusterConfig) *ConfigsManagement {
-	return &ConfigsManagement{
+// NewConfigsManagement creates a new instance for the configs management. The
+// MC store is used for reporting the status of MC configs and may be nil.
+// Returns nil if a K8s client could not be created or the persisted VIP
+// allocations could not be loaded.
+func NewConfigsManagement(kubeconfig, context string, istioStore, mcStore model.ConfigStore, clusterConfig *ClusterConfig) *ConfigsManagement {
+	clientset, err := makeK8sClientset(kubeconfig, context)
+	if err != nil {
+		log.Errorf("Failed to create K8s client: %v", err)
+		return nil
+	}
+
+	cm := &ConfigsManagement{
 		istioStore:    istioStore,
-		kubeconfig:    kubeconfig,
-		context:       context,
+		mcStore:       mcStore,
+		clientset:     clientset,
 		clusterConfig: clusterConfig,
+		seen:          make(map[string]model.Config),
 	}
+	// The handlers only react to changes so the informer never needs a resync
+	cm.services = newServiceCache(serviceListWatch(clientset), 0, cm.localServicesChanged)
+	cm.endpoints = newEndpointsCache(endpointsListWatch(clientset), cm.localReadinessChanged)
+	cm.namespaces = newNamespaceCache(namespaceListWatch(clientset), 0, cm.namespacesChanged)
+	if clusterConfig.VIPs.CIDR != "" {
+		name := clusterConfig.VIPs.ConfigMap
+		if name == "" {
+			name = DefaultVIPConfigMap
+		}
+		namespace := clusterConfig.VIPs.Namespace
+		if namespace == "" {
+			namespace = clusterConfig.ConversionOptions().ControlPlaneNamespace
+		}
+		cm.vips, err = newVIPAllocator(clusterConfig.VIPs.CIDR, clientset.CoreV1().ConfigMaps(namespace), name)
+		if err != nil {
+			log.Errorf("Failed to set up VIP allocation: %v", err)
+			return nil
+		}
+	}
+	cm.queue = newEventQueue(cm.reconcile, retryInitialDelay, retryMaxDelay, maxEventRetries)
+	return cm
 }
 
-// McConfigAdded should be called when a a Multi-cluster config has been added
-func (cm *ConfigsManagement) McConfigAdded(config model.Config) {
-	nsClient, err := makeK8sServicesClient(cm.kubeconfig, cm.context, config.Namespace)
-	if err != nil {
-		log.Errorf("Failed to create k8s services client for NS %s: %v", config.Namespace, err)
+// Run processes the queued Multi-cluster config changes until the stop
+// channel is closed. Processing starts once the K8s Services, Endpoints and
+// namespaces caches are synced.
+func (cm *ConfigsManagement) Run(stopCh <-chan struct{}) {
+	go cm.services.Run(stopCh)
+	go cm.endpoints.Run(stopCh)
+	go cm.namespaces.Run(stopCh)
+	if !cache.WaitForCacheSync(stopCh, cm.services.HasSynced, cm.endpoints.HasSynced, cm.namespaces.HasSynced) {
+		log.Warn("Stopped before the K8s Services, Endpoints and namespaces caches were synced")
 		return
 	}
-	svcList, err := nsClient.List(metav1.ListOptions{})
-	if err != nil {
-		log.Errora(err)
-	}
-	reconciler := reconcile.NewReconciler(cm.istioStore, svcList.Items, cm.clusterConfig)
-	changes, err := reconciler.AddMulticlusterConfig(config)
-	if err != nil {
-		log.Errora(err)
-		return
+	cm.queue.Run(1, stopCh)
+}
+
+// Services returns the K8s Services of the cluster, as cached
+func (cm *ConfigsManagement) Services() reconcile.ServiceLister {
+	return cm.services
+}
+
+// ClusterPolicies resolves where the ClusterServiceExpositionPolicies apply,
+// against the cached namespaces and K8s Services
+func (cm *ConfigsManagement) ClusterPolicies() mcmodel.ClusterPolicyScope {
+	return &clusterPolicyScope{
+		mcStore:    cm.mcStore,
+		namespaces: cm.namespaces,
+		services:   cm.services,
 	}
-	storeIstioConfigs(cm.istioStore, changes)
-	storeK8sConfigs(changes.Kubernetes, nsClient)
+}
+
+// McConfigAdded should be called when a a Multi-cluster config has been added
+func (cm *ConfigsManagement) McConfigAdded(config model.Config) {
+	cm.changedBeyondStatus(config)
+	cm.queue.Push(mcEvent{config: config, event: model.EventAdd})
+	cm.exposuresChanged(config)
 }
 
 // McConfigDeleted should be called when a a Multi-cluster config has been deleted
 func (cm *ConfigsManagement) McConfigDeleted(config model.Config) {
-	nsClient, err := makeK8sServicesClient(cm.kubeconfig, cm.context, config.Namespace)
-	if err != nil {
-		log.Errorf("Failed to create k8s services client for NS %s: %v", config.Namespace, err)
+	cm.seenLock.Lock()
+	delete(cm.seen, eventKey(config))
+	cm.seenLock.Unlock()
+	cm.queue.Push(mcEvent{config: config, event: model.EventDelete})
+	cm.exposuresChanged(config)
+}
+
+// McConfigModified should be called when a a Multi-cluster config has been modified
+func (cm *ConfigsManagement) McConfigModified(config model.Config) {
+	if !cm.changedBeyondStatus(config) {
+		// Recording the status of the config needs no reconciliation
 		return
 	}
-	svcList, err := nsClient.List(metav1.ListOptions{})
+	cm.queue.Push(mcEvent{config: config, event: model.EventUpdate})
+	cm.exposuresChanged(config)
+}
+
+// changedBeyondStatus records the config as the last version notified and
+// returns false if it only differs from the previous one by its
+// StatusAnnotationKey annotation
+func (cm *ConfigsManagement) changedBeyondStatus(config model.Config) bool {
+	cm.seenLock.Lock()
+	defer cm.seenLock.Unlock()
+	key := eventKey(config)
+	previous, ok := cm.seen[key]
+	cm.seen[key] = config
+	return !ok || !proto.Equal(previous.Spec, config.Spec) || !reflect.DeepEqual(previous.Labels, config.Labels) ||
+		!reflect.DeepEqual(withoutStatus(previous.Annotations), withoutStatus(config.Annotations))
+}
+
+// withoutStatus returns the annotations but StatusAnnotationKey
+func withoutStatus(annotations map[string]string) map[string]string {
+	out := make(map[string]string, len(annotations))
+	for k, v := range annotations {
+		if k != StatusAnnotationKey {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// exposuresChanged queues the other cluster policies for reconciliation when
+// an exposition policy, which may take precedence over them, changed
+func (cm *ConfigsManagement) exposuresChanged(config model.Config) {
+	switch config.Type {
+	case mcmodel.ServiceExpositionPolicy.Type:
+		cm.requeueClusterPolicies("")
+	case mcmodel.ClusterServiceExpositionPolicy.Type:
+		cm.requeueClusterPolicies(config.Name)
+	}
+}
+
+// reconcile brings the Istio and K8s configs in line with a single
+// Multi-cluster config change. Returning an error will cause a retry.
+func (cm *ConfigsManagement) reconcile(ev mcEvent) error {
+	config := ev.config
+	style, err := cm.clusterConfig.ConversionStyleFor(config)
 	if err != nil {
-		log.Errora(err)
+		return err
+	}
+	opts := cm.clusterConfig.ConversionOptions()
+	var allocations *vipAllocations
+	if cm.vips != nil {
+		allocations = &vipAllocations{allocator: cm.vips}
+		opts.VIPs = allocations
+	}
+	opts.Readiness = cm.endpoints
+	opts.ClusterPolicies = cm.ClusterPolicies()
+	if ev.event != model.EventDelete {
+		cm.warnInvalid(config)
+	}
+	reconciler := reconcile.NewReconciler(cm.istioStore, cm.services, cm.clusterConfig, style, opts)
+
+	var changes *reconcile.ConfigChanges
+	switch ev.event {
+	case model.EventAdd:
+		changes, err = reconciler.AddMulticlusterConfig(config)
+	case model.EventUpdate:
+		changes, err = reconciler.ModifyMulticlusterConfig(config)
+	case model.EventDelete:
+		changes, err = reconciler.DeleteMulticlusterConfig(config)
 	}
-	reconciler := reconcile.NewReconciler(cm.istioStore, svcList.Items, cm.clusterConfig)
-	changes, err := reconciler.DeleteMulticlusterConfig(config)
 	if err != nil {
-		log.Errora(err)
-		return
+		cm.releaseVIPs(allocations.hosts(), nil)
+		return err
 	}
-	storeIstioConfigs(cm.istioStore, changes)
 
-	// Verify the K8s service is in the expected namespace (internal check)
-	for _, deletion := range changes.Deletions {
-		if deletion.Namespace != config.Namespace {
-			log.Warnf("\tCannot delete K8s service %s.%s (expected namespace %q)", deletion.Namespace, deletion.Name, config.Namespace)
+	if ev.event == model.EventDelete {
+		// Verify the K8s service is in the expected namespace (internal check)
+		for _, deletion := range changes.Deletions {
+			if deletion.Namespace != config.Namespace {
+				log.Warnf("\tCannot delete K8s service %s.%s (expected namespace %q)", deletion.Namespace, deletion.Name, config.Namespace)
+			}
 		}
 	}
 
-	storeK8sConfigs(changes.Kubernetes, nsClient)
+	nsClient := cm.clientset.CoreV1().Services(config.Namespace)
+	result, err := applyChanges(cm.istioStore, nsClient, changes)
+	if ev.event != model.EventDelete && len(result.steps) > 0 {
+		cm.updateStatus(config, result.status())
+	}
+	// The changes may have been rolled back, the VIPs are freed in any case
+	// once no ServiceEntry has their hosts
+	cm.releaseVIPs(allocations.hosts(), changes.Deletions)
+	return err
 }
 
-// McConfigModified should be called when a a Multi-cluster config has been modified
-func (cm *ConfigsManagement) McConfigModified(config model.Config) {
-	nsClient, err := makeK8sServicesClient(cm.kubeconfig, cm.context, config.Namespace)
-	if err != nil {
-		log.Errorf("Failed to create k8s services client for NS %s: %v", config.Namespace, err)
+// warnInvalid logs the semantic errors of a Multi-cluster config, found
+// against the other Multi-cluster configs and the state of the cluster. They
+// are not fatal: the config is realized as far as possible.
+func (cm *ConfigsManagement) warnInvalid(config model.Config) {
+	if cm.mcStore == nil {
 		return
 	}
-	svcList, err := nsClient.List(metav1.ListOptions{})
-	if err != nil {
-		log.Errora(err)
+	var mcs []model.Config
+	for _, typ := range mcmodel.MultiClusterConfigTypes.Types() {
+		configs, err := cm.mcStore.List(typ, kube_v1.NamespaceAll)
+		if err != nil {
+			log.Warnf("Could not list the Multi-cluster configs to validate %s.%s: %v", config.Name, config.Namespace, err)
+			return
+		}
+		mcs = append(mcs, configs...)
 	}
-	reconciler := reconcile.NewReconciler(cm.istioStore, svcList.Items, cm.clusterConfig)
-	changes, err := reconciler.ModifyMulticlusterConfig(config)
+	svcs, err := cm.services.List(kube_v1.NamespaceAll)
 	if err != nil {
-		log.Errora(err)
+		log.Warnf("Could not list the K8s Services to validate %s.%s: %v", config.Name, config.Namespace, err)
 		return
 	}
-	storeIstioConfigs(cm.istioStore, changes)
-	storeK8sConfigs(changes.Kubernetes, nsClient)
+	err = mcmodel.ValidateSemantics(mcs, cm.clusterConfig, cm.istioStore, svcs)
+	for _, fe := range mcmodel.FieldErrorsOf(err, config) {
+		log.Warnf("Invalid Multi-cluster config: %v", fe)
+	}
 }
 
-// StoreIstioConfigs updates the provided ConfigStore with the created, updated and deleted configs
-func storeIstioConfigs(store model.ConfigStore, changes *reconcile.ConfigChanges) {
-	if changes == nil {
+// localReadinessChanged queues the FALLBACK bindings of a local K8s Service
+// for reconciliation, so that they route to the remote clusters once it has
+// no ready endpoints and back to it when it has
+func (cm *ConfigsManagement) localReadinessChanged(namespace, name string) {
+	if cm.mcStore == nil {
 		return
 	}
-	if len(changes.Modifications) > 0 {
-		log.Debugf("Istio configs updated: %d", len(changes.Modifications))
-		for _, cfg := range changes.Modifications {
-			_, err := store.Update(cfg)
-			if err != nil {
-				log.Warnf("\tType:%s\tName: %s.%s [Error: %v]", cfg.Type, cfg.Name, cfg.Namespace, err)
-				continue
-			}
-			log.Debugf("\tType:%s\tName: %s.%s [Updated]", cfg.Type, cfg.Name, cfg.Namespace)
-		}
+	configs, err := cm.mcStore.List(mcmodel.RemoteServiceBinding.Type, kube_v1.NamespaceAll)
+	if err != nil {
+		log.Warnf("Could not list the bindings falling back from %s.%s: %v", name, namespace, err)
+		return
 	}
-	if len(changes.Additions) > 0 {
-		log.Debugf("Istio configs created: %d", len(changes.Additions))
-		for _, cfg := range changes.Additions {
-			_, err := store.Create(cfg)
-			if err != nil {
-				log.Warnf("\tType:%s\tName: %s.%s [Error: %v]", cfg.Type, cfg.Name, cfg.Namespace, err)
-				continue
-			}
-			log.Debugf("\tType:%s\tName: %s.%s [Created]", cfg.Type, cfg.Name, cfg.Namespace)
+	for _, config := range configs {
+		rsb, ok := config.Spec.(*v1alpha2.RemoteServiceBinding)
+		if ok && mcmodel.FallsBackFrom(rsb, namespace, name) {
+			log.Infof("Service %s.%s changed readiness, reconciling %s.%s", name, namespace, config.Name, config.Namespace)
+			cm.queue.Push(mcEvent{config: config, event: model.EventUpdate})
 		}
 	}
-	if len(changes.Deletions) > 0 {
-		log.Debugf("Istio configs deleted: %d", len(changes.Deletions))
-		for _, cfg := range changes.Deletions {
-			err := store.Delete(cfg.Type, cfg.Name, cfg.Namespace)
-			if err != nil {
-				log.Warnf("\tType:%s\tName: %s.%s [Error: %v]", cfg.Type, cfg.Name, cfg.Namespace, err)
-				continue
-			}
-			log.Debugf("\tType:%s\tName: %s.%s [Deleted]", cfg.Type, cfg.Name, cfg.Namespace)
+}
+
+// localServicesChanged queues the SEPs of a namespace that select K8s
+// Services by labels, and the cluster policies, for reconciliation, so that
+// they expose the Services as they are created and removed
+func (cm *ConfigsManagement) localServicesChanged(namespace string) {
+	if cm.mcStore == nil {
+		return
+	}
+	configs, err := cm.mcStore.List(mcmodel.ServiceExpositionPolicy.Type, namespace)
+	if err != nil {
+		log.Warnf("Could not list the policies of namespace %s: %v", namespace, err)
+		return
+	}
+	for _, config := range configs {
+		sep, ok := config.Spec.(*v1alpha2.ServiceExpositionPolicy)
+		if ok && mcmodel.SelectsServices(sep) {
+			log.Infof("Services of namespace %s changed, reconciling %s.%s", namespace, config.Name, config.Namespace)
+			cm.queue.Push(mcEvent{config: config, event: model.EventUpdate})
 		}
 	}
+	cm.requeueClusterPolicies("")
 }
 
-func storeK8sConfigs(changes *reconcile.KubernetesChanges, k8sSvcClient corev1.ServiceInterface) {
-	if changes == nil {
+// namespacesChanged queues the cluster policies for reconciliation, so that
+// they apply in the namespaces they select as those are created, removed and
+// labelled
+func (cm *ConfigsManagement) namespacesChanged() {
+	cm.requeueClusterPolicies("")
+}
+
+// requeueClusterPolicies queues the cluster policies but the one named
+// 'except' for reconciliation, so that they are resolved again against the
+// namespaces, K8s Services and exposition policies
+func (cm *ConfigsManagement) requeueClusterPolicies(except string) {
+	if cm.mcStore == nil {
 		return
 	}
-	if len(changes.Modifications) > 0 {
-		log.Debugf("Kubernetes services updated: %d", len(changes.Modifications))
-		for _, cfg := range changes.Modifications {
-			_, err := k8sSvcClient.Update(&cfg)
-			if err != nil {
-				log.Warnf("\tService Name: %s.%s [Error: %v]", cfg.Name, cfg.Namespace, err)
-				continue
-			}
-			log.Debugf("\tService Name: %s.%s [Updated]", cfg.Name, cfg.Namespace)
+	configs, err := cm.mcStore.List(mcmodel.ClusterServiceExpositionPolicy.Type, kube_v1.NamespaceAll)
+	if err != nil {
+		log.Warnf("Could not list the cluster policies: %v", err)
+		return
+	}
+	for _, config := range configs {
+		if config.Name != except {
+			cm.queue.Push(mcEvent{config: config, event: model.EventUpdate})
 		}
 	}
-	if len(changes.Additions) > 0 {
-		log.Debugf("Kubernetes services created: %d", len(changes.Additions))
-		for _, cfg := range changes.Additions {
-			_, err := k8sSvcClient.Create(&cfg)
-			if err != nil {
-				log.Warnf("\tService Name: %s.%s [Error: %v]", cfg.Name, cfg.Namespace, err)
-				continue
-			}
-			log.Debugf("\tService Name: %s.%s [Created]", cfg.Name, cfg.Namespace)
+}
+
+// releaseVIPs frees the VIPs of the hosts allocated during a reconciliation
+// and of the hosts of the deleted ServiceEntries that no ServiceEntry (e.g.
+// of a binding in another namespace) has. Failing to free a VIP is logged;
+// the VIP stays allocated to the host.
+func (cm *ConfigsManagement) releaseVIPs(allocated []string, deletions []model.Config) {
+	if cm.vips == nil {
+		return
+	}
+	hosts := allocated
+	for _, deletion := range deletions {
+		se, ok := deletion.Spec.(*v1alpha3.ServiceEntry)
+		if ok && len(se.Addresses) > 0 {
+			hosts = append(hosts, se.Hosts...)
 		}
 	}
-	if len(changes.Deletions) > 0 {
-		log.Debugf("Kubernetes services deleted: %d", len(changes.Deletions))
-		for _, cfg := range changes.Deletions {
-			err := k8sSvcClient.Delete(cfg.Name, &metav1.DeleteOptions{})
-			if err != nil {
-				log.Warnf("\tService Name: %s.%s [Error: %v]", cfg.Name, cfg.Namespace, err)
-				continue
-			}
-			log.Debugf("\tService Name: %s.%s [Deleted]", cfg.Name, cfg.Namespace)
+	if len(hosts) == 0 {
+		return
+	}
+	inUse, err := serviceEntryHosts(cm.istioStore)
+	if err != nil {
+		log.Warnf("Could not release VIPs: %v", err)
+		return
+	}
+	for _, host := range hosts {
+		if inUse[host] {
+			continue
+		}
+		if err := cm.vips.Release(host); err != nil {
+			log.Warnf("Could not release the VIP of %s: %v", host, err)
 		}
 	}
 }
 
-func makeK8sServicesClient(kubeconfig, context, namespace string) (corev1.ServiceInterface, error) {
-	config, err := kubecfg.BuildClientConfig(kubeconfig, context)
+// serviceEntryHosts returns the hosts of the ServiceEntries of every namespace
+func serviceEntryHosts(store model.ConfigStore) (map[string]bool, error) {
+	configs, err := store.List(model.ServiceEntry.Type, kube_v1.NamespaceAll)
 	if err != nil {
 		return nil, err
 	}
+	hosts := make(map[string]bool)
+	for _, config := range configs {
+		for _, host := range config.Spec.(*v1alpha3.ServiceEntry).Hosts {
+			hosts[host] = true
+		}
+	}
+	return hosts, nil
+}
+
+// updateStatus records the status on the MC config. Failing to record the
+// status is logged but is not a reconciliation failure.
+func (cm *ConfigsManagement) updateStatus(config model.Config, status *ReconcileStatus) {
+	if cm.mcStore == nil {
+		return
+	}
+	current, exists := cm.mcStore.Get(config.Type, config.Name, config.Namespace)
+	if !exists {
+		return
+	}
 
-	clientset, err := kubernetes.NewForConfig(config)
+	value, err := json.Marshal(status)
 	if err != nil {
-		return nil, err
+		log.Warnf("Could not encode status of %s %s.%s: %v", config.Type, config.Name, config.Namespace, err)
+		return
 	}
+	if current.Annotations[StatusAnnotationKey] == string(value) {
+		return
+	}
+	annotations := make(map[string]string, len(current.Annotations)+1)
+	for k, v := range current.Annotations {
+		annotations[k] = v
+	}
+	annotations[StatusAnnotationKey] = string(value)
+	current.Annotations = annotations
 
-	return clientset.CoreV1().Services(namespace), nil
+	if _, err = cm.mcStore.Update(*current); err != nil {
+		log.Warnf("Could not update status of %s %s.%s: %v", config.Type, config.Name, config.Namespace, err)
+	}
+}
+
+func makeK8sClientset(kubeconfig, context string) (kubernetes.Interface, error) {
+	config, err := kubecfg.BuildClientConfig(kubeconfig, context)
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(config)
 }
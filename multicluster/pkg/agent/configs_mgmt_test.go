@@ -0,0 +1,129 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"testing"
+	"time"
+
+	"istio.io/istio/pilot/pkg/config/memory"
+	"istio.io/istio/pilot/pkg/model"
+
+	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha2"
+	mcmodel "github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/model"
+)
+
+// The updates of a config that only record its status are not reconciled
+func TestStatusUpdatesNotReconciled(t *testing.T) {
+	cm := &ConfigsManagement{seen: make(map[string]model.Config)}
+	cm.queue = newEventQueue(func(ev mcEvent) error { return nil }, time.Millisecond, 10*time.Millisecond, 3)
+	// drain handles the queued event, if any, and returns it
+	drain := func() *mcEvent {
+		if cm.queue.Len() == 0 {
+			return nil
+		}
+		key, ev, _ := cm.queue.get()
+		cm.queue.done(key, ev, nil)
+		return &ev
+	}
+
+	config := model.Config{
+		ConfigMeta: model.ConfigMeta{
+			Type:      mcmodel.RemoteServiceBinding.Type,
+			Name:      "cluster-b-services",
+			Namespace: "default",
+		},
+		Spec: &v1alpha2.RemoteServiceBinding{
+			Remote: []*v1alpha2.RemoteServiceBinding_RemoteCluster{
+				{Cluster: "cluster-b", Services: []*v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService{{Name: "reviews"}}},
+			},
+		},
+	}
+	cm.McConfigAdded(config)
+	if ev := drain(); ev == nil || ev.event != model.EventAdd {
+		t.Fatalf("got %v, want the addition queued", ev)
+	}
+
+	withStatus := config
+	withStatus.Annotations = map[string]string{StatusAnnotationKey: `{"phase":"Applied","steps":[]}`}
+	cm.McConfigModified(withStatus)
+	if ev := drain(); ev != nil {
+		t.Errorf("got %v queued for a status update, want none", ev)
+	}
+
+	approved := withStatus
+	approved.Annotations = map[string]string{
+		StatusAnnotationKey:           `{"phase":"Applied","steps":[]}`,
+		mcmodel.ApprovalAnnotationKey: mcmodel.ApprovalApproved,
+	}
+	cm.McConfigModified(approved)
+	if ev := drain(); ev == nil || ev.event != model.EventUpdate {
+		t.Errorf("got %v, want the update of another annotation queued", ev)
+	}
+
+	modified := approved
+	modified.Spec = &v1alpha2.RemoteServiceBinding{
+		Remote: []*v1alpha2.RemoteServiceBinding_RemoteCluster{
+			{Cluster: "cluster-b", Services: []*v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService{{Name: "ratings"}}},
+		},
+	}
+	cm.McConfigModified(modified)
+	if ev := drain(); ev == nil || ev.event != model.EventUpdate {
+		t.Errorf("got %v, want the update of the spec queued", ev)
+	}
+}
+
+// A change of readiness of a local K8s Service requeues the FALLBACK bindings
+// of the Service, although the bindings themselves did not change
+func TestReadinessChangeRequeuesBindings(t *testing.T) {
+	binding := func(name string, mode v1alpha2.RemoteServiceBinding_Mode) model.Config {
+		return model.Config{
+			ConfigMeta: model.ConfigMeta{
+				Type:      mcmodel.RemoteServiceBinding.Type,
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: &v1alpha2.RemoteServiceBinding{
+				Remote: []*v1alpha2.RemoteServiceBinding_RemoteCluster{{
+					Cluster:  "cluster-b",
+					Services: []*v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService{{Name: "ratings", Namespace: "default"}},
+				}},
+				Mode: mode,
+			},
+		}
+	}
+	store := memory.Make(mcmodel.MultiClusterConfigTypes)
+	cm := &ConfigsManagement{seen: make(map[string]model.Config), mcStore: store}
+	cm.queue = newEventQueue(func(ev mcEvent) error { return nil }, time.Millisecond, 10*time.Millisecond, 3)
+	for _, config := range []model.Config{binding("fallback", v1alpha2.RemoteServiceBinding_FALLBACK),
+		binding("replace", v1alpha2.RemoteServiceBinding_REPLACE)} {
+		if _, err := store.Create(config); err != nil {
+			t.Fatal(err)
+		}
+		// The bindings were notified and reconciled
+		cm.McConfigAdded(config)
+		key, ev, _ := cm.queue.get()
+		cm.queue.done(key, ev, nil)
+	}
+
+	cm.localReadinessChanged("default", "ratings")
+	if cm.queue.Len() != 1 {
+		t.Fatalf("got %d events queued, want the FALLBACK binding", cm.queue.Len())
+	}
+	_, ev, _ := cm.queue.get()
+	if ev.event != model.EventUpdate || ev.config.Name != "fallback" {
+		t.Errorf("got %v queued, want the update of the FALLBACK binding", ev)
+	}
+}
@@ -15,11 +15,15 @@
 package agent
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"reflect"
 	"sort"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/ghodss/yaml"
@@ -32,8 +36,13 @@ import (
 
 	"istio.io/istio/pilot/test/util"
 
+	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha2"
 	"github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/config/kube/crd"
 	mcmodel "github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/model"
+	"github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/reconcile"
+
+	kube_v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // TestServiceToBinding tests agent.exposedServicesToBinding()
@@ -64,6 +73,16 @@ func TestServiceToBinding(t *testing.T) {
 				"cluster-d": "ratings-exposure.yaml",
 			},
 			out: "ratings-exposure-both-cd.yaml"},
+		{config: "cluster_a.yaml",
+			in: map[string]string{
+				"cluster-b": "reviews-exposure-route-policy.yaml",
+			},
+			out: "reviews-exposure-route-policy.yaml"},
+		{config: "cluster_a.yaml",
+			in: map[string]string{
+				"cluster-b": "reviews-exposure-multi-port.yaml",
+			},
+			out: "reviews-exposure-multi-port.yaml"},
 		// Commenting this one because no service is exposed therefore
 		// no RSB is generated
 		// {in: "ratings-exposure.yaml",
@@ -106,6 +125,401 @@ func TestServiceToBinding(t *testing.T) {
 	}
 }
 
+// The services exposed by peers which don't know of Ports are bound on their Port
+func TestRemoteServicesPorts(t *testing.T) {
+	grpc := &v1alpha2.ServicePort{Number: 9090, Name: "grpc", Protocol: "GRPC"}
+	tt := []struct {
+		exposed ExposedService
+		want    []*v1alpha2.ServicePort
+	}{
+		{exposed: ExposedService{Name: "reviews", Port: 9080},
+			want: []*v1alpha2.ServicePort{{Number: 9080}}},
+		{exposed: ExposedService{Name: "reviews", Port: 9080, Ports: []*v1alpha2.ServicePort{{Number: 9080}, grpc}},
+			want: []*v1alpha2.ServicePort{{Number: 9080}, grpc}},
+		{exposed: ExposedService{Name: "reviews"}},
+	}
+
+	for i, tc := range tt {
+		services := remoteServices(&ExposedServices{Services: []*ExposedService{&tc.exposed}})
+		if got := services[0].Ports; !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%d: got ports %v, want %v", i, got, tc.want)
+		}
+	}
+}
+
+// exposedOn returns the services the peer exposes, named with their port
+// such as "reviews:9080"
+func exposedOn(names ...string) *ExposedServices {
+	exposed := &ExposedServices{}
+	for _, name := range names {
+		parts := strings.Split(name, ":")
+		port, _ := strconv.Atoi(parts[1])
+		exposed.Services = append(exposed.Services, &ExposedService{Name: parts[0], Namespace: "default", Port: uint32(port)})
+	}
+	return exposed
+}
+
+// approveBinding approves the named binding as an operator would
+func approveBinding(t *testing.T, client *Client, name string) {
+	rsb := client.remoteServiceBinding(name)
+	if rsb == nil {
+		t.Fatalf("no binding %s to approve", name)
+	}
+	rsb.Annotations = map[string]string{mcmodel.ApprovalAnnotationKey: mcmodel.ApprovalApproved}
+	if _, err := client.store.Update(*rsb); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// The services of a peer needing approval are bound once approved. The new
+// services and those exposed differently await approval in a pending
+// binding, while the approved ones stay bound as they were approved.
+func TestBindingApproval(t *testing.T) {
+	type binding struct {
+		phase    string
+		services []string // bound services, named with their port
+	}
+	tt := []struct {
+		name     string
+		approval string
+		approve  bool     // approve the pending binding first
+		exposed  []string // services the peer exposes, named with their port
+		want     *binding
+		pending  *binding
+	}{
+		{name: "discovered", approval: ApprovalManual, exposed: []string{"reviews:9080"},
+			pending: &binding{mcmodel.ApprovalPending, []string{"reviews:9080"}}},
+		{name: "approved", approval: ApprovalManual, approve: true, exposed: []string{"reviews:9080"},
+			want: &binding{mcmodel.ApprovalApproved, []string{"reviews:9080"}}},
+		{name: "newly exposed", approval: ApprovalManual, exposed: []string{"reviews:9080", "ratings:9080"},
+			want:    &binding{mcmodel.ApprovalApproved, []string{"reviews:9080"}},
+			pending: &binding{mcmodel.ApprovalPending, []string{"ratings:9080"}}},
+		{name: "exposed differently", approval: ApprovalManual, exposed: []string{"reviews:9081", "ratings:9080"},
+			want:    &binding{mcmodel.ApprovalApproved, []string{"reviews:9080"}},
+			pending: &binding{mcmodel.ApprovalPending, []string{"reviews:9081", "ratings:9080"}}},
+		{name: "approved again", approval: ApprovalManual, approve: true, exposed: []string{"reviews:9081", "ratings:9080"},
+			want: &binding{mcmodel.ApprovalApproved, []string{"reviews:9081", "ratings:9080"}}},
+		{name: "no longer exposed", approval: ApprovalManual, exposed: []string{"ratings:9080"},
+			want: &binding{mcmodel.ApprovalApproved, []string{"ratings:9080"}}},
+		{name: "automatic", approval: ApprovalAutomatic, exposed: []string{"ratings:9080", "details:9080"},
+			want: &binding{"", []string{"ratings:9080", "details:9080"}}},
+		{name: "none exposed", approval: ApprovalAutomatic},
+	}
+
+	cs, err := createDebugMCConfigStore(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := mcmodel.MakeMCStore(cs)
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			peer := &ClusterConfig{ID: "cluster-b", Approval: tc.approval}
+			client, err := NewClient(&ClusterConfig{ID: "cluster-a", WatchedPeers: []ClusterConfig{*peer}}, peer, &store, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.approve {
+				approveBinding(t, client, "cluster-b-services-pending")
+			}
+			client.exposuresUpdated(exposedOn(tc.exposed...))
+
+			for name, want := range map[string]*binding{"cluster-b-services": tc.want, "cluster-b-services-pending": tc.pending} {
+				var got *binding
+				if rsb := client.remoteServiceBinding(name); rsb != nil {
+					got = &binding{phase: rsb.Annotations[mcmodel.ApprovalAnnotationKey]}
+					for _, svc := range client.peerServices(rsb) {
+						got.services = append(got.services, fmt.Sprintf("%s:%d", svc.Name, svc.Ports[0].Number))
+					}
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("got binding %s %v, want %v", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+// The configs realizing the approved services of a peer are kept when the
+// peer exposes more services, or exposes them differently, until approved
+func TestApprovedServicesKept(t *testing.T) {
+	tt := []struct {
+		name    string
+		exposed []string // services the peer exposes once reviews was approved
+	}{
+		{name: "newly exposed", exposed: []string{"reviews:9080", "ratings:9080"}},
+		{name: "exposed differently", exposed: []string{"reviews:9081"}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			cs, err := createDebugMCConfigStore(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			store := mcmodel.MakeMCStore(cs)
+			peer := &ClusterConfig{ID: "cluster-b", GatewayIP: "169.62.129.93", GatewayPort: 80, Approval: ApprovalManual}
+			cc := &ClusterConfig{ID: "cluster-a", WatchedPeers: []ClusterConfig{*peer}}
+			client, err := NewClient(cc, peer, &store, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			client.exposuresUpdated(exposedOn("reviews:9080"))
+			approveBinding(t, client, "cluster-b-services-pending")
+			client.exposuresUpdated(exposedOn("reviews:9080"))
+			rsb := client.remoteServiceBinding("cluster-b-services")
+			if rsb == nil {
+				t.Fatal("reviews was not bound once approved")
+			}
+
+			// realize returns the configs realizing the bindings of the store
+			realize := func() map[string]istiomodel.Config {
+				style, err := mcmodel.GetConversionStyle(mcmodel.DirectIngressStyle)
+				if err != nil {
+					t.Fatal(err)
+				}
+				configs, _, err := style.Convert(store.RemoteServiceBindings(), cc, memory.Make(istiomodel.IstioConfigTypes),
+					nil, cc.ConversionOptions())
+				if err != nil {
+					t.Fatal(err)
+				}
+				realized := make(map[string]istiomodel.Config)
+				for _, config := range configs {
+					realized[config.Type+" "+config.Name] = config
+				}
+				return realized
+			}
+			approved := realize()
+			if len(approved) == 0 {
+				t.Fatal("the approved binding realized no config")
+			}
+
+			client.exposuresUpdated(exposedOn(tc.exposed...))
+			if got := client.remoteServiceBinding("cluster-b-services"); got == nil || got.ResourceVersion != rsb.ResourceVersion {
+				t.Errorf("got binding %v, want the approved binding untouched", got)
+			}
+			if client.remoteServiceBinding("cluster-b-services-pending") == nil {
+				t.Error("no binding pending approval")
+			}
+			if realized := realize(); !reflect.DeepEqual(realized, approved) {
+				t.Errorf("got configs %v realized, want the configs of the approved binding %v", realized, approved)
+			}
+		})
+	}
+}
+
+// The services selected by labels are exposed as the K8s Services are
+// created and removed
+func TestExposedServicesSelector(t *testing.T) {
+	sep := istiomodel.Config{
+		ConfigMeta: istiomodel.ConfigMeta{Type: mcmodel.ServiceExpositionPolicy.Type, Name: "bookinfo", Namespace: "default"},
+		Spec: &v1alpha2.ServiceExpositionPolicy{
+			Exposed: []*v1alpha2.ServiceExpositionPolicy_ExposedService{
+				{Name: "productpage", Clusters: []string{"cluster-b"}},
+				{Alias: "bookinfo", Selector: map[string]string{"team": "bookinfo"}, Clusters: []string{"cluster-b"}},
+			},
+		},
+	}
+	cs, err := createDebugMCConfigStore([]istiomodel.Config{sep})
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := func(name string, labels map[string]string, ports ...kube_v1.ServicePort) kube_v1.Service {
+		return kube_v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+			Spec:       kube_v1.ServiceSpec{Ports: ports},
+		}
+	}
+	bookinfo := map[string]string{"team": "bookinfo"}
+
+	tt := []struct {
+		name string
+		svcs reconcile.ServiceList
+		want map[string][]*v1alpha2.ServicePort // exposed name to ports
+	}{
+		{name: "no services",
+			want: map[string][]*v1alpha2.ServicePort{"productpage": nil}},
+		{name: "selected services",
+			svcs: reconcile.ServiceList{
+				service("reviews", bookinfo, kube_v1.ServicePort{Name: "http", Port: 9080}),
+				service("ratings", bookinfo, kube_v1.ServicePort{Name: "grpc-api", Port: 9090},
+					kube_v1.ServicePort{Name: "metrics", Port: 15000}),
+				service("productpage", nil, kube_v1.ServicePort{Name: "http", Port: 9080}),
+			},
+			want: map[string][]*v1alpha2.ServicePort{
+				"productpage": nil,
+				"bookinfo-ratings": {{Number: 9090, Name: "grpc-api", Protocol: "GRPC"},
+					{Number: 15000, Name: "metrics", Protocol: "TCP"}},
+				"bookinfo-reviews": {{Number: 9080, Name: "http", Protocol: "HTTP"}},
+			}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			clusterConfig := &ClusterConfig{ID: "cluster-a", TrustedPeers: []string{"*"}}
+			server, err := NewServer(clusterConfig, mcmodel.MakeMCStore(cs), nil, tc.svcs, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make(map[string][]*v1alpha2.ServicePort)
+			for _, exposed := range server.exposedServices("cluster-b") {
+				got[exposed.Name] = exposed.Ports
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got exposed services %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// The clusters lists of the exposed services match cluster IDs, glob patterns
+// and the cluster groups of the configuration, less the excluded clusters
+func TestExposedServicesClusters(t *testing.T) {
+	sep := istiomodel.Config{
+		ConfigMeta: istiomodel.ConfigMeta{Type: mcmodel.ServiceExpositionPolicy.Type, Name: "bookinfo", Namespace: "default"},
+		Spec: &v1alpha2.ServiceExpositionPolicy{
+			Exposed: []*v1alpha2.ServiceExpositionPolicy_ExposedService{
+				{Name: "public"},
+				{Name: "listed", Clusters: []string{"dev-1", "prod-us-1"}},
+				{Name: "prod", Clusters: []string{"prod-*"}, ExcludedClusters: []string{"prod-eu-3"}},
+				{Name: "eu", Clusters: []string{"europe"}},
+				{Name: "not-eu", ExcludedClusters: []string{"europe"}},
+			},
+		},
+	}
+	cs, err := createDebugMCConfigStore([]istiomodel.Config{sep})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clusterConfig := &ClusterConfig{
+		ID:            "cluster-a",
+		TrustedPeers:  []string{"*"},
+		ClusterGroups: mcmodel.ClusterGroups{"europe": {"*-eu-*", "dev-2"}},
+	}
+	server, err := NewServer(clusterConfig, mcmodel.MakeMCStore(cs), nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tt := []struct {
+		cluster string
+		want    []string
+	}{
+		{cluster: "dev-1", want: []string{"public", "listed", "not-eu"}},
+		{cluster: "dev-2", want: []string{"public", "eu"}},
+		{cluster: "prod-us-1", want: []string{"public", "listed", "prod", "not-eu"}},
+		{cluster: "prod-eu-1", want: []string{"public", "prod", "eu"}},
+		{cluster: "prod-eu-3", want: []string{"public", "eu"}},
+		{cluster: "europe", want: []string{"public", "not-eu"}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.cluster, func(t *testing.T) {
+			got := make([]string, 0)
+			for _, exposed := range server.exposedServices(tc.cluster) {
+				got = append(got, exposed.Name)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got exposed services %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// namespaceList is a namespaceLister for a fixed list of namespaces
+type namespaceList []kube_v1.Namespace
+
+func (l namespaceList) List() ([]kube_v1.Namespace, error) {
+	return l, nil
+}
+
+// The cluster policies expose the services of the namespaces they select
+// that no exposition policy, or older cluster policy, exposes
+func TestExposedServicesClusterPolicy(t *testing.T) {
+	namespace := func(name, env string) kube_v1.Namespace {
+		return kube_v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"env": env}}}
+	}
+	namespaces := namespaceList{namespace("prod-a", "prod"), namespace("prod-b", "prod"), namespace("dev", "dev")}
+	public := map[string]string{"mc/public": "true"}
+	service := func(name, namespace string, labels map[string]string) kube_v1.Service {
+		return kube_v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+			Spec:       kube_v1.ServiceSpec{Ports: []kube_v1.ServicePort{{Name: "http", Port: 9080}}},
+		}
+	}
+	svcs := reconcile.ServiceList{
+		service("reviews", "prod-a", public),
+		service("details", "prod-a", nil),
+		service("ratings", "prod-b", public),
+		service("reviews", "dev", public),
+	}
+	csep := func(name string, created int64, selector map[string]string,
+		exposed ...*v1alpha2.ServiceExpositionPolicy_ExposedService) istiomodel.Config {
+		return istiomodel.Config{
+			ConfigMeta: istiomodel.ConfigMeta{Type: mcmodel.ClusterServiceExpositionPolicy.Type, Name: name,
+				CreationTimestamp: metav1.Unix(created, 0)},
+			Spec: &v1alpha2.ClusterServiceExpositionPolicy{NamespaceSelector: selector, Exposed: exposed},
+		}
+	}
+	publicProd := csep("public-prod", 1, map[string]string{"env": "prod"},
+		&v1alpha2.ServiceExpositionPolicy_ExposedService{Selector: public, Clusters: []string{"cluster-b"}})
+
+	tt := []struct {
+		name    string
+		configs []istiomodel.Config
+		want    []string // namespace/exposed name
+	}{
+		{name: "selected namespaces",
+			configs: []istiomodel.Config{publicProd},
+			want:    []string{"prod-a/reviews", "prod-b/ratings"}},
+		{name: "exposition policy takes precedence",
+			configs: []istiomodel.Config{publicProd, {
+				ConfigMeta: istiomodel.ConfigMeta{Type: mcmodel.ServiceExpositionPolicy.Type, Name: "reviews",
+					Namespace: "prod-a"},
+				Spec: &v1alpha2.ServiceExpositionPolicy{
+					Exposed: []*v1alpha2.ServiceExpositionPolicy_ExposedService{
+						{Name: "reviews", Alias: "reviews-a", Clusters: []string{"cluster-b"}},
+					},
+				},
+			}},
+			want: []string{"prod-a/reviews-a", "prod-b/ratings"}},
+		{name: "older cluster policy takes precedence",
+			configs: []istiomodel.Config{publicProd, csep("public", 2, nil,
+				&v1alpha2.ServiceExpositionPolicy_ExposedService{Alias: "mesh", Selector: public,
+					Clusters: []string{"cluster-b"}})},
+			want: []string{"dev/mesh-reviews", "prod-a/reviews", "prod-b/ratings"}},
+		{name: "named service",
+			configs: []istiomodel.Config{csep("details", 1, nil,
+				&v1alpha2.ServiceExpositionPolicy_ExposedService{Name: "details", Clusters: []string{"cluster-b"}})},
+			want: []string{"prod-a/details"}},
+		{name: "other cluster",
+			configs: []istiomodel.Config{csep("details", 1, nil,
+				&v1alpha2.ServiceExpositionPolicy_ExposedService{Name: "details", Clusters: []string{"cluster-c"}})}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			cs, err := createDebugMCConfigStore(tc.configs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			policies := &clusterPolicyScope{mcStore: cs, namespaces: namespaces, services: svcs}
+			clusterConfig := &ClusterConfig{ID: "cluster-a", TrustedPeers: []string{"*"}}
+			server, err := NewServer(clusterConfig, mcmodel.MakeMCStore(cs), nil, svcs, policies)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, exposed := range server.exposedServices("cluster-b") {
+				got = append(got, exposed.Namespace+"/"+exposed.Name)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got exposed services %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
 func sortedStringKeys(in map[string]string) []string {
 	out := make([]string, 0)
 	for key := range in {
@@ -122,7 +536,15 @@ func readAndConvert(reader io.Reader, writer io.Writer, clusterConfig *ClusterCo
 		return err
 	}
 
-	configs, err := readConfigs(reader)
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	configs, err := readConfigs(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	istioConfigs, _, err := istiocrd.ParseInputs(string(data))
 	if err != nil {
 		return err
 	}
@@ -133,7 +555,7 @@ func readAndConvert(reader io.Reader, writer io.Writer, clusterConfig *ClusterCo
 	}
 
 	store := mcmodel.MakeMCStore(cs)
-	server, err := NewServer(clusterConfig, store)
+	server, err := NewServer(clusterConfig, store, createDebugIstioConfigStore(istioConfigs), nil, nil)
 	if err != nil {
 		return err
 	}
@@ -146,13 +568,16 @@ func readAndConvert(reader io.Reader, writer io.Writer, clusterConfig *ClusterCo
 		return err
 	}
 
-	exposedSvcs := ExposedServices{Services: svcs}
-	binding := client.createRemoteServiceBinding(&exposedSvcs, ConnectionModeLive)
+	// The client stores the binding of the services it is told the peer exposes
+	client.exposuresUpdated(&ExposedServices{Services: svcs})
+	binding := client.remoteServiceBinding(client.bindingName())
 	if binding != nil {
 		if err = mcmodel.RemoteServiceBinding.Validate(binding.Name, binding.Namespace, binding.Spec); err != nil {
 			return multierror.Prefix(err, "validation error:")
 		}
 
+		// The version stamped by the store changes on every run
+		binding.ResourceVersion = ""
 		err = writeMCYAMLOutput(mcmodel.MultiClusterConfigTypes, []istiomodel.Config{*binding}, writer)
 		if err != nil {
 			return err
@@ -213,6 +638,16 @@ func readConfigs(reader io.Reader) ([]istiomodel.Config, error) {
 	return config, nil
 }
 
+// createDebugIstioConfigStore creates a memory store of Istio configs, the
+// VirtualServices the exposed services route policies are read from
+func createDebugIstioConfigStore(configs []istiomodel.Config) istiomodel.ConfigStore {
+	out := memory.Make(istiomodel.IstioConfigTypes)
+	for _, config := range configs {
+		out.Create(config) // nolint: errcheck
+	}
+	return out
+}
+
 func createDebugMCConfigStore(configs []istiomodel.Config) (istiomodel.ConfigStore, error) {
 	out := memory.Make(mcmodel.MultiClusterConfigTypes)
 	for _, config := range configs {
@@ -0,0 +1,140 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	kube_v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"istio.io/istio/pilot/pkg/model"
+
+	mcmodel "github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/model"
+	"github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/reconcile"
+)
+
+// namespaceCache keeps a local copy of the namespaces of the cluster, kept up
+// to date by a shared informer, for resolving the namespaces selected by the
+// ClusterServiceExpositionPolicies
+type namespaceCache struct {
+	informer cache.SharedIndexInformer
+}
+
+// newNamespaceCache creates a namespaces cache fed by the list-watcher.
+// onChange, if not nil, is called when a namespace is added or deleted or its
+// labels change, including for the namespaces listed initially.
+func newNamespaceCache(lw cache.ListerWatcher, resyncPeriod time.Duration, onChange func()) *namespaceCache {
+	c := &namespaceCache{
+		informer: cache.NewSharedIndexInformer(lw, &kube_v1.Namespace{}, resyncPeriod, cache.Indexers{}),
+	}
+	if onChange == nil {
+		return c
+	}
+	c.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) { onChange() },
+		UpdateFunc: func(old, cur interface{}) {
+			oldNs, oldOk := old.(*kube_v1.Namespace)
+			curNs, curOk := cur.(*kube_v1.Namespace)
+			if oldOk && curOk && reflect.DeepEqual(oldNs.Labels, curNs.Labels) {
+				return
+			}
+			onChange()
+		},
+		DeleteFunc: func(obj interface{}) { onChange() },
+	})
+	return c
+}
+
+// namespaceListWatch lists and watches the namespaces of the cluster
+func namespaceListWatch(client kubernetes.Interface) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			return client.CoreV1().Namespaces().List(opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			return client.CoreV1().Namespaces().Watch(opts)
+		},
+	}
+}
+
+// Run starts the informer and blocks until the stop channel is closed
+func (c *namespaceCache) Run(stop <-chan struct{}) {
+	c.informer.Run(stop)
+}
+
+// HasSynced returns true once the initial list of namespaces was loaded
+func (c *namespaceCache) HasSynced() bool {
+	return c.informer.HasSynced()
+}
+
+// List returns the cached namespaces. The returned namespaces are copies and
+// may be modified.
+func (c *namespaceCache) List() ([]kube_v1.Namespace, error) {
+	objs := c.informer.GetStore().List()
+	out := make([]kube_v1.Namespace, 0, len(objs))
+	for _, obj := range objs {
+		ns, ok := obj.(*kube_v1.Namespace)
+		if !ok {
+			return nil, fmt.Errorf("unexpected object %T in the namespaces cache", obj)
+		}
+		out = append(out, *ns.DeepCopy())
+	}
+	return out, nil
+}
+
+// namespaceLister lists the namespaces of the cluster, typically from an
+// informer cache
+type namespaceLister interface {
+	List() ([]kube_v1.Namespace, error)
+}
+
+// clusterPolicyScope resolves the ClusterServiceExpositionPolicies against
+// the Multi-cluster configs of the store and the listed namespaces and K8s
+// Services. It implements mcmodel.ClusterPolicyScope.
+type clusterPolicyScope struct {
+	mcStore    model.ConfigStore
+	namespaces namespaceLister
+	services   reconcile.ServiceLister
+}
+
+// NamespaceExposures returns the cluster policy as it applies in each
+// namespace. Without a Multi-cluster store no other policy takes precedence.
+func (s *clusterPolicyScope) NamespaceExposures(config model.Config) ([]model.Config, error) {
+	namespaces, err := s.namespaces.List()
+	if err != nil {
+		return nil, err
+	}
+	svcs, err := s.services.List(kube_v1.NamespaceAll)
+	if err != nil {
+		return nil, err
+	}
+	var seps, cseps []model.Config
+	if s.mcStore != nil {
+		if seps, err = s.mcStore.List(mcmodel.ServiceExpositionPolicy.Type, kube_v1.NamespaceAll); err != nil {
+			return nil, err
+		}
+		if cseps, err = s.mcStore.List(mcmodel.ClusterServiceExpositionPolicy.Type, kube_v1.NamespaceAll); err != nil {
+			return nil, err
+		}
+	}
+	return mcmodel.ResolveClusterPolicy(config, namespaces, seps, cseps, svcs), nil
+}
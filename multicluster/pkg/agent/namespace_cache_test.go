@@ -0,0 +1,106 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	kube_v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+// fakeNamespaceListWatch serves an initial list of namespaces and then the
+// events sent to its watcher
+type fakeNamespaceListWatch struct {
+	initial []kube_v1.Namespace
+	watcher *watch.FakeWatcher
+}
+
+func (lw *fakeNamespaceListWatch) List(options metav1.ListOptions) (runtime.Object, error) {
+	return &kube_v1.NamespaceList{ListMeta: metav1.ListMeta{ResourceVersion: "1"}, Items: lw.initial}, nil
+}
+
+func (lw *fakeNamespaceListWatch) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	return lw.watcher, nil
+}
+
+func labeledNamespace(name string, labels map[string]string) kube_v1.Namespace {
+	return kube_v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func TestNamespaceCacheChanges(t *testing.T) {
+	lw := &fakeNamespaceListWatch{
+		initial: []kube_v1.Namespace{
+			labeledNamespace("default", nil),
+			labeledNamespace("prod", map[string]string{"env": "prod"}),
+		},
+		watcher: watch.NewFake(),
+	}
+	var lock sync.Mutex
+	changes := 0
+	c := newNamespaceCache(lw, 0, func() {
+		lock.Lock()
+		defer lock.Unlock()
+		changes++
+	})
+	changed := func() int {
+		lock.Lock()
+		defer lock.Unlock()
+		return changes
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go c.Run(stop)
+	if !cache.WaitForCacheSync(stop, c.HasSynced) {
+		t.Fatal("cache did not sync")
+	}
+	waitFor(t, func() bool { return changed() == 2 })
+
+	// Only the namespaces added, deleted or labelled differently are reported
+	annotated := labeledNamespace("default", nil)
+	annotated.Annotations = map[string]string{"owner": "test"}
+	lw.watcher.Modify(&annotated)
+	relabeled := labeledNamespace("default", map[string]string{"env": "dev"})
+	lw.watcher.Modify(&relabeled)
+	deleted := labeledNamespace("prod", nil)
+	lw.watcher.Delete(&deleted)
+	waitFor(t, func() bool {
+		names := namespaceNames(t, c)
+		return len(names) == 1 && names[0] == "default:dev"
+	})
+	if got := changed(); got != 4 {
+		t.Errorf("got %d changes, want 4", got)
+	}
+}
+
+// namespaceNames returns the cached namespaces with their env label
+func namespaceNames(t *testing.T, c *namespaceCache) []string {
+	namespaces, err := c.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		names = append(names, ns.Name+":"+ns.Labels["env"])
+	}
+	sort.Strings(names)
+	return names
+}
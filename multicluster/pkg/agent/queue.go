@@ -0,0 +1,214 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/log"
+
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+const (
+	// Initial and maximal delay before a failed event is retried
+	retryInitialDelay = 1 * time.Second
+	retryMaxDelay     = 2 * time.Minute
+
+	// Number of times a failing event is retried before it is dropped
+	maxEventRetries = 10
+)
+
+// mcEvent is a Multi-cluster config change waiting to be reconciled
+type mcEvent struct {
+	config model.Config
+	event  model.Event
+}
+
+// eventHandler processes a single event. A returned error will cause the
+// event to be retried later.
+type eventHandler func(ev mcEvent) error
+
+// eventQueue is a keyed and rate-limited work queue for Multi-cluster config
+// events. Events are keyed by the type, namespace and name of the config.
+// Events pushed for a key that was not processed yet replace the waiting
+// event, a deletion followed by a creation becoming an update, and a key is
+// never handed to more than one worker at a time.
+// Failed events are retried with an exponential backoff per key.
+type eventQueue struct {
+	handler    eventHandler
+	backoff    *flowcontrol.Backoff
+	maxRetries int
+
+	lock       sync.Mutex
+	cond       *sync.Cond
+	keys       []string
+	pending    map[string]mcEvent
+	processing map[string]bool
+	retries    map[string]int
+	generation map[string]uint64
+	closing    bool
+}
+
+// newEventQueue creates a queue calling the handler for every event
+func newEventQueue(handler eventHandler, initialDelay, maxDelay time.Duration, maxRetries int) *eventQueue {
+	q := &eventQueue{
+		handler:    handler,
+		backoff:    flowcontrol.NewBackOff(initialDelay, maxDelay),
+		maxRetries: maxRetries,
+		pending:    make(map[string]mcEvent),
+		processing: make(map[string]bool),
+		retries:    make(map[string]int),
+		generation: make(map[string]uint64),
+	}
+	q.cond = sync.NewCond(&q.lock)
+	return q
+}
+
+// Push adds an event to the queue
+func (q *eventQueue) Push(ev mcEvent) {
+	key := eventKey(ev.config)
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	// A new event for the key supersedes any retry still waiting for it. The
+	// backoff is kept so that a key failing repeatedly keeps slowing down.
+	q.generation[key]++
+	q.add(key, ev)
+}
+
+// add marks the event as the one to process next for its key. Must be
+// called with the lock held.
+func (q *eventQueue) add(key string, ev mcEvent) {
+	if q.closing {
+		return
+	}
+	waiting, queued := q.pending[key]
+	if queued && waiting.event == model.EventDelete && ev.event == model.EventAdd {
+		// The config was recreated before its deletion was handled: what
+		// was realized for the deleted config is pruned as for an update
+		ev.event = model.EventUpdate
+	}
+	q.pending[key] = ev
+	if !queued && !q.processing[key] {
+		q.keys = append(q.keys, key)
+		q.cond.Signal()
+	}
+}
+
+// Run starts the workers and blocks until the stop channel is closed
+func (q *eventQueue) Run(workers int, stop <-chan struct{}) {
+	for i := 0; i < workers; i++ {
+		go q.work()
+	}
+	<-stop
+
+	q.lock.Lock()
+	q.closing = true
+	q.cond.Broadcast()
+	q.lock.Unlock()
+}
+
+func (q *eventQueue) work() {
+	for {
+		key, ev, ok := q.get()
+		if !ok {
+			return
+		}
+		q.done(key, ev, q.handler(ev))
+	}
+}
+
+// get blocks until an event is available or the queue is closing
+func (q *eventQueue) get() (string, mcEvent, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for len(q.keys) == 0 && !q.closing {
+		q.cond.Wait()
+	}
+	if q.closing {
+		return "", mcEvent{}, false
+	}
+
+	key := q.keys[0]
+	q.keys = q.keys[1:]
+	ev := q.pending[key]
+	delete(q.pending, key)
+	q.processing[key] = true
+	return key, ev, true
+}
+
+// done releases the key after the handler returned and schedules a retry if
+// the handler failed
+func (q *eventQueue) done(key string, ev mcEvent, err error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	delete(q.processing, key)
+
+	_, superseded := q.pending[key]
+	switch {
+	case err == nil:
+		q.backoff.Reset(key)
+		delete(q.retries, key)
+	case superseded:
+		log.Warnf("Failed to handle %s (a newer event is queued): %v", key, err)
+	case q.retries[key] >= q.maxRetries:
+		log.Errorf("Dropping %s after %d retries: %v", key, q.retries[key], err)
+		q.backoff.Reset(key)
+		delete(q.retries, key)
+	default:
+		q.retries[key]++
+		q.backoff.Next(key, q.backoff.Clock.Now())
+		delay := q.backoff.Get(key)
+		log.Warnf("Failed to handle %s, retrying in %v: %v", key, delay, err)
+		generation := q.generation[key]
+		time.AfterFunc(delay, func() {
+			q.retry(key, ev, generation)
+		})
+	}
+
+	if superseded {
+		q.keys = append(q.keys, key)
+		q.cond.Signal()
+	}
+}
+
+// retry puts a failed event back unless a newer event arrived meanwhile
+func (q *eventQueue) retry(key string, ev mcEvent, generation uint64) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if q.generation[key] != generation {
+		return
+	}
+	q.add(key, ev)
+}
+
+// Len returns the number of keys waiting to be processed
+func (q *eventQueue) Len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return len(q.pending)
+}
+
+func eventKey(config model.Config) string {
+	return fmt.Sprintf("%s/%s/%s", config.Type, config.Namespace, config.Name)
+}
@@ -0,0 +1,204 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"istio.io/istio/pilot/pkg/model"
+
+	mcmodel "github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/model"
+)
+
+func testEvent(name string, ev model.Event, version string) mcEvent {
+	return mcEvent{
+		config: model.Config{
+			ConfigMeta: model.ConfigMeta{
+				Type:            mcmodel.ServiceExpositionPolicy.Type,
+				Name:            name,
+				Namespace:       "default",
+				ResourceVersion: version,
+			},
+		},
+		event: ev,
+	}
+}
+
+// waitFor polls until cond() holds or fails the test
+func waitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the queue")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestEventQueueDeduplicates(t *testing.T) {
+	var lock sync.Mutex
+	handled := []string{}
+	q := newEventQueue(func(ev mcEvent) error {
+		lock.Lock()
+		defer lock.Unlock()
+		handled = append(handled, fmt.Sprintf("%s:%s", ev.config.Name, ev.config.ResourceVersion))
+		return nil
+	}, time.Millisecond, 10*time.Millisecond, 3)
+
+	q.Push(testEvent("a", model.EventAdd, "1"))
+	q.Push(testEvent("b", model.EventAdd, "1"))
+	q.Push(testEvent("a", model.EventUpdate, "2"))
+	q.Push(testEvent("a", model.EventUpdate, "3"))
+	if q.Len() != 2 {
+		t.Fatalf("expected 2 queued keys, got %d", q.Len())
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go q.Run(1, stop)
+
+	waitFor(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(handled) == 2
+	})
+	lock.Lock()
+	defer lock.Unlock()
+	if handled[0] != "a:3" || handled[1] != "b:1" {
+		t.Errorf("unexpected events handled: %v", handled)
+	}
+}
+
+// A config deleted and created again before the deletion was handled is
+// handled as an update, so that what is no longer desired gets pruned
+func TestEventQueueDeleteThenCreate(t *testing.T) {
+	var lock sync.Mutex
+	handled := []string{}
+	q := newEventQueue(func(ev mcEvent) error {
+		lock.Lock()
+		defer lock.Unlock()
+		handled = append(handled, fmt.Sprintf("%s:%s:%v", ev.config.Name, ev.config.ResourceVersion, ev.event))
+		return nil
+	}, time.Millisecond, 10*time.Millisecond, 3)
+
+	q.Push(testEvent("a", model.EventDelete, "1"))
+	q.Push(testEvent("a", model.EventAdd, "2"))
+	q.Push(testEvent("b", model.EventAdd, "1"))
+	q.Push(testEvent("b", model.EventDelete, "1"))
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go q.Run(1, stop)
+
+	waitFor(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(handled) == 2
+	})
+	lock.Lock()
+	defer lock.Unlock()
+	want := []string{
+		fmt.Sprintf("a:2:%v", model.EventUpdate),
+		fmt.Sprintf("b:1:%v", model.EventDelete),
+	}
+	if !reflect.DeepEqual(handled, want) {
+		t.Errorf("got events %v, want %v", handled, want)
+	}
+}
+
+func TestEventQueueRetries(t *testing.T) {
+	var lock sync.Mutex
+	calls := map[string]int{}
+	q := newEventQueue(func(ev mcEvent) error {
+		lock.Lock()
+		defer lock.Unlock()
+		calls[ev.config.Name]++
+		if ev.config.Name == "flaky" && calls["flaky"] < 3 {
+			return fmt.Errorf("transient failure")
+		}
+		if ev.config.Name == "broken" {
+			return fmt.Errorf("permanent failure")
+		}
+		return nil
+	}, time.Millisecond, 5*time.Millisecond, 2)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go q.Run(1, stop)
+
+	q.Push(testEvent("flaky", model.EventAdd, "1"))
+	q.Push(testEvent("broken", model.EventAdd, "1"))
+
+	waitFor(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return calls["flaky"] == 3 && calls["broken"] == 3
+	})
+
+	// Nothing else should be retried after the event was dropped
+	time.Sleep(50 * time.Millisecond)
+	lock.Lock()
+	defer lock.Unlock()
+	if calls["flaky"] != 3 || calls["broken"] != 3 {
+		t.Errorf("unexpected number of calls: %v", calls)
+	}
+}
+
+func TestEventQueueSerializesKey(t *testing.T) {
+	var lock sync.Mutex
+	active := map[string]bool{}
+	count := 0
+	overlap := false
+	q := newEventQueue(func(ev mcEvent) error {
+		lock.Lock()
+		if active[ev.config.Name] {
+			overlap = true
+		}
+		active[ev.config.Name] = true
+		lock.Unlock()
+
+		time.Sleep(2 * time.Millisecond)
+
+		lock.Lock()
+		defer lock.Unlock()
+		active[ev.config.Name] = false
+		count++
+		return nil
+	}, time.Millisecond, 5*time.Millisecond, 2)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go q.Run(4, stop)
+
+	for i := 0; i < 20; i++ {
+		q.Push(testEvent("same", model.EventUpdate, fmt.Sprintf("%d", i)))
+		time.Sleep(time.Millisecond)
+	}
+
+	waitFor(t, func() bool { return q.Len() == 0 })
+	time.Sleep(10 * time.Millisecond)
+	lock.Lock()
+	defer lock.Unlock()
+	if overlap {
+		t.Error("events for the same key were handled concurrently")
+	}
+	if count == 0 || count > 20 {
+		t.Errorf("unexpected number of handled events: %d", count)
+	}
+}
@@ -0,0 +1,107 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	kube_v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// endpointsCache keeps a local copy of the K8s Endpoints of all namespaces,
+// kept up to date by a shared informer. It implements model.ServiceReadiness
+// for FALLBACK bindings and reports the Services that gain their first ready
+// endpoint or lose their last one.
+type endpointsCache struct {
+	informer cache.SharedIndexInformer
+}
+
+// newEndpointsCache creates an Endpoints cache fed by the list-watcher.
+// onChange is called with the namespace and name of each Service whose
+// readiness changes, including the Services listed initially with ready
+// endpoints.
+func newEndpointsCache(lw cache.ListerWatcher, onChange func(namespace, name string)) *endpointsCache {
+	c := &endpointsCache{
+		informer: cache.NewSharedIndexInformer(lw, &kube_v1.Endpoints{}, 0, cache.Indexers{}),
+	}
+	notify := func(old, cur interface{}) {
+		if hasReadyAddresses(old) == hasReadyAddresses(cur) {
+			return
+		}
+		if ep := toEndpoints(cur); ep != nil {
+			onChange(ep.Namespace, ep.Name)
+		} else if ep = toEndpoints(old); ep != nil {
+			onChange(ep.Namespace, ep.Name)
+		}
+	}
+	c.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { notify(nil, obj) },
+		UpdateFunc: notify,
+		DeleteFunc: func(obj interface{}) { notify(obj, nil) },
+	})
+	return c
+}
+
+// endpointsListWatch lists and watches the Endpoints of all namespaces
+func endpointsListWatch(client kubernetes.Interface) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			return client.CoreV1().Endpoints(metav1.NamespaceAll).List(opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			return client.CoreV1().Endpoints(metav1.NamespaceAll).Watch(opts)
+		},
+	}
+}
+
+// Run starts the informer and blocks until the stop channel is closed
+func (c *endpointsCache) Run(stop <-chan struct{}) {
+	c.informer.Run(stop)
+}
+
+// HasSynced returns true once the initial list of Endpoints was loaded
+func (c *endpointsCache) HasSynced() bool {
+	return c.informer.HasSynced()
+}
+
+// Ready is implementing the model.ServiceReadiness interface
+func (c *endpointsCache) Ready(namespace, name string) bool {
+	obj, exists, err := c.informer.GetStore().GetByKey(namespace + "/" + name)
+	return err == nil && exists && hasReadyAddresses(obj)
+}
+
+func toEndpoints(obj interface{}) *kube_v1.Endpoints {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	ep, _ := obj.(*kube_v1.Endpoints)
+	return ep
+}
+
+func hasReadyAddresses(obj interface{}) bool {
+	ep := toEndpoints(obj)
+	if ep == nil {
+		return false
+	}
+	for _, subset := range ep.Subsets {
+		if len(subset.Addresses) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,266 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"sync"
+	"testing"
+
+	"istio.io/api/networking/v1alpha3"
+	"istio.io/istio/pilot/pkg/config/memory"
+	istiomodel "istio.io/istio/pilot/pkg/model"
+
+	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha2"
+	mcmodel "github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/model"
+
+	kube_v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+// fakeEndpointsListWatch serves an initial list of Endpoints and then the
+// events sent to its watcher
+type fakeEndpointsListWatch struct {
+	initial []kube_v1.Endpoints
+	watcher *watch.FakeWatcher
+}
+
+func (lw *fakeEndpointsListWatch) List(options metav1.ListOptions) (runtime.Object, error) {
+	return &kube_v1.EndpointsList{ListMeta: metav1.ListMeta{ResourceVersion: "1"}, Items: lw.initial}, nil
+}
+
+func (lw *fakeEndpointsListWatch) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	return lw.watcher, nil
+}
+
+func endpoints(name, namespace string, ready ...string) kube_v1.Endpoints {
+	ep := kube_v1.Endpoints{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	if len(ready) > 0 {
+		subset := kube_v1.EndpointSubset{}
+		for _, ip := range ready {
+			subset.Addresses = append(subset.Addresses, kube_v1.EndpointAddress{IP: ip})
+		}
+		ep.Subsets = []kube_v1.EndpointSubset{subset}
+	}
+	return ep
+}
+
+// staticReadiness is a model.ServiceReadiness with fixed answers
+type staticReadiness map[string]bool
+
+func (r staticReadiness) Ready(namespace, name string) bool {
+	return r[namespace+"/"+name]
+}
+
+func TestEndpointsCache(t *testing.T) {
+	lw := &fakeEndpointsListWatch{
+		initial: []kube_v1.Endpoints{
+			endpoints("ratings", "default", "10.0.0.1"),
+			endpoints("reviews", "default"),
+		},
+		watcher: watch.NewFake(),
+	}
+	var lock sync.Mutex
+	changes := make(map[string]int)
+	c := newEndpointsCache(lw, func(namespace, name string) {
+		lock.Lock()
+		defer lock.Unlock()
+		changes[namespace+"/"+name]++
+	})
+	changed := func(key string) int {
+		lock.Lock()
+		defer lock.Unlock()
+		return changes[key]
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go c.Run(stop)
+	if !cache.WaitForCacheSync(stop, c.HasSynced) {
+		t.Fatal("cache did not sync")
+	}
+
+	if !c.Ready("default", "ratings") {
+		t.Error("ratings should be ready")
+	}
+	if c.Ready("default", "reviews") || c.Ready("default", "details") {
+		t.Error("reviews and details should not be ready")
+	}
+	waitFor(t, func() bool { return changed("default/ratings") == 1 })
+
+	// Losing the last ready address is reported, gaining more is not
+	notReady := endpoints("ratings", "default")
+	lw.watcher.Modify(&notReady)
+	waitFor(t, func() bool { return changed("default/ratings") == 2 })
+	if c.Ready("default", "ratings") {
+		t.Error("ratings should no longer be ready")
+	}
+	ready := endpoints("reviews", "default", "10.0.0.2")
+	lw.watcher.Modify(&ready)
+	moreReady := endpoints("reviews", "default", "10.0.0.2", "10.0.0.3")
+	lw.watcher.Modify(&moreReady)
+	lw.watcher.Delete(&moreReady)
+	waitFor(t, func() bool { return changed("default/reviews") == 2 })
+	if c.Ready("default", "reviews") {
+		t.Error("deleted reviews should not be ready")
+	}
+}
+
+func TestFallbackConversion(t *testing.T) {
+	cc := ClusterConfig{
+		ID:           "cluster1",
+		WatchedPeers: []ClusterConfig{{ID: "cluster2", GatewayIP: "169.62.129.93", GatewayPort: 80}},
+	}
+	rsb := istiomodel.Config{
+		ConfigMeta: istiomodel.ConfigMeta{
+			Type:      mcmodel.RemoteServiceBinding.Type,
+			Name:      "reviews",
+			Namespace: "default",
+		},
+		Spec: &v1alpha2.RemoteServiceBinding{
+			Mode: v1alpha2.RemoteServiceBinding_FALLBACK,
+			Remote: []*v1alpha2.RemoteServiceBinding_RemoteCluster{{
+				Cluster:  "cluster2",
+				Services: []*v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService{{Name: "reviews", Ports: []*v1alpha2.ServicePort{{Number: 9080}}}},
+			}},
+		},
+	}
+	local := []kube_v1.Service{namespacedService("reviews", "default")}
+
+	tt := []struct {
+		ready bool
+		host  string // Host the requests to reviews are routed to
+	}{
+		{ready: true, host: "reviews.default.svc.cluster.local"},
+		{ready: false, host: "reviews.default.fallback"},
+	}
+	for _, tc := range tt {
+		opts := cc.ConversionOptions()
+		opts.Readiness = staticReadiness{"default/reviews": tc.ready}
+		configs, svcs, err := mcmodel.ConvertBindingsAndExposuresDirectIngress([]istiomodel.Config{rsb}, cc,
+			memory.Make(istiomodel.IstioConfigTypes), local, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(svcs) != 0 {
+			t.Errorf("The local Service should be kept, got %v", svcs)
+		}
+		var host string
+		for _, config := range configs {
+			if vs, ok := config.Spec.(*v1alpha3.VirtualService); ok {
+				host = vs.Http[0].Route[0].Destination.Host
+			}
+		}
+		if host != tc.host {
+			t.Errorf("Ready %t: routed to %q, want %q", tc.ready, host, tc.host)
+		}
+	}
+}
+
+// A local Service with a ready endpoint keeps the requests even though its
+// other endpoints fail; those are ejected by outlier detection on the local
+// host, unless a DestinationRule of the user already covers it
+func TestFallbackFailingEndpoints(t *testing.T) {
+	cc := ClusterConfig{
+		ID:           "cluster1",
+		WatchedPeers: []ClusterConfig{{ID: "cluster2", GatewayIP: "169.62.129.93", GatewayPort: 80}},
+	}
+	rsb := istiomodel.Config{
+		ConfigMeta: istiomodel.ConfigMeta{
+			Type:      mcmodel.RemoteServiceBinding.Type,
+			Name:      "reviews",
+			Namespace: "default",
+		},
+		Spec: &v1alpha2.RemoteServiceBinding{
+			Mode: v1alpha2.RemoteServiceBinding_FALLBACK,
+			Remote: []*v1alpha2.RemoteServiceBinding_RemoteCluster{{
+				Cluster:  "cluster2",
+				Services: []*v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService{{Name: "reviews", Ports: []*v1alpha2.ServicePort{{Number: 9080}}}},
+			}},
+		},
+	}
+	local := []kube_v1.Service{namespacedService("reviews", "default")}
+
+	// One endpoint is ready, the two others fail their readiness probe
+	failing := endpoints("reviews", "default", "10.0.0.1")
+	failing.Subsets[0].NotReadyAddresses = []kube_v1.EndpointAddress{{IP: "10.0.0.2"}, {IP: "10.0.0.3"}}
+	lw := &fakeEndpointsListWatch{initial: []kube_v1.Endpoints{failing}, watcher: watch.NewFake()}
+	c := newEndpointsCache(lw, func(namespace, name string) {})
+	stop := make(chan struct{})
+	defer close(stop)
+	go c.Run(stop)
+	if !cache.WaitForCacheSync(stop, c.HasSynced) {
+		t.Fatal("cache did not sync")
+	}
+
+	userRule := istiomodel.Config{
+		ConfigMeta: istiomodel.ConfigMeta{
+			Type:      istiomodel.DestinationRule.Type,
+			Group:     istiomodel.DestinationRule.Group + istiomodel.IstioAPIGroupDomain,
+			Version:   istiomodel.DestinationRule.Version,
+			Name:      "reviews",
+			Namespace: "default",
+		},
+		Spec: &v1alpha3.DestinationRule{Host: "reviews"},
+	}
+
+	tt := []struct {
+		name      string
+		store     []istiomodel.Config
+		wantLocal bool // whether a rule is generated for the local host
+	}{
+		{name: "without rule", wantLocal: true},
+		{name: "with a rule of the user", store: []istiomodel.Config{userRule}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			store := memory.Make(istiomodel.IstioConfigTypes)
+			for _, config := range tc.store {
+				if _, err := store.Create(config); err != nil {
+					t.Fatal(err)
+				}
+			}
+			opts := cc.ConversionOptions()
+			opts.Readiness = c
+			configs, _, err := mcmodel.ConvertBindingsAndExposuresDirectIngress([]istiomodel.Config{rsb}, cc, store, local, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var host string
+			var rule *v1alpha3.DestinationRule
+			for _, config := range configs {
+				switch spec := config.Spec.(type) {
+				case *v1alpha3.VirtualService:
+					host = spec.Http[0].Route[0].Destination.Host
+				case *v1alpha3.DestinationRule:
+					if spec.Host == "reviews.default.svc.cluster.local" {
+						rule = spec
+					}
+				}
+			}
+			if host != "reviews.default.svc.cluster.local" {
+				t.Errorf("routed to %q, want the local host", host)
+			}
+			if (rule != nil) != tc.wantLocal {
+				t.Fatalf("got rule %v for the local host, want one: %t", rule, tc.wantLocal)
+			}
+			if rule != nil && rule.TrafficPolicy.GetOutlierDetection() == nil {
+				t.Errorf("the rule of the local host has no outlier detection: %v", rule)
+			}
+		})
+	}
+}
@@ -6,11 +6,16 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha1"
+	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha2"
 	mcmodel "github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/model"
+	"github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/reconcile"
 
+	"istio.io/api/networking/v1alpha3"
+	istiomodel "istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pkg/log"
 
+	kube_v1 "k8s.io/api/core/v1"
+
 	"github.com/gorilla/mux"
 )
 
@@ -19,13 +24,22 @@ import (
 type Server struct {
 	httpServer http.Server
 	store      mcmodel.MCConfigStore
+	istioStore istiomodel.ConfigStore
+	services   reconcile.ServiceLister
+	policies   mcmodel.ClusterPolicyScope
 	config     *ClusterConfig
 }
 
 // NewServer will create a new agent server to serve peer request on the
 // specific address:port with information from the provided config store. The
-// server will start listening only when the Run() function is called.
-func NewServer(config *ClusterConfig, store mcmodel.MCConfigStore) (*Server, error) {
+// server will start listening only when the Run() function is called. The
+// route policies of the exposed services are read from the VirtualServices of
+// the Istio config store, none are published if it is nil. The services
+// exposed by label selectors are looked up in the K8s Services of 'services',
+// none are exposed if it is nil. The services exposed by cluster policies are
+// resolved by 'policies', none are exposed if it is nil.
+func NewServer(config *ClusterConfig, store mcmodel.MCConfigStore, istioStore istiomodel.ConfigStore,
+	services reconcile.ServiceLister, policies mcmodel.ClusterPolicyScope) (*Server, error) {
 	router := mux.NewRouter()
 	s := &Server{
 		httpServer: http.Server{
@@ -34,8 +48,11 @@ func NewServer(config *ClusterConfig, store mcmodel.MCConfigStore) (*Server, err
 			Addr:         fmt.Sprintf(":%d", config.AgentPort),
 			Handler:      router,
 		},
-		store:  store,
-		config: config,
+		store:      store,
+		istioStore: istioStore,
+		services:   services,
+		policies:   policies,
+		config:     config,
 	}
 	_ = router.NewRoute().PathPrefix("/exposed/{clusterID}").Methods("GET").HandlerFunc(s.handlePoliciesReq)
 
@@ -97,10 +114,10 @@ func (s *Server) isTrustedCluster(clusterID string) bool {
 // specified cluster ID and return those.
 func (s *Server) exposedServices(clusterID string) []*ExposedService {
 	var results []*ExposedService
-	for _, policy := range s.store.ServiceExpositionPolicies() {
-		value, _ := policy.Spec.(*v1alpha1.ServiceExpositionPolicy)
-		for _, exposed := range value.Exposed {
-			if isRelevantExposedService(exposed, clusterID) {
+	for _, policy := range s.expositionPolicies() {
+		value, _ := policy.Spec.(*v1alpha2.ServiceExpositionPolicy)
+		for _, exposed := range s.policyExposures(value, policy.Namespace) {
+			if mcmodel.ExposedToCluster(exposed, clusterID, s.config.ClusterGroups) {
 				exposedName := exposed.Alias
 				if exposedName == "" {
 					exposedName = exposed.Name
@@ -108,7 +125,9 @@ func (s *Server) exposedServices(clusterID string) []*ExposedService {
 				results = append(results, &ExposedService{
 					Name:      exposedName,
 					Namespace: policy.Namespace,
-					Port:      exposed.Port,
+					Port:      v1alpha2.PrimaryPortNumber(exposed.Ports),
+					Ports:     exposed.Ports,
+					Policy:    s.routePolicy(exposed, policy.Namespace),
 				})
 			}
 		}
@@ -116,21 +135,99 @@ func (s *Server) exposedServices(clusterID string) []*ExposedService {
 	return results
 }
 
-// Checks whether the cluster ID is listed in the list of clusters that the
-// service is exposed to.
-func isRelevantExposedService(service *v1alpha1.ServiceExpositionPolicy_ExposedService, toClusterID string) bool {
-	// If there is no clusters list, we treat this policy as exposed to all trusted clusters
-	if len(service.Clusters) == 0 {
-		return true
+// Return the exposition policies, followed by the cluster policies as they
+// apply in each namespace.
+func (s *Server) expositionPolicies() []istiomodel.Config {
+	out := s.store.ServiceExpositionPolicies()
+	if s.policies == nil {
+		return out
+	}
+	for _, policy := range s.store.ClusterServiceExpositionPolicies() {
+		resolved, err := s.policies.NamespaceExposures(policy)
+		if err != nil {
+			log.Warnf("Failed to resolve the namespaces of cluster policy %s: %v", policy.Name, err)
+			continue
+		}
+		out = append(out, resolved...)
 	}
+	return out
+}
 
-	// Go through the list of allowed clusters and see if it is listed
-	for _, cluster := range service.Clusters {
-		if cluster == toClusterID {
-			return true
+// Expand the services of the policy selected by labels among the K8s Services
+// of the namespace.
+func (s *Server) policyExposures(sep *v1alpha2.ServiceExpositionPolicy,
+	namespace string) []*v1alpha2.ServiceExpositionPolicy_ExposedService {
+	if !mcmodel.SelectsServices(sep) {
+		return sep.Exposed
+	}
+	var svcs []kube_v1.Service
+	if s.services != nil {
+		var err error
+		if svcs, err = s.services.List(namespace); err != nil {
+			log.Warnf("Failed to list the K8s Services of namespace %s: %v", namespace, err)
 		}
 	}
+	return mcmodel.ExposedServices(sep, namespace, svcs)
+}
 
-	// Service is not exposed to the specified cluster
+// Search the Istio config store for the VirtualService routing the in-mesh
+// requests to the exposed service and return the policy of its route, nil if
+// there is none. VirtualServices generated by the agent are ignored.
+func (s *Server) routePolicy(exposed *v1alpha2.ServiceExpositionPolicy_ExposedService, namespace string) *v1alpha2.RoutePolicy {
+	if s.istioStore == nil {
+		return nil
+	}
+	configs, err := s.istioStore.List(istiomodel.VirtualService.Type, namespace)
+	if err != nil {
+		log.Warnf("Failed to list the VirtualServices of namespace %s: %v", namespace, err)
+		return nil
+	}
+	for _, config := range configs {
+		if mcmodel.GetProvenance(config.Annotations).Len() > 0 {
+			continue
+		}
+		vs, ok := config.Spec.(*v1alpha3.VirtualService)
+		if !ok || !routesMesh(vs) {
+			continue
+		}
+		for _, host := range vs.Hosts {
+			if mcmodel.IsServiceHost(host, exposed.Name, namespace) {
+				if route := exposedRoute(vs, exposed, namespace); route != nil {
+					return mcmodel.RoutePolicyOf(route)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// exposedRoute returns the first route of the VirtualService without match
+// conditions sending requests to the exposed service, and to its subset if it
+// has one.
+func exposedRoute(vs *v1alpha3.VirtualService, exposed *v1alpha2.ServiceExpositionPolicy_ExposedService, namespace string) *v1alpha3.HTTPRoute {
+	for _, route := range vs.Http {
+		if len(route.Match) > 0 {
+			continue
+		}
+		for _, dest := range route.Route {
+			if dest.Destination != nil && mcmodel.IsServiceHost(dest.Destination.Host, exposed.Name, namespace) &&
+				(exposed.Subset == "" || dest.Destination.Subset == exposed.Subset) {
+				return route
+			}
+		}
+	}
+	return nil
+}
+
+// routesMesh returns true if the VirtualService applies to the sidecars
+func routesMesh(vs *v1alpha3.VirtualService) bool {
+	if len(vs.Gateways) == 0 {
+		return true
+	}
+	for _, gateway := range vs.Gateways {
+		if gateway == "mesh" {
+			return true
+		}
+	}
 	return false
 }
@@ -0,0 +1,130 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	kube_v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	mcmodel "github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/model"
+)
+
+// serviceCache keeps a local copy of the K8s Services of all namespaces,
+// kept up to date by a shared informer. It implements reconcile.ServiceLister
+// so that reconciling does not need to list Services from the API server,
+// and reports the namespaces whose Services may be selected differently.
+type serviceCache struct {
+	informer cache.SharedIndexInformer
+}
+
+// newServiceCache creates a Services cache fed by the list-watcher. onChange,
+// if not nil, is called with the namespace of each Service added, deleted or
+// whose labels or ports change, including the Services listed initially.
+// The Services created by the agent are not reported.
+func newServiceCache(lw cache.ListerWatcher, resyncPeriod time.Duration, onChange func(namespace string)) *serviceCache {
+	c := &serviceCache{
+		informer: cache.NewSharedIndexInformer(lw, &kube_v1.Service{}, resyncPeriod,
+			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}),
+	}
+	if onChange == nil {
+		return c
+	}
+	notify := func(old, cur interface{}) {
+		oldSvc, curSvc := toService(old), toService(cur)
+		svc := curSvc
+		if svc == nil {
+			svc = oldSvc
+		}
+		if svc == nil || mcmodel.GetProvenance(svc.Annotations).Len() > 0 {
+			return
+		}
+		if oldSvc != nil && curSvc != nil && reflect.DeepEqual(oldSvc.Labels, curSvc.Labels) &&
+			reflect.DeepEqual(oldSvc.Spec.Ports, curSvc.Spec.Ports) {
+			return
+		}
+		onChange(svc.Namespace)
+	}
+	c.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { notify(nil, obj) },
+		UpdateFunc: notify,
+		DeleteFunc: func(obj interface{}) { notify(obj, nil) },
+	})
+	return c
+}
+
+// toService returns the Service of an informer notification, nil if there is
+// none. Deletions may carry the last known state of the Service.
+func toService(obj interface{}) *kube_v1.Service {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	svc, _ := obj.(*kube_v1.Service)
+	return svc
+}
+
+// serviceListWatch lists and watches the Services of all namespaces
+func serviceListWatch(client kubernetes.Interface) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			return client.CoreV1().Services(metav1.NamespaceAll).List(opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			return client.CoreV1().Services(metav1.NamespaceAll).Watch(opts)
+		},
+	}
+}
+
+// Run starts the informer and blocks until the stop channel is closed
+func (c *serviceCache) Run(stop <-chan struct{}) {
+	c.informer.Run(stop)
+}
+
+// HasSynced returns true once the initial list of Services was loaded
+func (c *serviceCache) HasSynced() bool {
+	return c.informer.HasSynced()
+}
+
+// List returns the cached Services of a namespace, or of all namespaces for
+// metav1.NamespaceAll. The returned Services are copies and may be modified.
+func (c *serviceCache) List(namespace string) ([]kube_v1.Service, error) {
+	var objs []interface{}
+	if namespace == metav1.NamespaceAll {
+		objs = c.informer.GetIndexer().List()
+	} else {
+		var err error
+		objs, err = c.informer.GetIndexer().ByIndex(cache.NamespaceIndex, namespace)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([]kube_v1.Service, 0, len(objs))
+	for _, obj := range objs {
+		svc, ok := obj.(*kube_v1.Service)
+		if !ok {
+			return nil, fmt.Errorf("unexpected object %T in the Services cache", obj)
+		}
+		out = append(out, *svc.DeepCopy())
+	}
+	return out, nil
+}
@@ -0,0 +1,155 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	kube_v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	mcmodel "github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/model"
+)
+
+// fakeServiceListWatch serves an initial list of Services and then the
+// events sent to its watcher. It counts the calls to List.
+type fakeServiceListWatch struct {
+	lock    sync.Mutex
+	initial []kube_v1.Service
+	watcher *watch.FakeWatcher
+	lists   int
+}
+
+func (lw *fakeServiceListWatch) List(options metav1.ListOptions) (runtime.Object, error) {
+	lw.lock.Lock()
+	defer lw.lock.Unlock()
+	lw.lists++
+	return &kube_v1.ServiceList{ListMeta: metav1.ListMeta{ResourceVersion: "1"}, Items: lw.initial}, nil
+}
+
+func (lw *fakeServiceListWatch) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	return lw.watcher, nil
+}
+
+func namespacedService(name, namespace string) kube_v1.Service {
+	return kube_v1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+}
+
+func serviceNames(t *testing.T, c *serviceCache, namespace string) []string {
+	svcs, err := c.List(namespace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0, len(svcs))
+	for _, svc := range svcs {
+		names = append(names, svc.Namespace+"/"+svc.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestServiceCache(t *testing.T) {
+	lw := &fakeServiceListWatch{
+		initial: []kube_v1.Service{
+			namespacedService("foo", "default"),
+			namespacedService("bar", "ns2"),
+		},
+		watcher: watch.NewFake(),
+	}
+	c := newServiceCache(lw, 0, nil)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go c.Run(stop)
+	if !cache.WaitForCacheSync(stop, c.HasSynced) {
+		t.Fatal("cache did not sync")
+	}
+
+	if names := serviceNames(t, c, "default"); len(names) != 1 || names[0] != "default/foo" {
+		t.Errorf("unexpected Services in namespace default: %v", names)
+	}
+	if names := serviceNames(t, c, metav1.NamespaceAll); len(names) != 2 {
+		t.Errorf("unexpected Services in all namespaces: %v", names)
+	}
+
+	// Changes are picked up from the watch without listing again
+	added := namespacedService("baz", "default")
+	lw.watcher.Add(&added)
+	deleted := namespacedService("foo", "default")
+	lw.watcher.Delete(&deleted)
+	waitFor(t, func() bool {
+		names := serviceNames(t, c, "default")
+		return len(names) == 1 && names[0] == "default/baz"
+	})
+
+	lw.lock.Lock()
+	defer lw.lock.Unlock()
+	if lw.lists != 1 {
+		t.Errorf("expected a single List call, got %d", lw.lists)
+	}
+}
+
+func TestServiceCacheChanges(t *testing.T) {
+	lw := &fakeServiceListWatch{
+		initial: []kube_v1.Service{
+			namespacedService("foo", "default"),
+			namespacedService("bar", "ns2"),
+		},
+		watcher: watch.NewFake(),
+	}
+	var lock sync.Mutex
+	changes := make(map[string]int)
+	c := newServiceCache(lw, 0, func(namespace string) {
+		lock.Lock()
+		defer lock.Unlock()
+		changes[namespace]++
+	})
+	changed := func(namespace string) int {
+		lock.Lock()
+		defer lock.Unlock()
+		return changes[namespace]
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go c.Run(stop)
+	if !cache.WaitForCacheSync(stop, c.HasSynced) {
+		t.Fatal("cache did not sync")
+	}
+	waitFor(t, func() bool { return changed("default") == 1 && changed("ns2") == 1 })
+
+	// Only the changes of labels or ports are reported, and not for the
+	// Services created by the agent
+	annotated := namespacedService("foo", "default")
+	annotated.Annotations = map[string]string{"owner": "test"}
+	lw.watcher.Modify(&annotated)
+	generated := namespacedService("baz", "ns2")
+	generated.Annotations = map[string]string{mcmodel.ProvenanceAnnotationKey: "ns2.baz"}
+	lw.watcher.Add(&generated)
+	labeled := namespacedService("foo", "default")
+	labeled.Labels = map[string]string{"team": "bookinfo"}
+	lw.watcher.Modify(&labeled)
+	deleted := namespacedService("bar", "ns2")
+	lw.watcher.Delete(&deleted)
+	waitFor(t, func() bool { return changed("ns2") == 2 })
+	if got := changed("default"); got != 2 {
+		t.Errorf("got %d changes of namespace default, want 2", got)
+	}
+}
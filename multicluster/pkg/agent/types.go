@@ -14,7 +14,14 @@
 
 package agent
 
-import ()
+import (
+	"fmt"
+
+	istiomodel "istio.io/istio/pilot/pkg/model"
+
+	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha2"
+	"github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/model"
+)
 
 const (
 	// ConnectionModeKey is the labels key within the RemoteServiceBinding that
@@ -30,8 +37,74 @@ const (
 	// called whenever a change in an RemoteServiceBinding has been determined.
 	// Istio configs will be generated once the mode is switched to 'live'.
 	ConnectionModePotential = "potential"
+
+	// ApprovalAutomatic realizes the bindings of the services a peer exposes
+	// as soon as they are discovered
+	ApprovalAutomatic = "Automatic"
+
+	// ApprovalManual binds the services a peer newly exposes, or exposes
+	// differently, in a binding in the model.ApprovalPending phase; they are
+	// realized once an operator approves it
+	ApprovalManual = "Manual"
+
+	// StatusAnnotationKey is the annotation on a Multi-cluster config that
+	// holds the ReconcileStatus (as JSON) of the last reconciliation that had
+	// Istio or K8s configs to change
+	StatusAnnotationKey = "multicluster.istio.io/status"
+)
+
+// ReconcilePhase is the overall outcome of applying the changes needed for a
+// Multi-cluster config
+type ReconcilePhase string
+
+const (
+	// ReconcileApplied means all the changes were applied
+	ReconcileApplied ReconcilePhase = "Applied"
+
+	// ReconcileRolledBack means a change failed and all the changes applied
+	// before it were reverted. The reconciliation will be retried.
+	ReconcileRolledBack ReconcilePhase = "RolledBack"
+
+	// ReconcilePartial means a change failed and reverting the changes
+	// applied before it failed too. The steps tell which changes are in
+	// effect. The reconciliation will be retried.
+	ReconcilePartial ReconcilePhase = "Partial"
 )
 
+// StepResult is the outcome of a single Istio or K8s write
+type StepResult string
+
+const (
+	// StepApplied means the write is in effect
+	StepApplied StepResult = "Applied"
+	// StepFailed means the write was attempted and failed
+	StepFailed StepResult = "Failed"
+	// StepSkipped means the write was not attempted because a write it
+	// depends on failed
+	StepSkipped StepResult = "Skipped"
+	// StepRolledBack means the write was done and then reverted
+	StepRolledBack StepResult = "RolledBack"
+	// StepRollbackFailed means the write was done and could not be reverted
+	StepRollbackFailed StepResult = "RollbackFailed"
+)
+
+// ReconcileStatus reports the Istio and K8s writes done for a Multi-cluster
+// config
+type ReconcileStatus struct {
+	Phase ReconcilePhase `json:"phase"`
+	Steps []StepStatus   `json:"steps"`
+}
+
+// StepStatus reports a single Istio or K8s write
+type StepStatus struct {
+	Operation string     `json:"operation"`
+	Kind      string     `json:"kind"`
+	Name      string     `json:"name"`
+	Namespace string     `json:"namespace,omitempty"`
+	Result    StepResult `json:"result"`
+	Error     string     `json:"error,omitempty"`
+}
+
 // ExposedServices is a struct that holds list of entries each holding the
 // information about an exposed service. JSON format of this struct is being
 // sent back from a remote cluster's agent in response to an exposition request.
@@ -44,7 +117,16 @@ type ExposedServices struct {
 type ExposedService struct {
 	Name      string
 	Namespace string
-	Port      uint32
+
+	// Port is the primary port of the service, for the peers that don't
+	// know of Ports
+	Port uint32
+
+	// Ports are the ports of the service, the first being Port
+	Ports []*v1alpha2.ServicePort `json:",omitempty"`
+
+	// Policy is the route-level behavior of the requests to the service
+	Policy *v1alpha2.RoutePolicy `json:",omitempty"`
 }
 
 // ClusterConfig holds all the configuration information about the local
@@ -60,8 +142,122 @@ type ClusterConfig struct {
 
 	ConnectionMode string `yaml:"ConnectionMode"`
 
+	// TrustDomain is the trust domain of the cluster's Istio identities. The
+	// identity of the services bound from a peer with a trust domain is
+	// verified.
+	TrustDomain string `yaml:"TrustDomain,omitempty"`
+
+	// ConversionStyle names the model.ConversionStyle generating the Istio
+	// configs, model.DefaultConversionStyle if empty. A watched peer may
+	// set the style of the bindings to its services.
+	ConversionStyle string `yaml:"ConversionStyle,omitempty"`
+
+	// Approval tells whether the bindings of the services a watched peer
+	// exposes are realized when discovered (ApprovalAutomatic, the default)
+	// or wait for an operator to approve them (ApprovalManual)
+	Approval string `yaml:"Approval,omitempty"`
+
 	WatchedPeers []ClusterConfig `yaml:"WatchedPeers,omitempty"`
 	TrustedPeers []string        `yaml:"TrustedPeers,omitempty"`
+
+	// PeerTrustDomains maps the IDs of trusted peers to the trust domain of
+	// their Istio identities. The local services exposed to a peer only admit
+	// the identities of its trust domain. Watched peers may set their
+	// TrustDomain instead.
+	PeerTrustDomains map[string]string `yaml:"PeerTrustDomains,omitempty"`
+
+	// ClusterGroups names groups of peers, by cluster ID or glob pattern,
+	// that the clusters lists of the ServiceExpositionPolicies may refer to
+	ClusterGroups model.ClusterGroups `yaml:"ClusterGroups,omitempty"`
+
+	// Istio describes the local Istio install (control-plane namespace,
+	// gateways and TLS credentials). It may be omitted for a default install.
+	Istio model.ConversionOptions `yaml:"Istio,omitempty"`
+
+	// VIPs configures the allocation of virtual IPs to the ServiceEntries of
+	// bound remote services. No VIPs are allocated if omitted.
+	VIPs VIPOptions `yaml:"VIPs,omitempty"`
+}
+
+// VIPOptions configures the allocation of virtual IPs
+type VIPOptions struct {
+	// CIDR is the range the VIPs are allocated from. It must be within the
+	// service range of the cluster, as the K8s Services of the bound hosts
+	// take their VIP as ClusterIP.
+	CIDR string `yaml:"CIDR,omitempty"`
+
+	// ConfigMap names the ConfigMap persisting the allocations,
+	// DefaultVIPConfigMap if empty
+	ConfigMap string `yaml:"ConfigMap,omitempty"`
+
+	// Namespace of the ConfigMap, by default the Istio control-plane namespace
+	Namespace string `yaml:"Namespace,omitempty"`
+}
+
+// ConversionOptions returns the options for generating the Istio configs of
+// the local cluster, defaulting what the configuration does not set
+func (cc ClusterConfig) ConversionOptions() model.ConversionOptions {
+	opts := cc.Istio.WithDefaults()
+	opts.TrustDomains = make(map[string]string)
+	opts.ClusterGroups = cc.ClusterGroups
+	for id, td := range cc.PeerTrustDomains {
+		opts.TrustDomains[id] = td
+	}
+	for _, peer := range cc.WatchedPeers {
+		if peer.TrustDomain != "" {
+			opts.TrustDomains[peer.ID] = peer.TrustDomain
+		}
+	}
+	return opts
+}
+
+// ConversionStyleFor returns the conversion style for a Multi-cluster config.
+// RemoteServiceBindings use the style of the peers they bind services from,
+// if set; the peers of a binding must then agree on the style.
+func (cc ClusterConfig) ConversionStyleFor(config istiomodel.Config) (model.ConversionStyle, error) {
+	name, err := cc.ConversionStyleName(config)
+	if err != nil {
+		return nil, err
+	}
+	return model.GetConversionStyle(name)
+}
+
+// ConversionStyleName is implementing the model.ClusterRegistry interface. It
+// returns the name of the style ConversionStyleFor() returns.
+func (cc ClusterConfig) ConversionStyleName(config istiomodel.Config) (string, error) {
+	name := cc.ConversionStyle
+	if rsb, ok := config.Spec.(*v1alpha2.RemoteServiceBinding); ok {
+		peerStyle := ""
+		for _, remote := range rsb.Remote {
+			for _, peer := range cc.WatchedPeers {
+				if peer.ID != remote.Cluster || peer.ConversionStyle == "" {
+					continue
+				}
+				if peerStyle != "" && peerStyle != peer.ConversionStyle {
+					return "", fmt.Errorf("peers of binding %s.%s have conflicting conversion styles %s and %s",
+						config.Namespace, config.Name, peerStyle, peer.ConversionStyle)
+				}
+				peerStyle = peer.ConversionStyle
+			}
+		}
+		if peerStyle != "" {
+			name = peerStyle
+		}
+	}
+	if name == "" {
+		name = model.DefaultConversionStyle
+	}
+	return name, nil
+}
+
+// IsPeer is implementing the model.ClusterRegistry interface
+func (cc ClusterConfig) IsPeer(id string) bool {
+	for _, peer := range cc.WatchedPeers {
+		if peer.ID == id {
+			return true
+		}
+	}
+	return false
 }
 
 // Gateway is implementing the model.ClusterInfo interface
@@ -0,0 +1,87 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"reflect"
+	"testing"
+
+	istiomodel "istio.io/istio/pilot/pkg/model"
+
+	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha2"
+	"github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/model"
+)
+
+func binding(clusters ...string) istiomodel.Config {
+	rsb := &v1alpha2.RemoteServiceBinding{}
+	for _, cluster := range clusters {
+		rsb.Remote = append(rsb.Remote, &v1alpha2.RemoteServiceBinding_RemoteCluster{Cluster: cluster})
+	}
+	return istiomodel.Config{
+		ConfigMeta: istiomodel.ConfigMeta{Type: model.RemoteServiceBinding.Type, Name: "rsb", Namespace: "default"},
+		Spec:       rsb,
+	}
+}
+
+func TestConversionStyleFor(t *testing.T) {
+	cc := ClusterConfig{
+		ID: "cluster-a",
+		WatchedPeers: []ClusterConfig{
+			{ID: "cluster-b"},
+			{ID: "cluster-c", ConversionStyle: model.EgressIngressStyle},
+			{ID: "cluster-d", ConversionStyle: model.DirectIngressStyle},
+		},
+	}
+	exposure := istiomodel.Config{
+		ConfigMeta: istiomodel.ConfigMeta{Type: model.ServiceExpositionPolicy.Type, Name: "sep", Namespace: "default"},
+		Spec:       &v1alpha2.ServiceExpositionPolicy{},
+	}
+
+	tt := []struct {
+		name     string
+		style    string
+		config   istiomodel.Config
+		expected string
+		mustFail bool
+	}{
+		{name: "default exposure", config: exposure, expected: model.DefaultConversionStyle},
+		{name: "cluster exposure", style: model.EgressIngressStyle, config: exposure, expected: model.EgressIngressStyle},
+		{name: "peer without style", style: model.EgressIngressStyle, config: binding("cluster-b"),
+			expected: model.EgressIngressStyle},
+		{name: "peer style", config: binding("cluster-b", "cluster-c"), expected: model.EgressIngressStyle},
+		{name: "conflicting peers", config: binding("cluster-c", "cluster-d"), mustFail: true},
+		{name: "unknown style", style: "UNKNOWN", config: exposure, mustFail: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			cc.ConversionStyle = tc.style
+			style, err := cc.ConversionStyleFor(tc.config)
+			if tc.mustFail {
+				if err == nil {
+					t.Errorf("Got a style; failure expected")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			expected, _ := model.GetConversionStyle(tc.expected)
+			if reflect.ValueOf(style).Pointer() != reflect.ValueOf(expected).Pointer() {
+				t.Errorf("Expected the %s style", tc.expected)
+			}
+		})
+	}
+}
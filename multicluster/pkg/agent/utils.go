@@ -4,11 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"os"
 
 	"github.com/ghodss/yaml"
 	multierror "github.com/hashicorp/go-multierror"
+
+	"github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/model"
 )
 
 // RenderJSON outputs the given data as JSON
@@ -42,6 +45,33 @@ func LoadConfig(filename string) (*ClusterConfig, error) {
 	if err != nil {
 		return nil, multierror.Prefix(err, fmt.Sprintf("can't unmarshal JSON from %q:", filename))
 	}
+	if err = config.Istio.Validate(); err != nil {
+		return nil, multierror.Prefix(err, fmt.Sprintf("invalid Istio settings in %q:", filename))
+	}
+	if config.VIPs.CIDR != "" {
+		if _, _, err = net.ParseCIDR(config.VIPs.CIDR); err != nil {
+			return nil, multierror.Prefix(err, fmt.Sprintf("invalid VIP CIDR in %q:", filename))
+		}
+	}
+	if err = model.ValidateClusterGroups(config.ClusterGroups); err != nil {
+		return nil, multierror.Prefix(err, fmt.Sprintf("invalid cluster groups in %q:", filename))
+	}
+	for _, peer := range config.WatchedPeers {
+		switch peer.Approval {
+		case "", ApprovalAutomatic, ApprovalManual:
+		default:
+			return nil, fmt.Errorf("invalid approval %q for %s in %q, expected %s or %s",
+				peer.Approval, peer.ID, filename, ApprovalAutomatic, ApprovalManual)
+		}
+	}
+	for _, cc := range append([]ClusterConfig{config}, config.WatchedPeers...) {
+		if cc.ConversionStyle == "" {
+			continue
+		}
+		if _, err = model.GetConversionStyle(cc.ConversionStyle); err != nil {
+			return nil, multierror.Prefix(err, fmt.Sprintf("invalid conversion style for %s in %q:", cc.ID, filename))
+		}
+	}
 
 	return &config, nil
 }
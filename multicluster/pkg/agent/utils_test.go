@@ -0,0 +1,158 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfigIstioSettings(t *testing.T) {
+	tt := []struct {
+		name     string
+		istio    string
+		mustFail bool
+	}{
+		{name: "default"},
+		{name: "istio-mutual",
+			istio: "Istio:\n  TLS:\n    Mode: ISTIO_MUTUAL\n"},
+		{name: "unknown-mode",
+			istio:    "Istio:\n  TLS:\n    Mode: SIMPLE\n",
+			mustFail: true},
+		{name: "sds-credential",
+			istio:    "Istio:\n  TLS:\n    CredentialName: mc-client-certs\n",
+			mustFail: true},
+	}
+
+	dir, err := ioutil.TempDir("", "mc-agent-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			filename := filepath.Join(dir, tc.name+".yaml")
+			data := "ID: cluster-a\nWatchedPeers:\n- ID: cluster-b\n  TrustDomain: b.example.com\n" +
+				"TrustedPeers:\n- cluster-c\nPeerTrustDomains:\n  cluster-c: c.example.com\n" + tc.istio
+			if err := ioutil.WriteFile(filename, []byte(data), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			config, err := LoadConfig(filename)
+			if tc.mustFail {
+				if err == nil {
+					t.Errorf("Loaded %q; failure expected", tc.istio)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error loading config: %v", err)
+			}
+			if td := config.ConversionOptions().TrustDomains["cluster-b"]; td != "b.example.com" {
+				t.Errorf("Expected the trust domain of cluster-b, got %q", td)
+			}
+			if td := config.ConversionOptions().TrustDomains["cluster-c"]; td != "c.example.com" {
+				t.Errorf("Expected the trust domain of cluster-c, got %q", td)
+			}
+		})
+	}
+}
+
+func TestLoadConfigClusterGroups(t *testing.T) {
+	tt := []struct {
+		name     string
+		groups   string
+		mustFail bool
+	}{
+		{name: "no-groups"},
+		{name: "groups",
+			groups: "ClusterGroups:\n  europe:\n  - \"*-eu-*\"\n  - cluster-b\n"},
+		{name: "bad-pattern",
+			groups:   "ClusterGroups:\n  europe:\n  - \"prod-[\"\n",
+			mustFail: true},
+	}
+
+	dir, err := ioutil.TempDir("", "mc-agent-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			filename := filepath.Join(dir, tc.name+".yaml")
+			if err := ioutil.WriteFile(filename, []byte("ID: cluster-a\n"+tc.groups), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := LoadConfig(filename)
+			if tc.mustFail {
+				if err == nil {
+					t.Errorf("Loaded %q; failure expected", tc.groups)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error loading config: %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadConfigApproval(t *testing.T) {
+	tt := []struct {
+		name     string
+		approval string
+		mustFail bool
+	}{
+		{name: "default"},
+		{name: "automatic", approval: ApprovalAutomatic},
+		{name: "manual", approval: ApprovalManual},
+		{name: "unknown", approval: "Never", mustFail: true},
+	}
+
+	dir, err := ioutil.TempDir("", "mc-agent-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			filename := filepath.Join(dir, tc.name+".yaml")
+			data := "ID: cluster-a\nWatchedPeers:\n- ID: cluster-b\n  Approval: \"" + tc.approval + "\"\n"
+			if err := ioutil.WriteFile(filename, []byte(data), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			config, err := LoadConfig(filename)
+			if tc.mustFail {
+				if err == nil {
+					t.Errorf("Loaded approval %q; failure expected", tc.approval)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error loading config: %v", err)
+			}
+			if got := config.WatchedPeers[0].Approval; got != tc.approval {
+				t.Errorf("got approval %q, want %q", got, tc.approval)
+			}
+		})
+	}
+}
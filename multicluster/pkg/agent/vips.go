@@ -0,0 +1,213 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"fmt"
+	"net"
+	"sync"
+
+	multierror "github.com/hashicorp/go-multierror"
+
+	"istio.io/istio/pkg/log"
+
+	kube_v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultVIPConfigMap is the ConfigMap persisting the VIP allocations unless
+// VIPOptions names another
+const DefaultVIPConfigMap = "mc-agent-vips"
+
+// configMapStore is the subset of the K8s ConfigMaps client of a namespace
+// needed for persisting the VIP allocations
+type configMapStore interface {
+	Get(name string, options metav1.GetOptions) (*kube_v1.ConfigMap, error)
+	Create(*kube_v1.ConfigMap) (*kube_v1.ConfigMap, error)
+	Update(*kube_v1.ConfigMap) (*kube_v1.ConfigMap, error)
+}
+
+// vipAllocator implements model.VIPAllocator by handing out the addresses of a
+// CIDR in order. The allocations are kept in a ConfigMap mapping hosts to
+// VIPs so that the hosts keep their VIPs when the agent restarts.
+type vipAllocator struct {
+	mu         sync.Mutex
+	network    *net.IPNet
+	vips       map[string]string // host -> VIP
+	hosts      map[string]string // VIP -> host
+	configMaps configMapStore
+	name       string
+}
+
+// newVIPAllocator creates an allocator for the CIDR, loading the allocations
+// persisted in the named ConfigMap
+func newVIPAllocator(cidr string, configMaps configMapStore, name string) (*vipAllocator, error) {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &vipAllocator{
+		network:    network,
+		vips:       make(map[string]string),
+		hosts:      make(map[string]string),
+		configMaps: configMaps,
+		name:       name,
+	}
+
+	cm, err := configMaps.Get(name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return a, nil
+	}
+	if err != nil {
+		return nil, multierror.Prefix(err, fmt.Sprintf("can't load VIP allocations from %s:", name))
+	}
+	for host, vip := range cm.Data {
+		ip := net.ParseIP(vip)
+		if ip == nil || !network.Contains(ip) {
+			log.Warnf("Ignoring VIP %q of %s, not in %s", vip, host, cidr)
+			continue
+		}
+		a.vips[host] = ip.String()
+		a.hosts[ip.String()] = host
+	}
+	return a, nil
+}
+
+// Allocate is implementing the model.VIPAllocator interface
+func (a *vipAllocator) Allocate(host string) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if vip, ok := a.vips[host]; ok {
+		return vip, nil
+	}
+
+	vip := a.nextFree()
+	if vip == "" {
+		return "", fmt.Errorf("no VIP left in %s", a.network)
+	}
+	a.vips[host] = vip
+	a.hosts[vip] = host
+	if err := a.save(); err != nil {
+		delete(a.vips, host)
+		delete(a.hosts, vip)
+		return "", err
+	}
+	return vip, nil
+}
+
+// Release frees the VIP of the host, if it has one
+func (a *vipAllocator) Release(host string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	vip, ok := a.vips[host]
+	if !ok {
+		return nil
+	}
+	delete(a.vips, host)
+	delete(a.hosts, vip)
+	if err := a.save(); err != nil {
+		a.vips[host] = vip
+		a.hosts[vip] = host
+		return err
+	}
+	return nil
+}
+
+// nextFree returns the lowest address of the network that is not allocated,
+// skipping the network and broadcast addresses. Returns "" if there is none.
+func (a *vipAllocator) nextFree() string {
+	ones, bits := a.network.Mask.Size()
+	ip := make(net.IP, len(a.network.IP))
+	copy(ip, a.network.IP)
+	for ; a.network.Contains(ip); increment(ip) {
+		if bits-ones >= 2 && (ip.Equal(a.network.IP) || isBroadcast(ip, a.network)) {
+			continue
+		}
+		if _, used := a.hosts[ip.String()]; !used {
+			return ip.String()
+		}
+	}
+	return ""
+}
+
+// save writes the allocations to the ConfigMap, creating it if needed
+func (a *vipAllocator) save() error {
+	data := make(map[string]string, len(a.vips))
+	for host, vip := range a.vips {
+		data[host] = vip
+	}
+
+	cm, err := a.configMaps.Get(a.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = a.configMaps.Create(&kube_v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: a.name},
+			Data:       data,
+		})
+	} else if err == nil {
+		cm.Data = data
+		_, err = a.configMaps.Update(cm)
+	}
+	if err != nil {
+		return multierror.Prefix(err, fmt.Sprintf("can't save VIP allocations to %s:", a.name))
+	}
+	return nil
+}
+
+// vipAllocations records the hosts a reconciliation allocated VIPs to, so
+// that the VIPs of those left without ServiceEntry, e.g. because creating
+// it failed and was rolled back, can be freed
+type vipAllocations struct {
+	allocator *vipAllocator
+	allocated []string
+}
+
+// Allocate is implementing the model.VIPAllocator interface
+func (a *vipAllocations) Allocate(host string) (string, error) {
+	vip, err := a.allocator.Allocate(host)
+	if err == nil {
+		a.allocated = append(a.allocated, host)
+	}
+	return vip, err
+}
+
+// hosts returns the hosts VIPs were allocated to, none if 'a' is nil
+func (a *vipAllocations) hosts() []string {
+	if a == nil {
+		return nil
+	}
+	return a.allocated
+}
+
+func increment(ip net.IP) {
+	for i := len(ip) - 1; i >= 0; i-- {
+		ip[i]++
+		if ip[i] != 0 {
+			return
+		}
+	}
+}
+
+func isBroadcast(ip net.IP, network *net.IPNet) bool {
+	for i := range ip {
+		if ip[i] != network.IP[i]|^network.Mask[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,362 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"istio.io/api/networking/v1alpha3"
+	"istio.io/istio/pilot/pkg/config/memory"
+	istiomodel "istio.io/istio/pilot/pkg/model"
+
+	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha2"
+	mcmodel "github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/model"
+	"github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/reconcile"
+
+	kube_v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// memoryConfigMaps keeps ConfigMaps in memory and fails the writes while failing is set
+type memoryConfigMaps struct {
+	configMaps map[string]kube_v1.ConfigMap
+	failing    bool
+}
+
+func (s *memoryConfigMaps) Get(name string, options metav1.GetOptions) (*kube_v1.ConfigMap, error) {
+	cm, ok := s.configMaps[name]
+	if !ok {
+		return nil, apierrors.NewNotFound(kube_v1.Resource("configmaps"), name)
+	}
+	return &cm, nil
+}
+
+func (s *memoryConfigMaps) Create(cm *kube_v1.ConfigMap) (*kube_v1.ConfigMap, error) {
+	return s.write(cm)
+}
+
+func (s *memoryConfigMaps) Update(cm *kube_v1.ConfigMap) (*kube_v1.ConfigMap, error) {
+	return s.write(cm)
+}
+
+func (s *memoryConfigMaps) write(cm *kube_v1.ConfigMap) (*kube_v1.ConfigMap, error) {
+	if s.failing {
+		return nil, fmt.Errorf("injected failure")
+	}
+	s.configMaps[cm.Name] = *cm
+	return cm, nil
+}
+
+func allocate(t *testing.T, a *vipAllocator, host, want string) {
+	got, err := a.Allocate(host)
+	if err != nil {
+		t.Fatalf("Allocate(%s): %v", host, err)
+	}
+	if got != want {
+		t.Errorf("Allocate(%s) = %s, want %s", host, got, want)
+	}
+}
+
+func TestVIPAllocator(t *testing.T) {
+	store := &memoryConfigMaps{configMaps: make(map[string]kube_v1.ConfigMap)}
+	a, err := newVIPAllocator("10.10.0.0/30", store, DefaultVIPConfigMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The network and broadcast addresses are skipped
+	allocate(t, a, "a.default.svc.cluster.local", "10.10.0.1")
+	allocate(t, a, "b.default.svc.cluster.local", "10.10.0.2")
+	allocate(t, a, "a.default.svc.cluster.local", "10.10.0.1")
+	if _, err = a.Allocate("c.default.svc.cluster.local"); err == nil {
+		t.Error("Expected the CIDR to be exhausted")
+	}
+
+	want := map[string]string{
+		"a.default.svc.cluster.local": "10.10.0.1",
+		"b.default.svc.cluster.local": "10.10.0.2",
+	}
+	if got := store.configMaps[DefaultVIPConfigMap].Data; !reflect.DeepEqual(got, want) {
+		t.Errorf("Persisted %v, want %v", got, want)
+	}
+
+	// Allocations survive a restart
+	restarted, err := newVIPAllocator("10.10.0.0/30", store, DefaultVIPConfigMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	allocate(t, restarted, "b.default.svc.cluster.local", "10.10.0.2")
+
+	// A released VIP is handed out again
+	if err = restarted.Release("a.default.svc.cluster.local"); err != nil {
+		t.Fatal(err)
+	}
+	allocate(t, restarted, "c.default.svc.cluster.local", "10.10.0.1")
+
+	// An allocation that cannot be persisted is not made
+	store.failing = true
+	if err = restarted.Release("b.default.svc.cluster.local"); err == nil {
+		t.Error("Expected the release to fail")
+	}
+	allocate(t, restarted, "b.default.svc.cluster.local", "10.10.0.2")
+}
+
+func TestVIPAllocatorIgnoresOtherRanges(t *testing.T) {
+	store := &memoryConfigMaps{configMaps: map[string]kube_v1.ConfigMap{
+		"vips": kube_v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "vips"},
+			Data: map[string]string{
+				"a.default.svc.cluster.local": "10.20.0.1",
+				"b.default.svc.cluster.local": "not-an-ip",
+				"c.default.svc.cluster.local": "10.10.0.5",
+			},
+		},
+	}}
+	a, err := newVIPAllocator("10.10.0.0/24", store, "vips")
+	if err != nil {
+		t.Fatal(err)
+	}
+	allocate(t, a, "c.default.svc.cluster.local", "10.10.0.5")
+	allocate(t, a, "a.default.svc.cluster.local", "10.10.0.1")
+	allocate(t, a, "b.default.svc.cluster.local", "10.10.0.2")
+}
+
+func TestConversionWithVIPs(t *testing.T) {
+	store := &memoryConfigMaps{configMaps: make(map[string]kube_v1.ConfigMap)}
+	a, err := newVIPAllocator("10.0.240.0/20", store, DefaultVIPConfigMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cc := ClusterConfig{
+		ID:           "cluster1",
+		WatchedPeers: []ClusterConfig{{ID: "cluster2", GatewayIP: "169.62.129.93", GatewayPort: 80}},
+	}
+	opts := cc.ConversionOptions()
+	opts.VIPs = a
+
+	rsb := istiomodel.Config{
+		ConfigMeta: istiomodel.ConfigMeta{
+			Type:      mcmodel.RemoteServiceBinding.Type,
+			Name:      "reviews",
+			Namespace: "default",
+		},
+		Spec: &v1alpha2.RemoteServiceBinding{
+			Remote: []*v1alpha2.RemoteServiceBinding_RemoteCluster{{
+				Cluster:  "cluster2",
+				Services: []*v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService{{Name: "reviews", Ports: []*v1alpha2.ServicePort{{Number: 9080}}}},
+			}},
+		},
+	}
+
+	for _, name := range mcmodel.ConversionStyles() {
+		t.Run(name, func(t *testing.T) {
+			style, err := mcmodel.GetConversionStyle(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			istioStore := memory.Make(istiomodel.IstioConfigTypes)
+			configs, svcs, err := style.Convert([]istiomodel.Config{rsb}, cc, istioStore, nil, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			found := false
+			for _, config := range configs {
+				se, ok := config.Spec.(*v1alpha3.ServiceEntry)
+				if !ok || se.Hosts[0] != "reviews.default.svc.cluster.local" {
+					continue
+				}
+				if want := []string{"10.0.240.1"}; !reflect.DeepEqual(se.Addresses, want) {
+					t.Errorf("ServiceEntry %s has addresses %v, want %v", config.Name, se.Addresses, want)
+				}
+				found = true
+			}
+			if !found {
+				t.Errorf("No ServiceEntry for reviews.default.svc.cluster.local in %v", configs)
+			}
+
+			// DNS resolves the host to the ClusterIP of its K8s Service, which must be the VIP
+			if len(svcs) != 1 || svcs[0].Name != "reviews" {
+				t.Fatalf("Expected the K8s Service of reviews, got %v", svcs)
+			}
+			if ip := svcs[0].Spec.ClusterIP; ip != "10.0.240.1" {
+				t.Errorf("K8s Service reviews resolves to %q, want the VIP 10.0.240.1", ip)
+			}
+		})
+	}
+}
+
+// The VIPs of the hosts left without ServiceEntry are freed, whether the
+// ServiceEntry was deleted or its creation rolled back
+func TestVIPsReleased(t *testing.T) {
+	cc := ClusterConfig{
+		ID:           "cluster1",
+		WatchedPeers: []ClusterConfig{{ID: "cluster2", GatewayIP: "169.62.129.93", GatewayPort: 80}},
+	}
+	rsb := istiomodel.Config{
+		ConfigMeta: istiomodel.ConfigMeta{
+			Type:      mcmodel.RemoteServiceBinding.Type,
+			Name:      "reviews",
+			Namespace: "default",
+		},
+		Spec: &v1alpha2.RemoteServiceBinding{
+			Remote: []*v1alpha2.RemoteServiceBinding_RemoteCluster{{
+				Cluster:  "cluster2",
+				Services: []*v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService{{Name: "reviews", Ports: []*v1alpha2.ServicePort{{Number: 9080}}}},
+			}},
+		},
+	}
+	host := "reviews.default.svc.cluster.local"
+
+	tt := []struct {
+		name      string
+		failures  map[string]bool
+		delete    bool
+		wantAdded bool // whether the host keeps its VIP once the binding is added
+	}{
+		{name: "deleted", delete: true, wantAdded: true},
+		{name: "rolled back", failures: map[string]bool{"create dest-rule-reviews": true}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			configMaps := &memoryConfigMaps{configMaps: make(map[string]kube_v1.ConfigMap)}
+			a, err := newVIPAllocator("10.0.240.0/20", configMaps, DefaultVIPConfigMap)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var writes []string
+			store := recordingStore{ConfigStore: memory.Make(istiomodel.IstioConfigTypes), writes: &writes, failures: tc.failures}
+			services := recordingServices{services: make(map[string]kube_v1.Service), writes: &writes}
+			cm := &ConfigsManagement{istioStore: store, vips: a}
+			style, err := cc.ConversionStyleFor(rsb)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// realize runs the reconciler, applies its changes then frees the VIPs
+			realize := func(changesFor func(reconcile.Reconciler) (*reconcile.ConfigChanges, error)) {
+				allocations := &vipAllocations{allocator: a}
+				opts := cc.ConversionOptions()
+				opts.VIPs = allocations
+				var svcs reconcile.ServiceList
+				for _, svc := range services.services {
+					svcs = append(svcs, svc)
+				}
+				changes, err := changesFor(reconcile.NewReconciler(store, svcs, cc, style, opts))
+				if err != nil {
+					t.Fatal(err)
+				}
+				_, err = applyChanges(store, services, changes)
+				if (err != nil) != (tc.failures != nil) {
+					t.Errorf("applying the changes: got error %v, want one: %v", err, tc.failures != nil)
+				}
+				cm.releaseVIPs(allocations.hosts(), changes.Deletions)
+			}
+
+			realize(func(r reconcile.Reconciler) (*reconcile.ConfigChanges, error) { return r.AddMulticlusterConfig(rsb) })
+			if _, ok := a.vips[host]; ok != tc.wantAdded {
+				t.Errorf("%s has a VIP: %v, want %v", host, ok, tc.wantAdded)
+			}
+			if !tc.delete {
+				return
+			}
+			realize(func(r reconcile.Reconciler) (*reconcile.ConfigChanges, error) { return r.DeleteMulticlusterConfig(rsb) })
+			if vip, ok := a.vips[host]; ok {
+				t.Errorf("%s still has VIP %s once the binding is deleted", host, vip)
+			}
+			if len(configMaps.configMaps[DefaultVIPConfigMap].Data) != 0 {
+				t.Errorf("the allocations persisted are %v, want none", configMaps.configMaps[DefaultVIPConfigMap].Data)
+			}
+		})
+	}
+}
+
+// The K8s Service of a bound host must have the VIP as ClusterIP, which cannot
+// be changed once the Service exists
+func TestVIPOfExistingService(t *testing.T) {
+	cc := ClusterConfig{
+		ID:           "cluster1",
+		WatchedPeers: []ClusterConfig{{ID: "cluster2", GatewayIP: "169.62.129.93", GatewayPort: 80}},
+	}
+	rsb := istiomodel.Config{
+		ConfigMeta: istiomodel.ConfigMeta{
+			Type:      mcmodel.RemoteServiceBinding.Type,
+			Name:      "reviews",
+			Namespace: "default",
+		},
+		Spec: &v1alpha2.RemoteServiceBinding{
+			Remote: []*v1alpha2.RemoteServiceBinding_RemoteCluster{{
+				Cluster:  "cluster2",
+				Services: []*v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService{{Name: "reviews", Ports: []*v1alpha2.ServicePort{{Number: 9080}}}},
+			}},
+		},
+	}
+
+	tt := []struct {
+		name      string
+		clusterIP string
+		wantErr   bool
+	}{
+		{name: "vip", clusterIP: "10.0.240.1"},
+		{name: "assigned by the master", clusterIP: "10.0.0.7", wantErr: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			a, err := newVIPAllocator("10.0.240.0/20",
+				&memoryConfigMaps{configMaps: make(map[string]kube_v1.ConfigMap)}, DefaultVIPConfigMap)
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts := cc.ConversionOptions()
+			opts.VIPs = a
+			style, err := cc.ConversionStyleFor(rsb)
+			if err != nil {
+				t.Fatal(err)
+			}
+			existing := kube_v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "reviews",
+					Namespace:   "default",
+					Annotations: map[string]string{mcmodel.ProvenanceAnnotationKey: mcmodel.ProvenanceAnnotation(rsb)},
+				},
+				Spec: kube_v1.ServiceSpec{
+					Type:      kube_v1.ServiceTypeClusterIP,
+					ClusterIP: tc.clusterIP,
+					Ports:     []kube_v1.ServicePort{{Protocol: "TCP", Port: 9080}},
+				},
+			}
+			r := reconcile.NewReconciler(memory.Make(istiomodel.IstioConfigTypes),
+				reconcile.ServiceList{existing}, cc, style, opts)
+
+			changes, err := r.AddMulticlusterConfig(rsb)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error for the ClusterIP %s, got changes %v", tc.clusterIP, changes.Kubernetes)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(changes.Kubernetes.Additions) != 0 || len(changes.Kubernetes.Modifications) != 0 {
+				t.Errorf("Expected the K8s Service to be kept, got %v", changes.Kubernetes)
+			}
+		})
+	}
+}
@@ -8,17 +8,12 @@ package crd
 
 import (
 	"fmt"
-	"time"
 
 	multierror "github.com/hashicorp/go-multierror"
-	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
-	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
-	"k8s.io/apimachinery/pkg/util/wait"
 
 	// import GKE cluster authentication plugin
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
@@ -76,8 +71,8 @@ func apiVersion(schema *model.ProtoSchema) string {
 }
 
 func apiVersionFromConfig(config *model.Config) string {
-	//return config.Group + "/" + config.Version
-	return "multicluster.istio.io/v1alpha1"
+	s := knownTypes[config.Type]
+	return apiVersion(&s.schema)
 }
 
 func newClientSet(descriptor model.ConfigDescriptor) (map[string]*restClient, error) {
@@ -88,16 +83,17 @@ func newClientSet(descriptor model.ConfigDescriptor) (map[string]*restClient, er
 			return nil, fmt.Errorf("missing known type for %q", typ.Type)
 		}
 
-		rc, ok := cs[apiVersion(&typ)]
+		// the client reads and writes the storage version of the type
+		rc, ok := cs[apiVersion(&s.schema)]
 		if !ok {
 			// create a new client if one doesn't already exist
 			rc = &restClient{
 				apiVersion: schema.GroupVersion{
-					ResourceGroup(&typ),
-					typ.Version,
+					Group:   ResourceGroup(&s.schema),
+					Version: s.schema.Version,
 				},
 			}
-			cs[apiVersion(&typ)] = rc
+			cs[apiVersion(&s.schema)] = rc
 		}
 		rc.descriptor = append(rc.descriptor, typ)
 		rc.types = append(rc.types, &s)
@@ -172,145 +168,31 @@ func NewClient(config string, context string, descriptor model.ConfigDescriptor,
 	return out, nil
 }
 
-// RegisterResources sends a request to create CRDs and waits for them to initialize
-func (cl *Client) RegisterResources() error {
+// RegisterResources creates or updates the CRDs and waits for them to be
+// established. Registering CRDs which already exist updates their schemas, so
+// it can be repeated on upgrades. The versions other than the storage one are
+// served through the conversion webhook if not nil.
+func (cl *Client) RegisterResources(conversion *ConversionWebhook) error {
 	for k, rc := range cl.clientset {
 		log.Infof("registering for apiVersion %v", k)
-		if err := rc.registerResources(); err != nil {
+		if err := RegisterCustomResourceDefinitions(rc.restconfig, rc.descriptor, conversion); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (rc *restClient) registerResources() error {
-	cs, err := apiextensionsclient.NewForConfig(rc.restconfig)
-	if err != nil {
-		return err
-	}
-
-	skipCreate := true
-	for _, schema := range rc.descriptor {
-		name := ResourceName(schema.Plural) + "." + ResourceGroup(&schema)
-		crd, errGet := cs.ApiextensionsV1beta1().CustomResourceDefinitions().Get(name, meta_v1.GetOptions{})
-		if errGet != nil {
-			skipCreate = false
-			break // create the resources
-		}
-		for _, cond := range crd.Status.Conditions {
-			if cond.Type == apiextensionsv1beta1.Established &&
-				cond.Status == apiextensionsv1beta1.ConditionTrue {
-				continue
-			}
-
-			if cond.Type == apiextensionsv1beta1.NamesAccepted &&
-				cond.Status == apiextensionsv1beta1.ConditionTrue {
-				continue
-			}
-
-			log.Warnf("Not established: %v", name)
-			skipCreate = false
-			break
-		}
-	}
-
-	if skipCreate {
-		return nil
-	}
-
-	for _, schema := range rc.descriptor {
-		g := ResourceGroup(&schema)
-		name := ResourceName(schema.Plural) + "." + g
-		crdScope := apiextensionsv1beta1.NamespaceScoped
-		if schema.ClusterScoped {
-			crdScope = apiextensionsv1beta1.ClusterScoped
-		}
-		crd := &apiextensionsv1beta1.CustomResourceDefinition{
-			ObjectMeta: meta_v1.ObjectMeta{
-				Name: name,
-			},
-			Spec: apiextensionsv1beta1.CustomResourceDefinitionSpec{
-				Group:   g,
-				Version: schema.Version,
-				Scope:   crdScope,
-				Names: apiextensionsv1beta1.CustomResourceDefinitionNames{
-					Plural: ResourceName(schema.Plural),
-					Kind:   KabobCaseToCamelCase(schema.Type),
-				},
-			},
-		}
-		log.Infof("registering CRD %q", name)
-		_, err = cs.ApiextensionsV1beta1().CustomResourceDefinitions().Create(crd)
-		if err != nil && !apierrors.IsAlreadyExists(err) {
-			return err
-		}
-	}
-
-	// wait for CRD being established
-	errPoll := wait.Poll(500*time.Millisecond, 60*time.Second, func() (bool, error) {
-	descriptor:
-		for _, schema := range rc.descriptor {
-			name := ResourceName(schema.Plural) + "." + ResourceGroup(&schema)
-			crd, errGet := cs.ApiextensionsV1beta1().CustomResourceDefinitions().Get(name, meta_v1.GetOptions{})
-			if errGet != nil {
-				return false, errGet
-			}
-			for _, cond := range crd.Status.Conditions {
-				switch cond.Type {
-				case apiextensionsv1beta1.Established:
-					if cond.Status == apiextensionsv1beta1.ConditionTrue {
-						log.Infof("established CRD %q", name)
-						continue descriptor
-					}
-				case apiextensionsv1beta1.NamesAccepted:
-					if cond.Status == apiextensionsv1beta1.ConditionFalse {
-						log.Warnf("name conflict: %v", cond.Reason)
-					}
-				}
-			}
-			log.Infof("missing status condition for %q", name)
-			return false, nil
-		}
-		return true, nil
-	})
-
-	if errPoll != nil {
-		deleteErr := rc.deregisterResources()
-		if deleteErr != nil {
-			return multierror.Append(errPoll, deleteErr)
-		}
-		return errPoll
-	}
-
-	return nil
-}
-
 // DeregisterResources removes third party resources
 func (cl *Client) DeregisterResources() error {
 	for k, rc := range cl.clientset {
 		log.Infof("deregistering for apiVersion %s", k)
-		if err := rc.deregisterResources(); err != nil {
+		if err := DeregisterCustomResourceDefinitions(rc.restconfig, rc.descriptor); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (rc *restClient) deregisterResources() error {
-	cs, err := apiextensionsclient.NewForConfig(rc.restconfig)
-	if err != nil {
-		return err
-	}
-
-	var errs error
-	for _, schema := range rc.descriptor {
-		name := ResourceName(schema.Plural) + "." + ResourceGroup(&schema)
-		err := cs.ApiextensionsV1beta1().CustomResourceDefinitions().Delete(name, nil)
-		errs = multierror.Append(errs, err)
-	}
-	return errs
-}
-
 // ConfigDescriptor for the store
 func (cl *Client) ConfigDescriptor() model.ConfigDescriptor {
 	d := make(model.ConfigDescriptor, 0, len(cl.clientset))
@@ -320,6 +202,15 @@ func (cl *Client) ConfigDescriptor() model.ConfigDescriptor {
 	return d
 }
 
+// resourceNamespace returns the namespace of the requests for the objects of
+// the type, none for cluster-scoped types
+func resourceNamespace(schema model.ProtoSchema, namespace string) string {
+	if schema.ClusterScoped {
+		return ""
+	}
+	return namespace
+}
+
 // Get implements store interface
 func (cl *Client) Get(typ, name, namespace string) (*model.Config, bool) {
 	s, ok := knownTypes[typ]
@@ -341,7 +232,7 @@ func (cl *Client) Get(typ, name, namespace string) (*model.Config, bool) {
 
 	config := s.object.DeepCopyObject().(IstioObject)
 	err := rc.dynamic.Get().
-		Namespace(namespace).
+		Namespace(resourceNamespace(schema, namespace)).
 		Resource(ResourceName(schema.Plural)).
 		Name(name).
 		Do().Into(config)
@@ -447,7 +338,7 @@ func (cl *Client) Delete(typ, name, namespace string) error {
 	}
 
 	return rc.dynamic.Delete().
-		Namespace(namespace).
+		Namespace(resourceNamespace(schema, namespace)).
 		Resource(ResourceName(schema.Plural)).
 		Name(name).
 		Do().Error()
@@ -470,7 +361,7 @@ func (cl *Client) List(typ, namespace string) ([]model.Config, error) {
 
 	list := knownTypes[schema.Type].collection.DeepCopyObject().(IstioObjectList)
 	errs := rc.dynamic.Get().
-		Namespace(namespace).
+		Namespace(resourceNamespace(schema, namespace)).
 		Resource(ResourceName(schema.Plural)).
 		Do().Into(list)
 
@@ -65,10 +65,11 @@ func NewController(client *Client, options kube.ControllerOptions) model.ConfigS
 }
 
 func (c *controller) addInformer(schema model.ProtoSchema, namespace string, resyncPeriod time.Duration) {
+	storage := knownTypes[schema.Type].schema
 	c.kinds[schema.Type] = c.createInformer(knownTypes[schema.Type].object.DeepCopyObject(), schema.Type, resyncPeriod,
 		func(opts meta_v1.ListOptions) (result runtime.Object, err error) {
 			result = knownTypes[schema.Type].collection.DeepCopyObject()
-			rc, ok := c.client.clientset[apiVersion(&schema)]
+			rc, ok := c.client.clientset[apiVersion(&storage)]
 			if !ok {
 				return nil, fmt.Errorf("client not initialized %s", schema.Type)
 			}
@@ -83,7 +84,7 @@ func (c *controller) addInformer(schema model.ProtoSchema, namespace string, res
 			return
 		},
 		func(opts meta_v1.ListOptions) (watch.Interface, error) {
-			rc, ok := c.client.clientset[apiVersion(&schema)]
+			rc, ok := c.client.clientset[apiVersion(&storage)]
 			if !ok {
 				return nil, fmt.Errorf("client not initialized %s", schema.Type)
 			}
@@ -152,7 +153,7 @@ func (c *controller) RegisterEventHandler(typ string, f func(model.Config, model
 	c.kinds[typ].handler.Append(func(object interface{}, ev model.Event) error {
 		item, ok := object.(IstioObject)
 		if ok {
-			config, err := crd.ConvertObject(schema, item, c.client.domainSuffix)
+			config, err := ConvertObject(schema, item, c.client.domainSuffix)
 			if err != nil {
 				log.Warnf("error translating object for schema %#v : %v\n Object:\n%#v", schema, err, object)
 			} else {
@@ -195,7 +196,7 @@ func (c *controller) Get(typ, name, namespace string) (*model.Config, bool) {
 	}
 
 	store := c.kinds[typ].informer.GetStore()
-	data, exists, err := store.GetByKey(kube.KeyFunc(name, namespace))
+	data, exists, err := store.GetByKey(kube.KeyFunc(name, resourceNamespace(schema, namespace)))
 	if !exists {
 		return nil, false
 	}
@@ -210,7 +211,7 @@ func (c *controller) Get(typ, name, namespace string) (*model.Config, bool) {
 		return nil, false
 	}
 
-	config, err := crd.ConvertObject(schema, obj, c.client.domainSuffix)
+	config, err := ConvertObject(schema, obj, c.client.domainSuffix)
 	if err != nil {
 		return nil, false
 	}
@@ -251,11 +252,11 @@ func (c *controller) List(typ, namespace string) ([]model.Config, error) {
 			continue
 		}
 
-		if namespace != "" && namespace != item.GetObjectMeta().Namespace {
+		if ns := resourceNamespace(schema, namespace); ns != "" && ns != item.GetObjectMeta().Namespace {
 			continue
 		}
 
-		config, err := crd.ConvertObject(schema, item, c.client.domainSuffix)
+		config, err := ConvertObject(schema, item, c.client.domainSuffix)
 		if err != nil {
 			key := item.GetObjectMeta().Namespace + "/" + item.GetObjectMeta().Name
 			log.Errorf("Failed to convert %s object, ignoring: %s %v %v", typ, key, err, item.GetSpec())
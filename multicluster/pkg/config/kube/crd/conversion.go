@@ -23,9 +23,15 @@ import (
 )
 
 // ConvertObject converts an IstioObject k8s-style object to the
-// internal configuration model.
+// internal configuration model. The spec of the object is parsed with the
+// schema of its apiVersion, v1alpha1 when unset, and converted to the
+// version of the given schema.
 func ConvertObject(schema model.ProtoSchema, object IstioObject, domain string) (*model.Config, error) {
-	data, err := schema.FromJSONMap(object.GetSpec())
+	if err := checkRemovedFields(schema, object.GetSpec()); err != nil {
+		return nil, err
+	}
+	specSchema := objectSchema(schema, object)
+	data, err := specSchema.FromJSONMap(object.GetSpec())
 	if err != nil {
 		return nil, err
 	}
@@ -38,11 +44,11 @@ func ConvertObject(schema model.ProtoSchema, object IstioObject, domain string)
 	//	domain = "svc." + domain
 	//}
 
-	return &model.Config{
+	config := model.Config{
 		ConfigMeta: model.ConfigMeta{
 			Type:              schema.Type,
 			Group:             ResourceGroup(&schema),
-			Version:           schema.Version,
+			Version:           specSchema.Version,
 			Name:              meta.Name,
 			Namespace:         meta.Namespace,
 			Domain:            domain,
@@ -52,17 +58,61 @@ func ConvertObject(schema model.ProtoSchema, object IstioObject, domain string)
 			CreationTimestamp: meta.CreationTimestamp,
 		},
 		Spec: data,
-	}, nil
+	}
+	if schema.Version == specSchema.Version {
+		return &config, nil
+	}
+	if config, err = multiclustermodel.ConfigFromV1alpha1(config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// checkRemovedFields rejects the fields the Multi-cluster configs no longer
+// have with the reason, rather than as unknown fields
+func checkRemovedFields(schema model.ProtoSchema, spec map[string]interface{}) error {
+	if schema.Type != multiclustermodel.RemoteServiceBinding.Type {
+		return nil
+	}
+	remotes, _ := spec["remote"].([]interface{})
+	for i, r := range remotes {
+		if remote, ok := r.(map[string]interface{}); ok && remote["priority"] != nil {
+			return fmt.Errorf("remote[%d].priority is not supported: the Istio version supported cannot fail "+
+				"over between the clusters of a binding, the requests are split among them by weight", i)
+		}
+	}
+	return nil
 }
 
-// ConvertConfig translates Istio config to k8s config JSON
+// objectSchema returns the schema of the type of the given schema matching
+// the apiVersion of the object
+func objectSchema(schema model.ProtoSchema, object IstioObject) model.ProtoSchema {
+	version := object.GetObjectKind().GroupVersionKind().Version
+	if version == schema.Version {
+		return schema
+	}
+	if storage, exists := multiclustermodel.V1alpha1ConfigTypes.GetByType(schema.Type); exists &&
+		(version == "" || version == storage.Version) {
+		return storage
+	}
+	return schema
+}
+
+// ConvertConfig translates Istio config to k8s config JSON of the storage
+// version of its type, v1alpha1
 func ConvertConfig(schema model.ProtoSchema, config model.Config) (IstioObject, error) {
+	config, err := multiclustermodel.ConfigToV1alpha1(config)
+	if err != nil {
+		return nil, err
+	}
 	spec, err := model.ToJSONMap(config.Spec)
 	if err != nil {
 		return nil, err
 	}
 	namespace := config.Namespace
-	if namespace == "" {
+	if schema.ClusterScoped {
+		namespace = ""
+	} else if namespace == "" {
 		namespace = meta_v1.NamespaceDefault
 	}
 	out := knownTypes[schema.Type].object.DeepCopyObject().(IstioObject)
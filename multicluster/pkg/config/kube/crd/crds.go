@@ -0,0 +1,360 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ghodss/yaml"
+	multierror "github.com/hashicorp/go-multierror"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/log"
+)
+
+// crdGroupVersion is the API of the CustomResourceDefinitions
+var crdGroupVersion = schema.GroupVersion{Group: "apiextensions.k8s.io", Version: "v1"}
+
+// printerColumn is an additional column shown by `kubectl get`
+type printerColumn struct {
+	name     string
+	typ      string
+	jsonPath string
+}
+
+// crdNames holds the short names and printer columns of the CRD of a type
+type crdNames struct {
+	shortNames []string
+	columns    []printerColumn
+}
+
+// namesOfTypes are the CRD names and columns of the Multi-cluster types
+var namesOfTypes = map[string]crdNames{
+	"service-exposition-policy": {
+		shortNames: []string{"sep"},
+		columns: []printerColumn{
+			{name: "Exposed", typ: "string", jsonPath: ".spec.exposed[*].name"},
+		},
+	},
+	"remote-service-binding": {
+		shortNames: []string{"rsb"},
+		columns: []printerColumn{
+			{name: "Clusters", typ: "string", jsonPath: ".spec.remote[*].cluster"},
+			{name: "Mode", typ: "string", jsonPath: ".spec.mode"},
+			{name: "Connection", typ: "string", jsonPath: ".metadata.labels.connection"},
+		},
+	},
+	"cluster-service-exposition-policy": {
+		shortNames: []string{"csep"},
+		columns: []printerColumn{
+			{name: "Exposed", typ: "string", jsonPath: ".spec.exposed[*].name"},
+		},
+	},
+}
+
+// ConversionWebhook is the webhook the API server calls to convert the
+// objects between the versions of the CRDs
+type ConversionWebhook struct {
+	// Service and Namespace of the K8s Service of the webhook
+	Service   string
+	Namespace string
+
+	// Path the webhook serves the ConversionReviews on
+	Path string
+
+	// CABundle is the PEM of the CA certificates of the webhook
+	CABundle []byte
+}
+
+// CustomResourceDefinitions returns the apiextensions.k8s.io/v1
+// CustomResourceDefinitions of the types of the descriptor. The OpenAPI
+// schemas of the specs are generated from the proto messages of the types.
+// The types of the same group and plural are versions of one definition, the
+// storage version of each type coming first. The API server can only convert
+// the objects of the other versions with the conversion webhook, so they are
+// served only if one is given.
+func CustomResourceDefinitions(descriptor model.ConfigDescriptor, conversion *ConversionWebhook) ([]map[string]interface{}, error) {
+	var out []map[string]interface{}
+	byName := make(map[string]map[string]interface{})
+	for _, s := range withStorageVersions(descriptor) {
+		group := ResourceGroup(&s)
+		plural := ResourceName(s.Plural)
+		name := plural + "." + group
+
+		spec, err := openAPISchema(s.MessageName)
+		if err != nil {
+			return nil, multierror.Prefix(err, fmt.Sprintf("cannot generate the schema of %s:", s.Type))
+		}
+		names := namesOfTypes[s.Type]
+		columns := make([]interface{}, 0, len(names.columns)+1)
+		for _, column := range names.columns {
+			columns = append(columns, map[string]interface{}{
+				"name":     column.name,
+				"type":     column.typ,
+				"jsonPath": column.jsonPath,
+			})
+		}
+		columns = append(columns, map[string]interface{}{
+			"name":     "Age",
+			"type":     "date",
+			"jsonPath": ".metadata.creationTimestamp",
+		})
+		version := map[string]interface{}{
+			"name":    s.Version,
+			"served":  true,
+			"storage": false,
+			"schema": map[string]interface{}{
+				"openAPIV3Schema": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"apiVersion": map[string]interface{}{"type": "string"},
+						"kind":       map[string]interface{}{"type": "string"},
+						"metadata":   map[string]interface{}{"type": "object"},
+						"spec":       spec,
+					},
+				},
+			},
+			"additionalPrinterColumns": columns,
+		}
+
+		if crd, ok := byName[name]; ok {
+			spec := crd["spec"].(map[string]interface{})
+			version["served"] = conversion != nil
+			spec["versions"] = append(spec["versions"].([]interface{}), version)
+			if conversion != nil {
+				spec["conversion"] = conversion.crdConversion()
+			}
+			continue
+		}
+
+		// The first version of a definition is the storage version
+		version["storage"] = true
+		scope := "Namespaced"
+		if s.ClusterScoped {
+			scope = "Cluster"
+		}
+		kind := KabobCaseToCamelCase(s.Type)
+		crdNames := map[string]interface{}{
+			"kind":       kind,
+			"listKind":   kind + "List",
+			"plural":     plural,
+			"singular":   ResourceName(s.Type),
+			"categories": []interface{}{"istio-io", "multicluster-istio-io"},
+		}
+		if len(names.shortNames) > 0 {
+			shortNames := make([]interface{}, len(names.shortNames))
+			for i, shortName := range names.shortNames {
+				shortNames[i] = shortName
+			}
+			crdNames["shortNames"] = shortNames
+		}
+		crd := map[string]interface{}{
+			"apiVersion": crdGroupVersion.String(),
+			"kind":       "CustomResourceDefinition",
+			"metadata": map[string]interface{}{
+				"name": name,
+			},
+			"spec": map[string]interface{}{
+				"group":    group,
+				"names":    crdNames,
+				"scope":    scope,
+				"versions": []interface{}{version},
+			},
+		}
+		byName[name] = crd
+		out = append(out, crd)
+	}
+	return out, nil
+}
+
+// withStorageVersions returns the schemas of the descriptor, each preceded by
+// the schema of the storage version of its type if it is another one
+func withStorageVersions(descriptor model.ConfigDescriptor) model.ConfigDescriptor {
+	out := make(model.ConfigDescriptor, 0, 2*len(descriptor))
+	for _, s := range descriptor {
+		if known, ok := knownTypes[s.Type]; ok && known.schema.Version != s.Version {
+			out = append(out, known.schema)
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// crdConversion returns the conversion of the CRD spec calling the webhook
+func (c *ConversionWebhook) crdConversion() map[string]interface{} {
+	clientConfig := map[string]interface{}{
+		"service": map[string]interface{}{
+			"name":      c.Service,
+			"namespace": c.Namespace,
+			"path":      c.Path,
+		},
+	}
+	if len(c.CABundle) > 0 {
+		clientConfig["caBundle"] = c.CABundle
+	}
+	return map[string]interface{}{
+		"strategy": "Webhook",
+		"webhook": map[string]interface{}{
+			"clientConfig":             clientConfig,
+			"conversionReviewVersions": []interface{}{"v1"},
+		},
+	}
+}
+
+// WriteCustomResourceDefinitions writes the CustomResourceDefinitions of the
+// descriptor as a YAML stream
+func WriteCustomResourceDefinitions(descriptor model.ConfigDescriptor, conversion *ConversionWebhook, writer io.Writer) error {
+	crds, err := CustomResourceDefinitions(descriptor, conversion)
+	if err != nil {
+		return err
+	}
+	for _, crd := range crds {
+		data, err := yaml.Marshal(crd)
+		if err != nil {
+			return err
+		}
+		if _, err = fmt.Fprintf(writer, "---\n%s", data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterCustomResourceDefinitions creates the CustomResourceDefinitions of
+// the descriptor, or updates them to the current schemas if they exist, and
+// waits for them to be established. The versions other than the storage one
+// are served through the conversion webhook if not nil.
+func RegisterCustomResourceDefinitions(config *rest.Config, descriptor model.ConfigDescriptor,
+	conversion *ConversionWebhook) error {
+	client, err := crdRESTClient(config)
+	if err != nil {
+		return err
+	}
+	crds, err := CustomResourceDefinitions(descriptor, conversion)
+	if err != nil {
+		return err
+	}
+
+	for _, crd := range crds {
+		name := crd["metadata"].(map[string]interface{})["name"].(string)
+		existing, err := getCRD(client, name)
+		switch {
+		case apierrors.IsNotFound(err):
+			log.Infof("registering CRD %q", name)
+			err = client.Post().Resource("customresourcedefinitions").Body(mustJSON(crd)).Do().Error()
+		case err == nil:
+			log.Infof("updating CRD %q", name)
+			existingMeta, _ := existing["metadata"].(map[string]interface{})
+			crd["metadata"].(map[string]interface{})["resourceVersion"] = existingMeta["resourceVersion"]
+			err = client.Put().Resource("customresourcedefinitions").Name(name).Body(mustJSON(crd)).Do().Error()
+		}
+		if err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("cannot register CRD %q:", name))
+		}
+	}
+
+	// wait for CRD being established
+	return wait.Poll(500*time.Millisecond, 60*time.Second, func() (bool, error) {
+		for _, crd := range crds {
+			name := crd["metadata"].(map[string]interface{})["name"].(string)
+			existing, err := getCRD(client, name)
+			if err != nil {
+				return false, err
+			}
+			if !crdCondition(existing, "Established") {
+				log.Infof("missing status condition for %q", name)
+				return false, nil
+			}
+			log.Infof("established CRD %q", name)
+		}
+		return true, nil
+	})
+}
+
+// DeregisterCustomResourceDefinitions deletes the CustomResourceDefinitions
+// of the descriptor
+func DeregisterCustomResourceDefinitions(config *rest.Config, descriptor model.ConfigDescriptor) error {
+	client, err := crdRESTClient(config)
+	if err != nil {
+		return err
+	}
+	var errs error
+	for _, s := range descriptor {
+		name := ResourceName(s.Plural) + "." + ResourceGroup(&s)
+		err := client.Delete().Resource("customresourcedefinitions").Name(name).Do().Error()
+		if err != nil && !apierrors.IsNotFound(err) {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs
+}
+
+// crdRESTClient returns a client of the CustomResourceDefinitions API. The
+// definitions are handled as JSON objects as the apiextensions.k8s.io/v1
+// types are not available in the vendored client.
+func crdRESTClient(config *rest.Config) (*rest.RESTClient, error) {
+	cfg := rest.CopyConfig(config)
+	cfg.GroupVersion = &crdGroupVersion
+	cfg.APIPath = "/apis"
+	cfg.ContentType = runtime.ContentTypeJSON
+	cfg.NegotiatedSerializer = scheme.Codecs
+	if cfg.UserAgent == "" {
+		cfg.UserAgent = rest.DefaultKubernetesUserAgent()
+	}
+	return rest.RESTClientFor(cfg)
+}
+
+func getCRD(client *rest.RESTClient, name string) (map[string]interface{}, error) {
+	data, err := client.Get().Resource("customresourcedefinitions").Name(name).DoRaw()
+	if err != nil {
+		return nil, err
+	}
+	crd := make(map[string]interface{})
+	if err = json.Unmarshal(data, &crd); err != nil {
+		return nil, err
+	}
+	return crd, nil
+}
+
+// crdCondition returns true if the condition of the CRD status is true
+func crdCondition(crd map[string]interface{}, typ string) bool {
+	status, _ := crd["status"].(map[string]interface{})
+	conditions, _ := status["conditions"].([]interface{})
+	for _, c := range conditions {
+		condition, _ := c.(map[string]interface{})
+		if condition["type"] == typ {
+			return condition["status"] == "True"
+		}
+	}
+	return false
+}
+
+func mustJSON(obj interface{}) []byte {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
@@ -0,0 +1,178 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"k8s.io/client-go/rest"
+
+	"istio.io/istio/pilot/test/util"
+
+	mcmodel "github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/model"
+)
+
+const crdsHeader = `# Multi-Cluster CRDs, generated from the API protos.
+# Regenerate with: REFRESH_GOLDEN=true go test ./multicluster/pkg/config/kube/crd
+`
+
+// The manifest of the CRDs in docs/install must match the generated CRDs
+func TestCRDsManifest(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString(crdsHeader)
+	if err := WriteCustomResourceDefinitions(mcmodel.MultiClusterConfigTypes, nil, &buf); err != nil {
+		t.Fatal(err)
+	}
+	util.CompareContent(buf.Bytes(), "../../../../../docs/install/crds.yaml", t)
+}
+
+func TestOpenAPISchema(t *testing.T) {
+	schema, err := openAPISchema(mcmodel.RemoteServiceBinding.MessageName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tt := []struct {
+		path []string
+		want interface{}
+	}{
+		{path: []string{"properties", "remote", "type"}, want: "array"},
+		{path: []string{"properties", "remote", "items", "properties", "cluster", "type"}, want: "string"},
+		{path: []string{"properties", "remote", "items", "properties", "weight", "maximum"}, want: 4294967295},
+		{path: []string{"properties", "mode", "enum"}, want: []interface{}{"REPLACE", "FALLBACK"}},
+		{path: []string{"properties", "remote", "items", "properties", "services", "items", "properties", "policy",
+			"properties", "retries", "properties", "perTryTimeout", "type"}, want: "string"},
+	}
+
+	for _, tc := range tt {
+		var got interface{} = schema
+		for _, key := range tc.path {
+			m, ok := got.(map[string]interface{})
+			if !ok {
+				got = nil
+				break
+			}
+			got = m[key]
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", strings.Join(tc.path, "."), got, tc.want)
+		}
+	}
+}
+
+// fakeCRDServer is an API server serving the CustomResourceDefinitions,
+// established as soon as they are created
+type fakeCRDServer struct {
+	mu       sync.Mutex
+	crds     map[string]map[string]interface{}
+	requests []string
+}
+
+func (s *fakeCRDServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, req.Method)
+
+	const prefix = "/apis/apiextensions.k8s.io/v1/customresourcedefinitions"
+	if !strings.HasPrefix(req.URL.Path, prefix) {
+		http.NotFound(w, req)
+		return
+	}
+	name := strings.TrimPrefix(strings.TrimPrefix(req.URL.Path, prefix), "/")
+
+	switch req.Method {
+	case http.MethodGet:
+		crd, ok := s.crds[name]
+		if !ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"kind": "Status", "apiVersion": "v1", "status": "Failure", "reason": "NotFound", "code": 404}`) // nolint: errcheck
+			return
+		}
+		writeJSON(w, crd)
+	case http.MethodPost, http.MethodPut:
+		crd := make(map[string]interface{})
+		body, _ := ioutil.ReadAll(req.Body)
+		if err := json.Unmarshal(body, &crd); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		metadata := crd["metadata"].(map[string]interface{})
+		if req.Method == http.MethodPut && metadata["resourceVersion"] != s.crds[name]["metadata"].(map[string]interface{})["resourceVersion"] {
+			http.Error(w, "conflict", http.StatusConflict)
+			return
+		}
+		metadata["resourceVersion"] = fmt.Sprintf("%d", len(s.requests))
+		crd["status"] = map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{"type": "Established", "status": "True"}},
+		}
+		s.crds[metadata["name"].(string)] = crd
+		writeJSON(w, crd)
+	default:
+		http.Error(w, "unsupported", http.StatusMethodNotAllowed)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(obj) // nolint: errcheck
+}
+
+func TestRegisterCustomResourceDefinitions(t *testing.T) {
+	fake := &fakeCRDServer{crds: make(map[string]map[string]interface{})}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	config := &rest.Config{Host: server.URL}
+
+	// The first registration creates the CRDs, the next ones update them
+	tt := []struct {
+		want string
+	}{
+		{want: "GET POST GET POST GET POST GET GET GET"},
+		{want: "GET PUT GET PUT GET PUT GET GET GET"},
+	}
+	for i, tc := range tt {
+		fake.requests = nil
+		if err := RegisterCustomResourceDefinitions(config, mcmodel.MultiClusterConfigTypes, nil); err != nil {
+			t.Fatalf("registration %d: %v", i, err)
+		}
+		if got := strings.Join(fake.requests, " "); got != tc.want {
+			t.Errorf("registration %d: got requests %q, want %q", i, got, tc.want)
+		}
+		if len(fake.crds) != len(mcmodel.MultiClusterConfigTypes) {
+			t.Errorf("registration %d: got %d CRDs, want %d", i, len(fake.crds), len(mcmodel.MultiClusterConfigTypes))
+		}
+	}
+
+	if _, ok := fake.crds["remoteservicebindings.multicluster.istio.io"]; !ok {
+		t.Errorf("the RemoteServiceBinding CRD was not registered")
+	}
+	csep, ok := fake.crds["clusterserviceexpositionpolicies.multicluster.istio.io"]
+	if !ok {
+		t.Fatalf("the ClusterServiceExpositionPolicy CRD was not registered")
+	}
+	if scope := csep["spec"].(map[string]interface{})["scope"]; scope != "Cluster" {
+		t.Errorf("the ClusterServiceExpositionPolicy CRD has scope %v, want Cluster", scope)
+	}
+}
@@ -21,8 +21,6 @@ import (
 	"os"
 	"testing"
 
-	multierror "github.com/hashicorp/go-multierror"
-
 	istiocrd "istio.io/istio/pilot/pkg/config/kube/crd"
 	"istio.io/istio/pilot/pkg/config/memory"
 	istiomodel "istio.io/istio/pilot/pkg/model"
@@ -33,8 +31,7 @@ import (
 	mcmodel "github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/model"
 
 	kube_v1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/serializer"
+	kubeyaml "k8s.io/apimachinery/pkg/util/yaml"
 )
 
 func TestBindingToDirectIngressConfiguration(t *testing.T) {
@@ -61,6 +58,10 @@ func TestBindingToDirectIngressConfiguration(t *testing.T) {
 			in:    "reviews-exposure-both.yaml",
 			out:   "reviews-directingress-exposure.yaml",
 			store: "reviews-exposure-starter.yaml"},
+		{config: "cluster_a.yaml",
+			in:    "reviews-exposure-bookinfo.yaml",
+			out:   "reviews-directingress-exposure-bookinfo.yaml",
+			store: "reviews-exposure-starter-bookinfo.yaml"},
 		{config: "cluster_a.yaml",
 			in:  "reviews-binding-three-versions.yaml",
 			out: "reviews-binding-three-versions.yaml"},
@@ -70,6 +71,49 @@ func TestBindingToDirectIngressConfiguration(t *testing.T) {
 		{config: "cluster_b_listens_cd.yaml",
 			in:  "ratings-binding-both-cd.yaml",
 			out: "ratings-binding-both-cd.yaml"},
+		{config: "cluster_a_mc_gateways.yaml",
+			in:  "ratings-binding.yaml",
+			out: "ratings-binding-mc-gateways.yaml"},
+		{config: "cluster1_trust_domains.yaml",
+			in:  "reviews-binding.yaml",
+			out: "reviews-directingress-binding-istio-mutual.yaml"},
+		{config: "cluster1_trust_domains.yaml",
+			in:  "reviews-binding-both-clusters-mutual.yaml",
+			out: "reviews-directingress-binding-both-clusters-mutual.yaml"},
+		{config: "cluster_a_mc_gateways.yaml",
+			in:    "reviews-exposure-both.yaml",
+			out:   "reviews-directingress-exposure-mc-gateways.yaml",
+			store: "reviews-exposure-starter.yaml"},
+		{config: "cluster_b_listens_cd.yaml",
+			in:  "ratings-binding-weighted.yaml",
+			out: "ratings-binding-weighted.yaml"},
+		{config: "cluster_b_listens_cd.yaml",
+			in:       "ratings-binding-fallback.yaml",
+			svcStore: "ratings-local-service.yaml",
+			out:      "ratings-binding-fallback.yaml"},
+		{config: "cluster_b_listens_cd.yaml",
+			in:  "ratings-binding-fallback.yaml",
+			out: "ratings-binding-fallback-no-local.yaml"},
+		{config: "cluster_b_listens_cd.yaml",
+			in:  "ratings-binding-route-policy.yaml",
+			out: "ratings-binding-route-policy.yaml"},
+		{config: "cluster_b_listens_cd.yaml",
+			in:  "ratings-binding-multi-port.yaml",
+			out: "ratings-binding-multi-port.yaml"},
+		{config: "cluster_a.yaml",
+			in:  "reviews-exposure-multi-port.yaml",
+			out: "reviews-directingress-exposure-multi-port.yaml"},
+		{config: "cluster_a.yaml",
+			in:       "bookinfo-exposure-selector.yaml",
+			svcStore: "bookinfo-local-services.yaml",
+			out:      "bookinfo-directingress-exposure-selector.yaml"},
+		{config: "cluster1_trust_domains.yaml",
+			in:  "exposure-authorization.yaml",
+			out: "directingress-exposure-authorization.yaml"},
+		{config: "cluster_a.yaml",
+			in:       "cluster-exposure.yaml",
+			svcStore: "cluster-exposure-local-services.yaml",
+			out:      "cluster-directingress-exposure.yaml"},
 	}
 
 	for _, tc := range tt {
@@ -103,16 +147,22 @@ func TestBindingToDirectIngressConfiguration(t *testing.T) {
 			}
 
 			var svcStore []kube_v1.Service
+			var namespaces []kube_v1.Namespace
 			if tc.svcStore != "" {
 				svcStore, err = createTestServiceStoreFromFile("../../../test/expose-binding/" + tc.svcStore)
 				if err != nil {
 					t.Fatal(err)
 				}
+				namespaces, err = createTestNamespacesFromFile("../../../test/expose-binding/" + tc.svcStore)
+				if err != nil {
+					t.Fatal(err)
+				}
 			} else {
 				svcStore = make([]kube_v1.Service, 0)
 			}
 
-			if err := readAndConvertDirectIngress(in, out, clusterConfig, store, svcStore); err != nil {
+			if err := readAndConvert(mcmodel.DirectIngressStyle, in, out, clusterConfig, store, svcStore,
+				namespaces); err != nil {
 				t.Fatalf("Unexpected error converting configs: %v", err)
 			}
 
@@ -121,70 +171,6 @@ func TestBindingToDirectIngressConfiguration(t *testing.T) {
 	}
 }
 
-// readAndConvertDirectIngress converts a .yaml file of ServiceExposurePolicy and RemoteServiceBinding to Istio config .yaml file
-func readAndConvertDirectIngress(reader io.Reader, writer io.Writer, clusterConfig *agent.ClusterConfig, store istiomodel.ConfigStore, svcStore []kube_v1.Service) error { // nolint: lll
-	configs, err := readConfigs(reader)
-	if err != nil {
-		return err
-	}
-
-	// Ensure every input config is valid
-	mcDescriptor := istiomodel.ConfigDescriptor{
-		mcmodel.ServiceExpositionPolicy,
-		mcmodel.RemoteServiceBinding,
-	}
-	for _, config := range configs {
-		schema, exists := mcDescriptor.GetByType(config.Type)
-		if !exists {
-			continue
-		}
-		err = schema.Validate(config.Name, config.Namespace, config.Spec)
-		if err != nil {
-			return multierror.Prefix(err, "input validation failure")
-		}
-	}
-
-	istioConfigs, svcs, err := mcmodel.ConvertBindingsAndExposuresDirectIngress(configs, clusterConfig, store, svcStore)
-	if err != nil {
-		return err
-	}
-
-	configDescriptor := istiomodel.ConfigDescriptor{
-		istiomodel.VirtualService,
-		istiomodel.Gateway,
-		istiomodel.DestinationRule,
-		istiomodel.ServiceEntry,
-	}
-
-	// Ensure every generated config is valid
-	for _, istioConfig := range istioConfigs {
-		schema, exists := configDescriptor.GetByType(istioConfig.Type)
-		if !exists {
-			continue // Don't validate generated K8s config (the Service)
-		}
-
-		err = schema.Validate(istioConfig.Name, istioConfig.Namespace, istioConfig.Spec)
-		if err != nil {
-			return multierror.Prefix(err, "validation failure")
-		}
-	}
-
-	err = writeIstioYAMLOutput(configDescriptor, istioConfigs, writer)
-	if err != nil {
-		return multierror.Prefix(err, "couldn't write yaml")
-	}
-
-	if len(svcs) > 0 {
-		writer.Write([]byte("---\n")) // nolint: errcheck
-		err = writeK8sYAMLOutput(svcs, writer)
-		if err != nil {
-			return multierror.Prefix(err, "couldn't write yaml")
-		}
-	}
-
-	return nil
-}
-
 func createTestConfigStoreFromFile(fname string) (istiomodel.ConfigStore, error) {
 	configs := []istiomodel.Config{}
 
@@ -223,6 +209,17 @@ func createTestServiceStoreFromFile(fname string) ([]kube_v1.Service, error) {
 	return svcs, nil
 }
 
+// createTestNamespacesFromFile reads the namespaces of a file of K8s Services
+func createTestNamespacesFromFile(fname string) ([]kube_v1.Namespace, error) {
+	reader, err := os.Open(fname)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close() // nolint: errcheck
+
+	return readK8sNamespaces(reader)
+}
+
 func createTestConfigStore(configs []istiomodel.Config) (istiomodel.ConfigStore, error) {
 	out := memory.Make(istiomodel.IstioConfigTypes)
 	for _, config := range configs {
@@ -252,27 +249,50 @@ func readIstioConfigs(reader io.Reader) ([]istiomodel.Config, error) {
 func readK8sServices(reader io.Reader) ([]kube_v1.Service, error) {
 	outSvcs := make([]kube_v1.Service, 0)
 
-	data, err := ioutil.ReadAll(reader)
-	if err != nil {
-		return nil, err
-	}
+	// The Services are stored as a YAML stream
+	yamlDecoder := kubeyaml.NewYAMLOrJSONDecoder(reader, 512*1024)
+	for {
+		svc := kube_v1.Service{}
+		err := yamlDecoder.Decode(&svc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
 
-	runtimeScheme := runtime.NewScheme()
-	codecs := serializer.NewCodecFactory(runtimeScheme)
-	deserializer := codecs.UniversalDeserializer()
-	obj, _, err := deserializer.Decode(data, nil, nil)
-	if err != nil {
-		return nil, err
+		switch svc.Kind {
+		case "":
+			// An empty document
+		case "Service":
+			outSvcs = append(outSvcs, svc)
+		case "Namespace":
+			// Read by readK8sNamespaces
+		default:
+			fmt.Printf("Unexpected Kubernetes type %v\n", svc.Kind)
+		}
 	}
 
-	// now use switch over the type of the object
-	// and match each type-case
-	switch o := obj.(type) {
-	case *kube_v1.Service:
-		outSvcs = append(outSvcs, *o)
-	default:
-		fmt.Printf("Unexpected Kubernetes type %v\n", o)
+	return outSvcs, nil
+}
+
+func readK8sNamespaces(reader io.Reader) ([]kube_v1.Namespace, error) {
+	out := make([]kube_v1.Namespace, 0)
+
+	yamlDecoder := kubeyaml.NewYAMLOrJSONDecoder(reader, 512*1024)
+	for {
+		ns := kube_v1.Namespace{}
+		err := yamlDecoder.Decode(&ns)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if ns.Kind == "Namespace" {
+			out = append(out, ns)
+		}
 	}
 
-	return outSvcs, nil
+	return out, nil
 }
@@ -18,15 +18,22 @@ import (
 	"os"
 	"testing"
 
-	"github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/agent"
+	istiomodel "istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pilot/test/util"
+
+	"github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/agent"
+	mcmodel "github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/model"
+
+	kube_v1 "k8s.io/api/core/v1"
 )
 
 func TestBindingToConfigurationEgressIngress(t *testing.T) {
 	tt := []struct {
-		config string // Config of binding cluster
-		in     string
-		out    string
+		config   string // Config of binding cluster
+		in       string // Filename of SEP and RSBs
+		store    string // Filename for baseline Istio configuration for merging
+		svcStore string // Filename for baseline Kuberentes services configuration for merging
+		out      string // Filename for generated Istio configuration
 	}{
 		{config: "cluster1.yaml",
 			in:  "sample-binding.yaml",
@@ -40,10 +47,81 @@ func TestBindingToConfigurationEgressIngress(t *testing.T) {
 		{config: "cluster_a.yaml",
 			in:  "rshriram-demo-exposure.yaml",
 			out: "rshriram-demo-exposure.yaml"},
+		{config: "cluster_a_mc_gateways.yaml",
+			in:  "sample-binding.yaml",
+			out: "sample-binding-mc-gateways.yaml"},
+		{config: "cluster_a_mc_gateways.yaml",
+			in:  "sample-exposure.yaml",
+			out: "sample-exposure-mc-gateways.yaml"},
+		{config: "cluster_a.yaml",
+			in:  "ratings-binding.yaml",
+			out: "egressingress-ratings-binding.yaml"},
+		{config: "cluster_b_listens_cd.yaml",
+			in:  "ratings-binding-both-cd.yaml",
+			out: "egressingress-ratings-binding-both-cd.yaml"},
+		{config: "cluster_b_listens_cd.yaml",
+			in:  "ratings-binding-route-policy.yaml",
+			out: "egressingress-ratings-binding-route-policy.yaml"},
+		{config: "cluster_a.yaml",
+			in:  "ratings-exposure.yaml",
+			out: "egressingress-ratings-exposure.yaml"},
+		{config: "cluster1.yaml",
+			in:  "reviews-binding.yaml",
+			out: "egressingress-reviews-binding.yaml"},
+		{config: "cluster1_trust_domains.yaml",
+			in:  "reviews-binding-both-clusters-mutual.yaml",
+			out: "egressingress-reviews-binding-both-clusters-mutual.yaml"},
+		{config: "cluster1.yaml",
+			in:  "reviews-binding-ratings-only.yaml",
+			out: "egressingress-reviews-binding-ratings-only.yaml"},
+		{config: "cluster_a.yaml",
+			in:  "reviews-binding-three-versions.yaml",
+			out: "egressingress-reviews-binding-three-versions.yaml"},
+		{config: "cluster1.yaml",
+			in:  "reviews-binding-v1-only.yaml",
+			out: "egressingress-reviews-binding-v1-only.yaml"},
+		{config: "cluster1.yaml",
+			in:    "reviews-binding.yaml",
+			out:   "egressingress-reviews-binding-shared.yaml",
+			store: "egressingress-reviews-binding-shared-starter.yaml"},
+		{config: "cluster_a.yaml",
+			in:    "reviews-exposure.yaml",
+			out:   "egressingress-reviews-exposure.yaml",
+			store: "reviews-exposure-starter.yaml"},
+		{config: "cluster_a.yaml",
+			in:    "reviews-exposure-both.yaml",
+			out:   "egressingress-reviews-exposure-both.yaml",
+			store: "reviews-exposure-starter.yaml"},
+		{config: "cluster_a.yaml",
+			in:    "reviews-exposure-bookinfo.yaml",
+			out:   "egressingress-reviews-exposure-bookinfo.yaml",
+			store: "reviews-exposure-starter-bookinfo.yaml"},
+		{config: "cluster_a.yaml",
+			in:    "reviews-exposure-cluster1.yaml",
+			out:   "egressingress-reviews-exposure-cluster1.yaml",
+			store: "reviews-exposure-starter.yaml"},
+		{config: "cluster_a.yaml",
+			in:    "reviews-exposure-v1-only.yaml",
+			out:   "egressingress-reviews-exposure-v1-only.yaml",
+			store: "reviews-exposure-starter.yaml"},
+		{config: "cluster_a.yaml",
+			in:    "reviews-exposure-v1-v2.yaml",
+			out:   "egressingress-reviews-exposure-v1-v2.yaml",
+			store: "reviews-exposure-starter-v1-v2.yaml"},
+		{config: "cluster_a.yaml",
+			in:    "reviews-exposure-v1-widened.yaml",
+			out:   "egressingress-reviews-exposure-v1-widened.yaml",
+			store: "reviews-exposure-starter.yaml"},
+		{config: "cluster_a.yaml",
+			in:  "multi-port-exposure.yaml",
+			out: "egressingress-multi-port-exposure.yaml"},
+		{config: "cluster_a.yaml",
+			in:  "cassandra-exposure.yaml",
+			out: "egressingress-cassandra-exposure.yaml"},
 	}
 
 	for _, tc := range tt {
-		t.Run(tc.in, func(t *testing.T) {
+		t.Run(tc.out, func(t *testing.T) {
 			clusterConfig, err := agent.LoadConfig("../../../test/mc-agent/" + tc.config)
 			if err != nil {
 				t.Fatal(err)
@@ -62,7 +140,23 @@ func TestBindingToConfigurationEgressIngress(t *testing.T) {
 			}
 			defer out.Close() // nolint: errcheck
 
-			if err := readAndConvert(in, out, clusterConfig); err != nil {
+			store, err := createTestConfigStore([]istiomodel.Config{})
+			if tc.store != "" {
+				store, err = createTestConfigStoreFromFile("../../../test/expose-binding/" + tc.store)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			svcStore := make([]kube_v1.Service, 0)
+			if tc.svcStore != "" {
+				svcStore, err = createTestServiceStoreFromFile("../../../test/expose-binding/" + tc.svcStore)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := readAndConvert(mcmodel.EgressIngressStyle, in, out, clusterConfig, store, svcStore, nil); err != nil {
 				t.Fatalf("Unexpected error converting configs: %v", err)
 			}
 
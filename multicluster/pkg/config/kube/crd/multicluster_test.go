@@ -15,11 +15,13 @@
 package crd
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/ghodss/yaml"
@@ -42,18 +44,17 @@ func TestParseYaml(t *testing.T) {
 		outtypes []string
 	}{
 		{in: "ratings-exposure.yaml",
-			outtypes: []string{"*v1alpha1.ServiceExpositionPolicy"}},
+			outtypes: []string{"*v1alpha2.ServiceExpositionPolicy"}},
 		{in: "sample-exposure.yaml",
-			outtypes: []string{"*v1alpha1.ServiceExpositionPolicy"}},
+			outtypes: []string{"*v1alpha2.ServiceExpositionPolicy"}},
 		{in: "ratings-binding.yaml",
-			outtypes: []string{"*v1alpha1.RemoteServiceBinding"}},
+			outtypes: []string{"*v1alpha2.RemoteServiceBinding"}},
 		{in: "sample-binding.yaml",
-			outtypes: []string{"*v1alpha1.RemoteServiceBinding"}},
-		// TODO Enable ports on SEP
-		// {in: "multi-port-exposure.yaml",
-		//	outtypes: []string { "*v1alpha1.ServiceExpositionPolicy" } },
-		// {in: "cassandra-exposure.yaml",
-		//	outtypes: []string { "*v1alpha1.ServiceExpositionPolicy" } },
+			outtypes: []string{"*v1alpha2.RemoteServiceBinding"}},
+		{in: "multi-port-exposure.yaml",
+			outtypes: []string{"*v1alpha2.ServiceExpositionPolicy"}},
+		{in: "cassandra-exposure.yaml",
+			outtypes: []string{"*v1alpha2.ServiceExpositionPolicy", "*v1alpha2.ServiceExpositionPolicy"}},
 	}
 
 	for _, tc := range tt {
@@ -109,9 +110,27 @@ func TestValidation(t *testing.T) {
 	tt := []struct {
 		in       string
 		mustFail bool
+		err      string // Part of the expected error
 	}{
 		{in: "invalid-exposure.yaml",
 			mustFail: true},
+		{in: "invalid-ports-exposure.yaml",
+			mustFail: true},
+		{in: "reviews-exposure-multi-port.yaml"},
+		{in: "ratings-binding-multi-port.yaml"},
+		{in: "invalid-selector-exposure.yaml",
+			mustFail: true},
+		{in: "bookinfo-exposure-selector.yaml"},
+		{in: "invalid-cluster-exposure.yaml",
+			mustFail: true},
+		{in: "cluster-exposure.yaml"},
+		{in: "invalid-clusters-pattern-exposure.yaml",
+			mustFail: true},
+		{in: "clusters-pattern-exposure.yaml"},
+		{in: "bindings-pending-approval.yaml"},
+		{in: "invalid-priority-binding.yaml",
+			mustFail: true,
+			err:      "remote[1].priority is not supported"},
 	}
 
 	for _, tc := range tt {
@@ -126,6 +145,8 @@ func TestValidation(t *testing.T) {
 			if tc.mustFail {
 				if err == nil {
 					t.Errorf("Validated correct; failure expected")
+				} else if !strings.Contains(err.Error(), tc.err) {
+					t.Errorf("Got error %v, want %q", err, tc.err)
 				}
 			} else {
 				if err != nil {
@@ -136,20 +157,71 @@ func TestValidation(t *testing.T) {
 	}
 }
 
-// readAndConvert converts a .yaml file of ServiceExposurePolicy and RemoteServiceBinding to Istio config .yaml file
-func readAndConvert(reader io.Reader, writer io.Writer, clusterConfig *agent.ClusterConfig) error {
+// The bindings awaiting approval are not realized, the approved ones are
+func TestPendingBindingsNotRealized(t *testing.T) {
+	clusterConfig, err := agent.LoadConfig("../../../test/mc-agent/cluster_b_listens_cd.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := createTestConfigStoreFromFile("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile("../../../test/expose-binding/bindings-pending-approval.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	configs, err := readConfigs(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending := mcmodel.ProvenanceAnnotation(configs[0])
+
+	for _, style := range []string{mcmodel.DirectIngressStyle, mcmodel.EgressIngressStyle} {
+		t.Run(style, func(t *testing.T) {
+			conversionStyle, err := mcmodel.GetConversionStyle(style)
+			if err != nil {
+				t.Fatal(err)
+			}
+			istioConfigs, svcs, err := mcmodel.ConvertBindingsAndExposures2(conversionStyle, configs, clusterConfig,
+				store, nil, clusterConfig.ConversionOptions())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(istioConfigs) == 0 || len(svcs) == 0 {
+				t.Errorf("The approved binding was not realized")
+			}
+			for _, config := range istioConfigs {
+				if mcmodel.GetProvenance(config.Annotations).Has(pending) {
+					t.Errorf("%s %s.%s realizes the pending binding", config.Type, config.Namespace, config.Name)
+				}
+			}
+			for _, svc := range svcs {
+				if svc.Name == "ratings" {
+					t.Errorf("K8s Service %s.%s realizes the pending binding", svc.Namespace, svc.Name)
+				}
+			}
+		})
+	}
+}
+
+// readAndConvert converts a .yaml file of ServiceExposurePolicy and RemoteServiceBinding to Istio and
+// Kubernetes config .yaml file using the named conversion style
+func readAndConvert(style string, reader io.Reader, writer io.Writer, clusterConfig *agent.ClusterConfig,
+	store istiomodel.ConfigStore, svcStore []kube_v1.Service, namespaces []kube_v1.Namespace) error {
+	conversionStyle, err := mcmodel.GetConversionStyle(style)
+	if err != nil {
+		return err
+	}
+
 	configs, err := readConfigs(reader)
 	if err != nil {
 		return err
 	}
 
 	// Ensure every input config is valid
-	mcDescriptor := istiomodel.ConfigDescriptor{
-		mcmodel.ServiceExpositionPolicy,
-		mcmodel.RemoteServiceBinding,
-	}
 	for _, config := range configs {
-		schema, exists := mcDescriptor.GetByType(config.Type)
+		schema, exists := mcmodel.MultiClusterConfigTypes.GetByType(config.Type)
 		if !exists {
 			continue
 		}
@@ -159,7 +231,9 @@ func readAndConvert(reader io.Reader, writer io.Writer, clusterConfig *agent.Clu
 		}
 	}
 
-	istioConfigs, err := mcmodel.ConvertBindingsAndExposuresEgressIngress(configs, clusterConfig)
+	opts := clusterConfig.ConversionOptions()
+	opts.ClusterPolicies = &clusterPolicies{namespaces: namespaces, configs: configs, svcs: svcStore}
+	istioConfigs, svcs, err := conversionStyle.Convert(configs, clusterConfig, store, svcStore, opts)
 	if err != nil {
 		return err
 	}
@@ -169,6 +243,8 @@ func readAndConvert(reader io.Reader, writer io.Writer, clusterConfig *agent.Clu
 		istiomodel.Gateway,
 		istiomodel.DestinationRule,
 		istiomodel.ServiceEntry,
+		istiomodel.ServiceRole,
+		istiomodel.ServiceRoleBinding,
 	}
 
 	// Ensure every generated config is valid
@@ -186,7 +262,19 @@ func readAndConvert(reader io.Reader, writer io.Writer, clusterConfig *agent.Clu
 	}
 
 	err = writeIstioYAMLOutput(configDescriptor, istioConfigs, writer)
-	return err
+	if err != nil {
+		return multierror.Prefix(err, "couldn't write yaml")
+	}
+
+	if len(svcs) > 0 {
+		writer.Write([]byte("---\n")) // nolint: errcheck
+		err = writeK8sYAMLOutput(svcs, writer)
+		if err != nil {
+			return multierror.Prefix(err, "couldn't write yaml")
+		}
+	}
+
+	return nil
 }
 
 func configToIstioObj(descriptor istiomodel.ConfigDescriptor, config istiomodel.Config) (IstioObject, error) {
@@ -245,3 +333,24 @@ func writeK8sYAMLOutput(svcs []kube_v1.Service, writer io.Writer) error {
 
 	return nil
 }
+
+// clusterPolicies resolves the cluster policies against fixed namespaces,
+// Multi-cluster configs and K8s Services
+type clusterPolicies struct {
+	namespaces []kube_v1.Namespace
+	configs    []istiomodel.Config
+	svcs       []kube_v1.Service
+}
+
+func (p *clusterPolicies) NamespaceExposures(config istiomodel.Config) ([]istiomodel.Config, error) {
+	var seps, cseps []istiomodel.Config
+	for _, c := range p.configs {
+		switch c.Type {
+		case mcmodel.ServiceExpositionPolicy.Type:
+			seps = append(seps, c)
+		case mcmodel.ClusterServiceExpositionPolicy.Type:
+			cseps = append(cseps, c)
+		}
+	}
+	return mcmodel.ResolveClusterPolicy(config, p.namespaces, seps, cseps, p.svcs), nil
+}
@@ -0,0 +1,188 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crd
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+)
+
+// openAPISchema returns the structural OpenAPI v3 schema of the JSON form of
+// a registered proto message, e.g. "istio.multicluster.v1alpha1.RemoteServiceBinding".
+// The schema is derived from the message descriptor compiled into the Go
+// code: fields are named like the JSON marshaling of the messages does, in
+// lower camel case, and enums are written by name.
+func openAPISchema(messageName string) (map[string]interface{}, error) {
+	g := &schemaGenerator{visiting: make(map[string]bool)}
+	return g.message(messageName)
+}
+
+type schemaGenerator struct {
+	// visiting holds the messages being generated, to detect recursion
+	visiting map[string]bool
+}
+
+func (g *schemaGenerator) message(name string) (map[string]interface{}, error) {
+	switch name {
+	case "google.protobuf.Duration", "google.protobuf.Timestamp":
+		return map[string]interface{}{"type": "string"}, nil
+	case "google.protobuf.Struct", "google.protobuf.Value", "google.protobuf.Any":
+		return map[string]interface{}{"x-kubernetes-preserve-unknown-fields": true}, nil
+	}
+	if g.visiting[name] {
+		// Structural schemas can't be recursive, leave the rest unchecked
+		return map[string]interface{}{"type": "object", "x-kubernetes-preserve-unknown-fields": true}, nil
+	}
+	g.visiting[name] = true
+	defer delete(g.visiting, name)
+
+	md, err := messageDescriptor(name)
+	if err != nil {
+		return nil, err
+	}
+
+	properties := make(map[string]interface{}, len(md.Field))
+	for _, field := range md.Field {
+		schema, err := g.field(field, md)
+		if err != nil {
+			return nil, fmt.Errorf("field %s of %s: %v", field.GetName(), name, err)
+		}
+		properties[jsonName(field)] = schema
+	}
+	schema := map[string]interface{}{"type": "object"}
+	if len(properties) > 0 {
+		schema["properties"] = properties
+	}
+	return schema, nil
+}
+
+func (g *schemaGenerator) field(field *descriptor.FieldDescriptorProto, parent *descriptor.DescriptorProto) (map[string]interface{}, error) {
+	var schema map[string]interface{}
+	switch field.GetType() {
+	case descriptor.FieldDescriptorProto_TYPE_STRING:
+		schema = map[string]interface{}{"type": "string"}
+	case descriptor.FieldDescriptorProto_TYPE_BYTES:
+		schema = map[string]interface{}{"type": "string", "format": "byte"}
+	case descriptor.FieldDescriptorProto_TYPE_BOOL:
+		schema = map[string]interface{}{"type": "boolean"}
+	case descriptor.FieldDescriptorProto_TYPE_DOUBLE, descriptor.FieldDescriptorProto_TYPE_FLOAT:
+		schema = map[string]interface{}{"type": "number"}
+	case descriptor.FieldDescriptorProto_TYPE_INT32, descriptor.FieldDescriptorProto_TYPE_SINT32,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED32:
+		schema = map[string]interface{}{"type": "integer", "format": "int32"}
+	case descriptor.FieldDescriptorProto_TYPE_UINT32, descriptor.FieldDescriptorProto_TYPE_FIXED32:
+		schema = map[string]interface{}{"type": "integer", "minimum": 0, "maximum": 4294967295}
+	case descriptor.FieldDescriptorProto_TYPE_INT64, descriptor.FieldDescriptorProto_TYPE_SINT64,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED64, descriptor.FieldDescriptorProto_TYPE_UINT64,
+		descriptor.FieldDescriptorProto_TYPE_FIXED64:
+		// 64 bits integers are written as strings in JSON
+		schema = map[string]interface{}{"x-kubernetes-int-or-string": true}
+	case descriptor.FieldDescriptorProto_TYPE_ENUM:
+		values := proto.EnumValueMap(goEnumName(field.GetTypeName()))
+		if values == nil {
+			return nil, fmt.Errorf("enum %s is not registered", field.GetTypeName())
+		}
+		names := make([]string, 0, len(values))
+		for name := range values {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool { return values[names[i]] < values[names[j]] })
+		enum := make([]interface{}, len(names))
+		for i, name := range names {
+			enum[i] = name
+		}
+		schema = map[string]interface{}{"type": "string", "enum": enum}
+	case descriptor.FieldDescriptorProto_TYPE_MESSAGE:
+		if entry := mapEntry(field, parent); entry != nil {
+			value, err := g.field(entry.Field[1], entry)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"type": "object", "additionalProperties": value}, nil
+		}
+		var err error
+		if schema, err = g.message(strings.TrimPrefix(field.GetTypeName(), ".")); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported type %s", field.GetType())
+	}
+
+	if field.GetLabel() == descriptor.FieldDescriptorProto_LABEL_REPEATED {
+		return map[string]interface{}{"type": "array", "items": schema}, nil
+	}
+	return schema, nil
+}
+
+// messageDescriptor returns the descriptor of a registered message
+func messageDescriptor(name string) (*descriptor.DescriptorProto, error) {
+	typ := proto.MessageType(name)
+	if typ == nil {
+		return nil, fmt.Errorf("message %s is not registered", name)
+	}
+	msg, ok := reflect.New(typ.Elem()).Interface().(descriptor.Message)
+	if !ok {
+		return nil, fmt.Errorf("message %s has no descriptor", name)
+	}
+	_, md := descriptor.ForMessage(msg)
+	return md, nil
+}
+
+// goEnumName returns the name an enum is registered under by the generated
+// code, e.g. "istio.multicluster.v1alpha1.RemoteServiceBinding_Mode" for the
+// enum ".istio.multicluster.v1alpha1.RemoteServiceBinding.Mode": the
+// enclosing messages are joined by underscores like the Go types are.
+func goEnumName(typeName string) string {
+	parts := strings.Split(strings.TrimPrefix(typeName, "."), ".")
+	i := 0
+	for i < len(parts)-1 && parts[i] != "" && strings.ToLower(parts[i][:1]) == parts[i][:1] {
+		i++
+	}
+	return strings.Join(append(parts[:i], strings.Join(parts[i:], "_")), ".")
+}
+
+// mapEntry returns the descriptor of the entries of a map field, nil if the
+// field is not a map
+func mapEntry(field *descriptor.FieldDescriptorProto, parent *descriptor.DescriptorProto) *descriptor.DescriptorProto {
+	if field.GetLabel() != descriptor.FieldDescriptorProto_LABEL_REPEATED {
+		return nil
+	}
+	typeName := field.GetTypeName()
+	for _, nested := range parent.NestedType {
+		if nested.GetOptions().GetMapEntry() && strings.HasSuffix(typeName, "."+nested.GetName()) {
+			return nested
+		}
+	}
+	return nil
+}
+
+// jsonName returns the name of the field in the JSON form of its message
+func jsonName(field *descriptor.FieldDescriptorProto) string {
+	if field.JsonName != nil {
+		return field.GetJsonName()
+	}
+	parts := strings.Split(field.GetName(), "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
@@ -16,6 +16,8 @@ import (
 )
 
 type schemaType struct {
+	// schema is the schema of the storage version of the type, the version
+	// the objects are read and written at
 	schema     model.ProtoSchema
 	object     IstioObject
 	collection IstioObjectList
@@ -23,26 +25,37 @@ type schemaType struct {
 
 var knownTypes = map[string]schemaType{
 	mcmodel.ServiceExpositionPolicy.Type: {
-		schema: mcmodel.ServiceExpositionPolicy,
+		schema: mcmodel.ServiceExpositionPolicyV1alpha1,
 		object: &ServiceExpositionPolicy{
 			TypeMeta: meta_v1.TypeMeta{
 				Kind:       "ServiceExpositionPolicy",
-				APIVersion: apiVersion(&mcmodel.ServiceExpositionPolicy),
+				APIVersion: apiVersion(&mcmodel.ServiceExpositionPolicyV1alpha1),
 			},
 		},
 		collection: &ServiceExpositionPolicyList{},
 	},
 
 	mcmodel.RemoteServiceBinding.Type: {
-		schema: mcmodel.RemoteServiceBinding,
+		schema: mcmodel.RemoteServiceBindingV1alpha1,
 		object: &RemoteServiceBinding{
 			TypeMeta: meta_v1.TypeMeta{
 				Kind:       "RemoteServiceBinding",
-				APIVersion: apiVersion(&mcmodel.RemoteServiceBinding),
+				APIVersion: apiVersion(&mcmodel.RemoteServiceBindingV1alpha1),
 			},
 		},
 		collection: &RemoteServiceBindingList{},
 	},
+
+	mcmodel.ClusterServiceExpositionPolicy.Type: {
+		schema: mcmodel.ClusterServiceExpositionPolicy,
+		object: &ClusterServiceExpositionPolicy{
+			TypeMeta: meta_v1.TypeMeta{
+				Kind:       "ClusterServiceExpositionPolicy",
+				APIVersion: apiVersion(&mcmodel.ClusterServiceExpositionPolicy),
+			},
+		},
+		collection: &ClusterServiceExpositionPolicyList{},
+	},
 }
 
 // ServiceExpositionPolicy is the generic Kubernetes API object wrapper
@@ -250,3 +263,106 @@ func (in *RemoteServiceBindingList) DeepCopyObject() runtime.Object {
 
 	return nil
 }
+
+// ClusterServiceExpositionPolicy is the generic Kubernetes API object wrapper
+type ClusterServiceExpositionPolicy struct {
+	meta_v1.TypeMeta   `json:",inline"`
+	meta_v1.ObjectMeta `json:"metadata"`
+	Spec               map[string]interface{} `json:"spec"`
+}
+
+// GetSpec from a wrapper
+func (in *ClusterServiceExpositionPolicy) GetSpec() map[string]interface{} {
+	return in.Spec
+}
+
+// SetSpec for a wrapper
+func (in *ClusterServiceExpositionPolicy) SetSpec(spec map[string]interface{}) {
+	in.Spec = spec
+}
+
+// GetObjectMeta from a wrapper
+func (in *ClusterServiceExpositionPolicy) GetObjectMeta() meta_v1.ObjectMeta {
+	return in.ObjectMeta
+}
+
+// SetObjectMeta for a wrapper
+func (in *ClusterServiceExpositionPolicy) SetObjectMeta(metadata meta_v1.ObjectMeta) {
+	in.ObjectMeta = metadata
+}
+
+// ClusterServiceExpositionPolicyList is the generic Kubernetes API list wrapper
+type ClusterServiceExpositionPolicyList struct {
+	meta_v1.TypeMeta `json:",inline"`
+	meta_v1.ListMeta `json:"metadata"`
+	Items            []ClusterServiceExpositionPolicy `json:"items"`
+}
+
+// GetItems from a wrapper
+func (in *ClusterServiceExpositionPolicyList) GetItems() []IstioObject {
+	out := make([]IstioObject, len(in.Items))
+	for i := range in.Items {
+		out[i] = &in.Items[i]
+	}
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterServiceExpositionPolicy) DeepCopyInto(out *ClusterServiceExpositionPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterServiceExpositionPolicy.
+func (in *ClusterServiceExpositionPolicy) DeepCopy() *ClusterServiceExpositionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterServiceExpositionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterServiceExpositionPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterServiceExpositionPolicyList) DeepCopyInto(out *ClusterServiceExpositionPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterServiceExpositionPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterServiceExpositionPolicyList.
+func (in *ClusterServiceExpositionPolicyList) DeepCopy() *ClusterServiceExpositionPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterServiceExpositionPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterServiceExpositionPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crd
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	multierror "github.com/hashicorp/go-multierror"
+
+	kube_v1 "k8s.io/api/core/v1"
+
+	"github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/agent"
+	mcmodel "github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/model"
+)
+
+// TestValidateSemantics tests model.ValidateSemantics()
+func TestValidateSemantics(t *testing.T) {
+	tt := []struct {
+		config   string   // Config of the cluster
+		in       string   // SEPs and RSBs
+		store    string   // Existing Istio configs
+		svcStore string   // Existing K8s Service
+		errs     []string // Expected errors
+	}{
+		{config: "cluster_b_listens_cd.yaml",
+			in:       "ratings-exposure.yaml",
+			svcStore: "ratings-local-service.yaml"},
+		{config: "cluster_b_listens_cd.yaml",
+			in:    "reviews-exposure-v1-only.yaml",
+			store: "reviews-exposure-starter.yaml"},
+		{config: "cluster_b_listens_cd.yaml",
+			in:       "invalid-semantics.yaml",
+			store:    "reviews-exposure-starter.yaml",
+			svcStore: "ratings-local-service.yaml",
+			errs: []string{
+				`RemoteServiceBinding default/details: spec.remote[1].cluster: cluster "cluster-z" is not a peer in the cluster configuration`,
+				`ServiceExpositionPolicy default/ratings: spec.exposed[1].ports[0].number: port 9091 is not a port of K8s Service default/ratings`,
+				`ServiceExpositionPolicy default/reviews: spec.exposed[0].alias: "shared" already exposes service "ratings" by ServiceExpositionPolicy default/ratings`,
+				`ServiceExpositionPolicy default/reviews: spec.exposed[1].subset: subset "v3" is not defined in DestinationRule default/dest-rule-name`,
+			}},
+		{config: "cluster_b_listens_cd.yaml",
+			in: "invalid-semantics.yaml",
+			errs: []string{
+				`RemoteServiceBinding default/details: spec.remote[1].cluster: cluster "cluster-z" is not a peer in the cluster configuration`,
+				`ServiceExpositionPolicy default/reviews: spec.exposed[0].alias: "shared" already exposes service "ratings" by ServiceExpositionPolicy default/ratings`,
+				`ServiceExpositionPolicy default/reviews: spec.exposed[0].subset: subset "v1" is not defined: service "reviews" has no DestinationRule`,
+				`ServiceExpositionPolicy default/reviews: spec.exposed[1].subset: subset "v3" is not defined: service "reviews" has no DestinationRule`,
+			}},
+		{config: "cluster_a.yaml",
+			in:       "invalid-selector-semantics.yaml",
+			svcStore: "bookinfo-local-services.yaml",
+			errs: []string{
+				`ServiceExpositionPolicy default/bookinfo: spec.exposed[0].selector: "ratings" already exposes service "productpage" by ServiceExpositionPolicy default/aliases`,
+				`ServiceExpositionPolicy default/bookinfo: spec.exposed[1].alias: alias "bookinfo-services-of-the-team-maintaining-the-reviews-app-reviews" of service "reviews" is too long`,
+			}},
+		{config: "cluster_b_listens_cd.yaml",
+			in: "bindings-pending-approval.yaml"},
+		{config: "cluster_b_listens_cd.yaml",
+			in: "invalid-approval-binding.yaml",
+			errs: []string{
+				`RemoteServiceBinding default/ratings: metadata.annotations[multicluster.istio.io/approval]: unknown approval phase "approved", expected Pending or Approved; the binding is not realized`,
+			}},
+		{config: "cluster_b_listens_cd.yaml",
+			in: "ratings-binding-weighted.yaml"},
+		{config: "cluster_b_egress_ingress.yaml",
+			in: "ratings-binding-weighted.yaml",
+			errs: []string{
+				`RemoteServiceBinding default/ratings-weighted: spec.remote[0].weight: weights are not supported by the EGRESS_INGRESS conversion style`,
+				`RemoteServiceBinding default/ratings-weighted: spec.remote[1].weight: weights are not supported by the EGRESS_INGRESS conversion style`,
+			}},
+		{config: "cluster_b_egress_ingress.yaml",
+			in: "ratings-binding-fallback.yaml",
+			errs: []string{
+				`RemoteServiceBinding default/ratings-fallback: spec.mode: FALLBACK bindings are not supported by the EGRESS_INGRESS conversion style`,
+			}},
+		{config: "cluster_b_egress_ingress.yaml",
+			in: "ratings-binding-multi-port.yaml",
+			errs: []string{
+				`RemoteServiceBinding default/ratings-multi-port: spec.remote[0].weight: weights are not supported by the EGRESS_INGRESS conversion style`,
+				`RemoteServiceBinding default/ratings-multi-port: spec.remote[0].services[0].ports: several ports are not supported by the EGRESS_INGRESS conversion style`,
+				`RemoteServiceBinding default/ratings-multi-port: spec.remote[1].services[0].ports: several ports are not supported by the EGRESS_INGRESS conversion style`,
+				`RemoteServiceBinding default/ratings-multi-port: spec.remote[1].services[0].weight: weights are not supported by the EGRESS_INGRESS conversion style`,
+			}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.in, func(t *testing.T) {
+			clusterConfig, err := agent.LoadConfig("../../../test/mc-agent/" + tc.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := ioutil.ReadFile("../../../test/expose-binding/" + tc.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			configs, _, err := ParseInputs(string(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			store, err := createTestConfigStoreFromFile("")
+			if tc.store != "" {
+				store, err = createTestConfigStoreFromFile("../../../test/expose-binding/" + tc.store)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var svcStore []kube_v1.Service
+			if tc.svcStore != "" {
+				svcStore, err = createTestServiceStoreFromFile("../../../test/expose-binding/" + tc.svcStore)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var errs []string
+			err = mcmodel.ValidateSemantics(configs, clusterConfig, store, svcStore)
+			if merr, ok := err.(*multierror.Error); ok {
+				for _, err := range merr.Errors {
+					errs = append(errs, err.Error())
+				}
+			} else if err != nil {
+				errs = append(errs, err.Error())
+			}
+			if !reflect.DeepEqual(errs, tc.errs) {
+				t.Errorf("got errors\n%v\nwant\n%v", errs, tc.errs)
+			}
+
+			// Each error is about one of the configs
+			count := 0
+			for _, config := range configs {
+				count += len(mcmodel.FieldErrorsOf(err, config))
+			}
+			if count != len(tc.errs) {
+				t.Errorf("got %d errors about the configs, want %d", count, len(tc.errs))
+			}
+		})
+	}
+}
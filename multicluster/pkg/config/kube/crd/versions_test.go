@@ -0,0 +1,153 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crd
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+
+	istiomodel "istio.io/istio/pilot/pkg/model"
+
+	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha2"
+	mcmodel "github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/model"
+)
+
+// The configs are written at v1alpha1 and read back unchanged
+func TestConfigVersionsRoundTrip(t *testing.T) {
+	tt := []struct {
+		name     string
+		typ      string
+		spec     proto.Message
+		stashed  bool   // whether v1alpha1 can't express the spec
+		v1alpha1 string // the v1alpha1 spec JSON written
+	}{
+		{name: "single port",
+			typ: mcmodel.ServiceExpositionPolicy.Type,
+			spec: &v1alpha2.ServiceExpositionPolicy{
+				Exposed: []*v1alpha2.ServiceExpositionPolicy_ExposedService{
+					{Name: "reviews", Ports: []*v1alpha2.ServicePort{{Number: 9080}}, Clusters: []string{"cluster-b"}},
+				},
+			},
+			v1alpha1: `{"exposed":[{"name":"reviews","port":9080,"clusters":["cluster-b"]}]}`},
+		{name: "several ports",
+			typ: mcmodel.ServiceExpositionPolicy.Type,
+			spec: &v1alpha2.ServiceExpositionPolicy{
+				Exposed: []*v1alpha2.ServiceExpositionPolicy_ExposedService{{Name: "reviews", Ports: []*v1alpha2.ServicePort{
+					{Number: 9080}, {Number: 9090, Name: "grpc", Protocol: "GRPC"}}}},
+			},
+			stashed:  true,
+			v1alpha1: `{"exposed":[{"name":"reviews","port":9080}]}`},
+		{name: "excluded clusters",
+			typ: mcmodel.ServiceExpositionPolicy.Type,
+			spec: &v1alpha2.ServiceExpositionPolicy{
+				Exposed: []*v1alpha2.ServiceExpositionPolicy_ExposedService{
+					{Name: "reviews", Clusters: []string{"prod-*"}, ExcludedClusters: []string{"prod-eu-3"}},
+				},
+			},
+			stashed:  true,
+			v1alpha1: `{"exposed":[{"name":"reviews","clusters":["prod-*"]}]}`},
+		{name: "fallback binding",
+			typ: mcmodel.RemoteServiceBinding.Type,
+			spec: &v1alpha2.RemoteServiceBinding{
+				Remote: []*v1alpha2.RemoteServiceBinding_RemoteCluster{{Cluster: "cluster-c", Weight: 20, Services: []*v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService{
+					{Name: "ratings", Policy: &v1alpha2.RoutePolicy{Timeout: "2s"}}}}},
+				Mode: v1alpha2.RemoteServiceBinding_FALLBACK,
+			},
+			v1alpha1: `{"remote":[{"cluster":"cluster-c","services":[{"name":"ratings","policy":{"timeout":"2s"}}],"weight":20}],"mode":"FALLBACK"}`},
+		{name: "service weight",
+			typ: mcmodel.RemoteServiceBinding.Type,
+			spec: &v1alpha2.RemoteServiceBinding{
+				Remote: []*v1alpha2.RemoteServiceBinding_RemoteCluster{{Cluster: "cluster-c", Services: []*v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService{
+					{Name: "ratings", Weight: 80}}}},
+			},
+			stashed:  true,
+			v1alpha1: `{"remote":[{"cluster":"cluster-c","services":[{"name":"ratings"}]}]}`},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			schema, _ := mcmodel.MultiClusterConfigTypes.GetByType(tc.typ)
+			config := istiomodel.Config{
+				ConfigMeta: istiomodel.ConfigMeta{Type: tc.typ, Name: "test", Namespace: "default",
+					Annotations: map[string]string{"owner": "test"}},
+				Spec: tc.spec,
+			}
+
+			obj, err := ConvertConfig(schema, config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := obj.GetObjectKind().GroupVersionKind().Version; got != "v1alpha1" {
+				t.Errorf("written at %q, want v1alpha1", got)
+			}
+			storage, _ := mcmodel.V1alpha1ConfigTypes.GetByType(tc.typ)
+			spec, err := storage.FromJSONMap(obj.GetSpec())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := istiomodel.ToJSON(spec); got != tc.v1alpha1 {
+				t.Errorf("got v1alpha1 spec\n%s\nwant\n%s", got, tc.v1alpha1)
+			}
+			if _, ok := obj.GetObjectMeta().Annotations[mcmodel.V1alpha2SpecAnnotationKey]; ok != tc.stashed {
+				t.Errorf("got the v1alpha2 spec annotation %t, want %t", ok, tc.stashed)
+			}
+
+			read, err := ConvertObject(schema, obj, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !proto.Equal(read.Spec, tc.spec) {
+				t.Errorf("read spec %v, want %v", read.Spec, tc.spec)
+			}
+			if read.Version != "v1alpha2" || len(read.Annotations) != 1 {
+				t.Errorf("read version %q and annotations %v, want v1alpha2 and the owner only", read.Version, read.Annotations)
+			}
+		})
+	}
+}
+
+// A config changed at v1alpha1 since it was written loses the spec kept by
+// the annotation
+func TestConfigChangedAtV1alpha1(t *testing.T) {
+	schema := mcmodel.ServiceExpositionPolicy
+	config := istiomodel.Config{
+		ConfigMeta: istiomodel.ConfigMeta{Type: schema.Type, Name: "reviews", Namespace: "default"},
+		Spec: &v1alpha2.ServiceExpositionPolicy{
+			Exposed: []*v1alpha2.ServiceExpositionPolicy_ExposedService{{Name: "reviews", Ports: []*v1alpha2.ServicePort{
+				{Number: 9080}, {Number: 9090}}}},
+		},
+	}
+	obj, err := ConvertConfig(schema, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj.GetSpec()["exposed"].([]interface{})[0].(map[string]interface{})["port"] = 8080
+
+	read, err := ConvertObject(schema, obj, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &v1alpha2.ServiceExpositionPolicy{
+		Exposed: []*v1alpha2.ServiceExpositionPolicy_ExposedService{{Name: "reviews", Ports: []*v1alpha2.ServicePort{
+			{Number: 8080}}}},
+	}
+	if !proto.Equal(read.Spec, want) {
+		t.Errorf("read spec %v, want %v", read.Spec, want)
+	}
+	if _, ok := read.Annotations[mcmodel.V1alpha2SpecAnnotationKey]; ok {
+		t.Errorf("the v1alpha2 spec annotation was kept")
+	}
+}
@@ -0,0 +1,51 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	istiomodel "istio.io/istio/pilot/pkg/model"
+)
+
+const (
+	// ApprovalAnnotationKey is the key to an annotation on a
+	// RemoteServiceBinding holding its approval phase. Bindings without the
+	// annotation need no approval.
+	ApprovalAnnotationKey = "multicluster.istio.io/approval"
+
+	// ApprovalPending holds the binding back until an operator approves it
+	ApprovalPending = "Pending"
+
+	// ApprovalApproved is the phase of a binding approved by an operator
+	ApprovalApproved = "Approved"
+)
+
+// BindingApproved returns true if the RemoteServiceBinding is to be realized:
+// it has no approval annotation or was approved. Any other phase, including
+// a misspelt one, holds the binding back.
+func BindingApproved(config istiomodel.Config) bool {
+	phase, ok := config.Annotations[ApprovalAnnotationKey]
+	return !ok || phase == ApprovalApproved
+}
+
+// validateBindingApproval returns an error if the approval phase of the
+// binding is neither ApprovalPending nor ApprovalApproved
+func validateBindingApproval(config istiomodel.Config) error {
+	phase, ok := config.Annotations[ApprovalAnnotationKey]
+	if !ok || phase == ApprovalPending || phase == ApprovalApproved {
+		return nil
+	}
+	return fieldError(config, "metadata.annotations["+ApprovalAnnotationKey+"]",
+		"unknown approval phase %q, expected %s or %s; the binding is not realized", phase, ApprovalPending, ApprovalApproved)
+}
@@ -0,0 +1,115 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+
+	rbac "istio.io/api/rbac/v1alpha1"
+	istiomodel "istio.io/istio/pilot/pkg/model"
+
+	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha2"
+)
+
+// expositionToAuthorization returns the ServiceRole and ServiceRoleBinding
+// allowing the remote clusters the service is exposed to, and only those, to
+// call the exposed workload. The ingress gateway passes the TLS connections
+// of remote clusters through to the sidecar of the workload, so the sidecar is
+// the first to know the identity of the caller and enforces the
+// authorization. It only does so in the namespaces and for the services the
+// mesh RbacConfig enables Istio RBAC for. The ingress gateway cannot
+// authorize the callers: it does not terminate their TLS, and the Istio
+// version supported here has no RBAC for gateways. Returns an error if none
+// of the clusters the service is exposed to can be identified, rather than
+// leaving the service open to every cluster of the root of trust.
+func expositionToAuthorization(es *v1alpha2.ServiceExpositionPolicy_ExposedService, config istiomodel.Config,
+	opts ConversionOptions) ([]istiomodel.Config, error) {
+	domains := allowedTrustDomains(es, opts)
+	if len(domains) == 0 {
+		return nil, fmt.Errorf("cannot authorize the clusters service %s is exposed to: "+
+			"no trust domain identifies them apart from the other peers", exposedServiceName(es))
+	}
+
+	name := fmt.Sprintf("remote-clusters-to-%s-%s", exposedServiceName(es), getNamespace(config))
+	subjects := make([]*rbac.Subject, 0, len(domains))
+	for _, td := range domains {
+		// Istio principals are the SPIFFE identities without the scheme
+		subjects = append(subjects, &rbac.Subject{User: fmt.Sprintf("%s/*", td)})
+	}
+
+	return []istiomodel.Config{
+		{
+			ConfigMeta: istiomodel.ConfigMeta{
+				Type:        istiomodel.ServiceRole.Type,
+				Group:       istiomodel.ServiceRole.Group + istiomodel.IstioAPIGroupDomain,
+				Version:     istiomodel.ServiceRole.Version,
+				Name:        name,
+				Namespace:   getNamespace(config),
+				Annotations: annotations(config),
+			},
+			Spec: &rbac.ServiceRole{
+				Rules: []*rbac.AccessRule{
+					{
+						Services: []string{exposedServiceHostname(es, config)},
+						Methods:  []string{"*"},
+					},
+				},
+			},
+		},
+		{
+			ConfigMeta: istiomodel.ConfigMeta{
+				Type:        istiomodel.ServiceRoleBinding.Type,
+				Group:       istiomodel.ServiceRoleBinding.Group + istiomodel.IstioAPIGroupDomain,
+				Version:     istiomodel.ServiceRoleBinding.Version,
+				Name:        name,
+				Namespace:   getNamespace(config),
+				Annotations: annotations(config),
+			},
+			Spec: &rbac.ServiceRoleBinding{
+				Subjects: subjects,
+				RoleRef: &rbac.RoleRef{
+					Kind: "ServiceRole",
+					Name: name,
+				},
+			},
+		},
+	}, nil
+}
+
+// allowedTrustDomains returns the trust domains of the peers the service is
+// exposed to, sorted. Peers are identified by their trust domain in the
+// options; a trust domain shared with a peer the service is not exposed to
+// cannot tell them apart and is left out.
+func allowedTrustDomains(es *v1alpha2.ServiceExpositionPolicy_ExposedService, opts ConversionOptions) []string {
+	allowed := make(map[string]bool)
+	denied := make(map[string]bool)
+	for cluster, td := range opts.TrustDomains {
+		if td == "" {
+			continue
+		}
+		if ExposedToCluster(es, cluster, opts.ClusterGroups) {
+			allowed[td] = true
+		} else {
+			denied[td] = true
+		}
+	}
+	for td := range denied {
+		delete(allowed, td)
+	}
+	if len(allowed) == 0 {
+		return nil
+	}
+	return sortedKeys(allowed)
+}
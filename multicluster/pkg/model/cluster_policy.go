@@ -0,0 +1,177 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"sort"
+
+	istiomodel "istio.io/istio/pilot/pkg/model"
+
+	kube_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha2"
+)
+
+// ClusterProvenanceNamespace stands for the namespace of cluster-scoped
+// configs in their ProvenanceAnnotation(), which is the same in every
+// namespace they apply to
+const ClusterProvenanceNamespace = "*"
+
+// ClusterPolicyScope resolves the namespaces a ClusterServiceExpositionPolicy
+// applies to, and what it exposes there
+type ClusterPolicyScope interface {
+	// NamespaceExposures returns the cluster policy as it applies in each
+	// namespace, see ResolveClusterPolicy
+	NamespaceExposures(config istiomodel.Config) ([]istiomodel.Config, error)
+}
+
+// ResolveClusterPolicy returns the ClusterServiceExpositionPolicy 'config' as
+// it applies in each of the 'namespaces' it selects: a config of the
+// namespace, of the policy's type and name, holding a ServiceExpositionPolicy
+// of the services it exposes there. Namespaces where it exposes nothing are
+// left out.
+//
+// The exposures of the namespaced 'seps' take precedence, then those of the
+// cluster policies 'cseps' by age, oldest first. A service, or an exposed
+// name, already used by an exposure that takes precedence is not exposed
+// again. The exposures naming a service only apply in the namespaces having
+// it among the K8s Services 'svcs'.
+func ResolveClusterPolicy(config istiomodel.Config, namespaces []kube_v1.Namespace,
+	seps, cseps []istiomodel.Config, svcs []kube_v1.Service) []istiomodel.Config {
+	csep, ok := config.Spec.(*v1alpha2.ClusterServiceExpositionPolicy)
+	if !ok {
+		return nil
+	}
+	older := olderClusterPolicies(config, cseps)
+
+	sorted := make([]kube_v1.Namespace, len(namespaces))
+	copy(sorted, namespaces)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	out := make([]istiomodel.Config, 0)
+	for _, ns := range sorted {
+		if !selectsNamespace(csep, ns) {
+			continue
+		}
+
+		claims := newExposureClaims()
+		for _, sep := range seps {
+			if spec, ok := sep.Spec.(*v1alpha2.ServiceExpositionPolicy); ok && getNamespace(sep) == ns.Name {
+				claims.claimAll(ExposedServices(spec, ns.Name, svcs))
+			}
+		}
+		for _, prior := range older {
+			spec := prior.Spec.(*v1alpha2.ClusterServiceExpositionPolicy)
+			if selectsNamespace(spec, ns) {
+				claims.claimAll(claims.unclaimed(clusterExposures(spec, ns.Name, svcs)))
+			}
+		}
+
+		exposed := claims.unclaimed(clusterExposures(csep, ns.Name, svcs))
+		if len(exposed) == 0 {
+			continue
+		}
+		resolved := config
+		resolved.Namespace = ns.Name
+		resolved.Spec = &v1alpha2.ServiceExpositionPolicy{Exposed: exposed}
+		out = append(out, resolved)
+	}
+	return out
+}
+
+// olderClusterPolicies returns the cluster policies taking precedence over
+// 'config', by creation time and then by name
+func olderClusterPolicies(config istiomodel.Config, cseps []istiomodel.Config) []istiomodel.Config {
+	out := make([]istiomodel.Config, 0, len(cseps))
+	for _, csep := range cseps {
+		if _, ok := csep.Spec.(*v1alpha2.ClusterServiceExpositionPolicy); ok && csep.Name != config.Name &&
+			precedes(csep, config) {
+			out = append(out, csep)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return precedes(out[i], out[j]) })
+	return out
+}
+
+func precedes(a, b istiomodel.Config) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
+
+// selectsNamespace returns true if the cluster policy applies in the namespace
+func selectsNamespace(csep *v1alpha2.ClusterServiceExpositionPolicy, ns kube_v1.Namespace) bool {
+	return labels.SelectorFromSet(labels.Set(csep.NamespaceSelector)).Matches(labels.Set(ns.Labels))
+}
+
+// clusterExposures returns the exposures of the cluster policy in the
+// namespace, leaving out the services the namespace doesn't have
+func clusterExposures(csep *v1alpha2.ClusterServiceExpositionPolicy, namespace string,
+	svcs []kube_v1.Service) []*v1alpha2.ServiceExpositionPolicy_ExposedService {
+	out := make([]*v1alpha2.ServiceExpositionPolicy_ExposedService, 0, len(csep.Exposed))
+	sep := &v1alpha2.ServiceExpositionPolicy{Exposed: csep.Exposed}
+	for _, es := range ExposedServices(sep, namespace, svcs) {
+		if localService(svcs, namespace, es.Name) != nil {
+			out = append(out, es)
+		}
+	}
+	return out
+}
+
+// exposureClaims records the services, and the exposed names, used by the
+// exposures of a namespace
+type exposureClaims struct {
+	services map[string]bool
+	names    map[string]bool
+}
+
+func newExposureClaims() *exposureClaims {
+	return &exposureClaims{
+		services: make(map[string]bool),
+		names:    make(map[string]bool),
+	}
+}
+
+func (c *exposureClaims) claimAll(exposed []*v1alpha2.ServiceExpositionPolicy_ExposedService) {
+	for _, es := range exposed {
+		c.services[es.Name] = true
+		c.names[exposedServiceName(es)] = true
+	}
+}
+
+// unclaimed returns the exposures using neither a claimed service nor a
+// claimed exposed name. Among themselves, the first exposure of a name wins.
+func (c *exposureClaims) unclaimed(
+	exposed []*v1alpha2.ServiceExpositionPolicy_ExposedService) []*v1alpha2.ServiceExpositionPolicy_ExposedService {
+	out := make([]*v1alpha2.ServiceExpositionPolicy_ExposedService, 0, len(exposed))
+	names := make(map[string]bool)
+	for _, es := range exposed {
+		name := exposedServiceName(es)
+		if c.services[es.Name] || c.names[name] || names[name] {
+			continue
+		}
+		names[name] = true
+		out = append(out, es)
+	}
+	return out
+}
+
+// clusterProvenance returns the ProvenanceAnnotation() of a cluster-scoped config
+func clusterProvenance(config istiomodel.Config) string {
+	return fmt.Sprintf("%s/%s.%s", provenanceKinds[config.Type], ClusterProvenanceNamespace, config.Name)
+}
@@ -0,0 +1,83 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha2"
+)
+
+// ClusterGroups names groups of clusters. The members of a group are cluster
+// IDs or glob patterns matching them.
+type ClusterGroups map[string][]string
+
+// ExposedToCluster returns true if the exposed service is exposed to the
+// cluster: its clusters list is empty or matches the cluster, and its
+// excluded clusters don't. The entries of both lists are matched by
+// MatchesCluster.
+func ExposedToCluster(es *v1alpha2.ServiceExpositionPolicy_ExposedService, clusterID string, groups ClusterGroups) bool {
+	if len(es.Clusters) > 0 && !MatchesCluster(es.Clusters, clusterID, groups) {
+		return false
+	}
+	return !MatchesCluster(es.ExcludedClusters, clusterID, groups)
+}
+
+// MatchesCluster returns true if one of the entries matches the cluster ID.
+// An entry naming one of the groups matches the members of the group, any
+// other entry is the cluster ID or a glob pattern, in the syntax of
+// path.Match.
+func MatchesCluster(entries []string, clusterID string, groups ClusterGroups) bool {
+	for _, entry := range entries {
+		if members, ok := groups[entry]; ok {
+			if MatchesCluster(members, clusterID, nil) {
+				return true
+			}
+			continue
+		}
+		if matched, err := path.Match(entry, clusterID); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateClusterPattern checks a cluster ID or glob pattern is well-formed
+func ValidateClusterPattern(pattern string) error {
+	if pattern == "" {
+		return fmt.Errorf("empty cluster")
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid cluster pattern %q: %v", pattern, err)
+	}
+	return nil
+}
+
+// ValidateClusterGroups checks the members of the cluster groups
+func ValidateClusterGroups(groups ClusterGroups) error {
+	var errs error
+	for name, members := range groups {
+		if name == "" {
+			errs = appendErrors(errs, fmt.Errorf("cluster group without a name"))
+		}
+		for _, member := range members {
+			if err := ValidateClusterPattern(member); err != nil {
+				errs = appendErrors(errs, fmt.Errorf("cluster group %q: %v", name, err))
+			}
+		}
+	}
+	return errs
+}
@@ -17,33 +17,77 @@ type MCConfigStore interface {
 
 	// RemoteServiceBindings lists all RemoteServiceBinding entries
 	RemoteServiceBindings() []istio.Config
+
+	// ClusterServiceExpositionPolicies lists all ClusterServiceExpositionPolicy entries
+	ClusterServiceExpositionPolicies() []istio.Config
 }
 
 var (
-	// ServiceExpositionPolicy describes v1alpha1 multi-cluster exposition policy
+	// ServiceExpositionPolicy describes v1alpha2 multi-cluster exposition policy
 	ServiceExpositionPolicy = istio.ProtoSchema{
 		Type:        "service-exposition-policy",
 		Plural:      "service-exposition-policies",
 		Group:       "multicluster",
-		Version:     "v1alpha1",
-		MessageName: "istio.multicluster.v1alpha1.ServiceExpositionPolicy",
+		Version:     "v1alpha2",
+		MessageName: "istio.multicluster.v1alpha2.ServiceExpositionPolicy",
 		Validate:    ValidateServiceExpositionPolicy,
 	}
 
-	// RemoteServiceBinding describes v1alpha1 multi-cluster remote service binding
+	// RemoteServiceBinding describes v1alpha2 multi-cluster remote service binding
 	RemoteServiceBinding = istio.ProtoSchema{
 		Type:        "remote-service-binding",
 		Plural:      "remote-service-bindings",
 		Group:       "multicluster",
-		Version:     "v1alpha1",
-		MessageName: "istio.multicluster.v1alpha1.RemoteServiceBinding",
+		Version:     "v1alpha2",
+		MessageName: "istio.multicluster.v1alpha2.RemoteServiceBinding",
 		Validate:    ValidateRemoteServiceBinding,
 	}
 
+	// ClusterServiceExpositionPolicy describes v1alpha2 cluster-scoped
+	// multi-cluster exposition policy. It has no v1alpha1 version.
+	ClusterServiceExpositionPolicy = istio.ProtoSchema{
+		ClusterScoped: true,
+		Type:          "cluster-service-exposition-policy",
+		Plural:        "cluster-service-exposition-policies",
+		Group:         "multicluster",
+		Version:       "v1alpha2",
+		MessageName:   "istio.multicluster.v1alpha2.ClusterServiceExpositionPolicy",
+		Validate:      ValidateClusterServiceExpositionPolicy,
+	}
+
 	// MultiClusterConfigTypes lists all Istio config types with schemas and validation
 	MultiClusterConfigTypes = istio.ConfigDescriptor{
 		ServiceExpositionPolicy,
 		RemoteServiceBinding,
+		ClusterServiceExpositionPolicy,
+	}
+
+	// ServiceExpositionPolicyV1alpha1 describes v1alpha1 multi-cluster exposition policy
+	ServiceExpositionPolicyV1alpha1 = istio.ProtoSchema{
+		Type:        ServiceExpositionPolicy.Type,
+		Plural:      ServiceExpositionPolicy.Plural,
+		Group:       ServiceExpositionPolicy.Group,
+		Version:     "v1alpha1",
+		MessageName: "istio.multicluster.v1alpha1.ServiceExpositionPolicy",
+		Validate:    validateV1alpha1(ValidateServiceExpositionPolicy),
+	}
+
+	// RemoteServiceBindingV1alpha1 describes v1alpha1 multi-cluster remote service binding
+	RemoteServiceBindingV1alpha1 = istio.ProtoSchema{
+		Type:        RemoteServiceBinding.Type,
+		Plural:      RemoteServiceBinding.Plural,
+		Group:       RemoteServiceBinding.Group,
+		Version:     "v1alpha1",
+		MessageName: "istio.multicluster.v1alpha1.RemoteServiceBinding",
+		Validate:    validateV1alpha1(ValidateRemoteServiceBinding),
+	}
+
+	// V1alpha1ConfigTypes lists the v1alpha1 versions of the config types.
+	// The configs are stored at v1alpha1 and converted to the v1alpha2 specs
+	// of MultiClusterConfigTypes, see ConfigFromV1alpha1.
+	V1alpha1ConfigTypes = istio.ConfigDescriptor{
+		ServiceExpositionPolicyV1alpha1,
+		RemoteServiceBindingV1alpha1,
 	}
 )
 
@@ -77,3 +121,13 @@ func (store *mcConfigStore) RemoteServiceBindings() []istio.Config {
 	}
 	return configs
 }
+
+// ClusterServiceExpositionPolicies will return all
+// ClusterServiceExpositionPolicy entries from the store
+func (store *mcConfigStore) ClusterServiceExpositionPolicies() []istio.Config {
+	configs, err := store.List(ClusterServiceExpositionPolicy.Type, istio.NamespaceAll)
+	if err != nil {
+		return nil
+	}
+	return configs
+}
@@ -15,8 +15,6 @@
 package model
 
 import (
-	"os"
-
 	istiomodel "istio.io/istio/pilot/pkg/model"
 
 	kube_v1 "k8s.io/api/core/v1"
@@ -26,18 +24,23 @@ const (
 	// ProvenanceAnnotationKey is the key to an annotation that maps created config back to multicluster desired state CRD
 	ProvenanceAnnotationKey = "multicluster.istio.io/provenance"
 
-	// IstioSystemNamespace is "istio-system", the namespace where the Istio components run
-	IstioSystemNamespace = istiomodel.IstioSystemNamespace // TODO handle non-default installs
+	// IstioSystemNamespace is "istio-system", the namespace where the Istio components of a default install run.
+	// See ConversionOptions for other installs.
+	IstioSystemNamespace = istiomodel.IstioSystemNamespace
 
-	// IstioConversionStyleKey names an exported OS environment variable with value DIRECT_INGRESS or EGRESS_INGRESS
+	// IstioConversionStyleKey names an OS environment variable with value DIRECT_INGRESS or EGRESS_INGRESS.
+	// Deprecated: the mc-agent only reads it when its cluster configuration has no ConversionStyle.
 	IstioConversionStyleKey = "MC_STYLE"
 
-	// EgressIngressStyle is a value for IstioConversionStyleKey that requests the agent create
-	// Istio configuration that flows through an Egress
+	// EgressIngressStyle is the name of the ConversionStyle creating Istio configuration that flows
+	// through an Egress
 	EgressIngressStyle = "EGRESS_INGRESS"
-	// DirectIngressStyle is a value for IstioConversionStyleKey that requests the agent create
-	// Istio configuration that communicates directly to the remote IngressGateway
+	// DirectIngressStyle is the name of the ConversionStyle creating Istio configuration that
+	// communicates directly to the remote IngressGateway
 	DirectIngressStyle = "DIRECT_INGRESS"
+
+	// DefaultConversionStyle is the style used when none is configured
+	DefaultConversionStyle = DirectIngressStyle
 )
 
 // ClusterInfo gets the IP and port for a cluster's ingress
@@ -52,25 +55,15 @@ type ClusterInfo interface {
 	Port(name string) uint32
 }
 
-// ConvertBindingsAndExposures is deprecated
-func ConvertBindingsAndExposures(mcs []istiomodel.Config, ci ClusterInfo, store istiomodel.ConfigStore) ([]istiomodel.Config, error) {
-	if os.Getenv(IstioConversionStyleKey) == DirectIngressStyle {
-		istioConfig, k8sConfig, err := ConvertBindingsAndExposuresDirectIngress(mcs, ci, store, []kube_v1.Service{})
-		_ = k8sConfig
-		return istioConfig, err
-	}
-
-	// Default
-	return ConvertBindingsAndExposuresEgressIngress(mcs, ci)
-}
-
 // ConvertBindingsAndExposures2 converts desired multicluster state into Kubernetes and Istio state
-func ConvertBindingsAndExposures2(mcs []istiomodel.Config, ci ClusterInfo, store istiomodel.ConfigStore, svcs []kube_v1.Service) ([]istiomodel.Config, []kube_v1.Service, error) { // nolint: lll
-	if os.Getenv(IstioConversionStyleKey) == DirectIngressStyle {
-		return ConvertBindingsAndExposuresDirectIngress(mcs, ci, store, svcs)
+// using the conversion style, or the DefaultConversionStyle if nil.
+// The options describe the local Istio install; unset options are defaulted.
+func ConvertBindingsAndExposures2(style ConversionStyle, mcs []istiomodel.Config, ci ClusterInfo, store istiomodel.ConfigStore, svcs []kube_v1.Service, opts ConversionOptions) ([]istiomodel.Config, []kube_v1.Service, error) { // nolint: lll
+	if style == nil {
+		var err error
+		if style, err = GetConversionStyle(DefaultConversionStyle); err != nil {
+			return nil, nil, err
+		}
 	}
-
-	// Default
-	istioConfig, err := ConvertBindingsAndExposuresEgressIngress(mcs, ci)
-	return istioConfig, []kube_v1.Service{}, err
+	return style.Convert(mcs, ci, store, svcs, opts.WithDefaults())
 }
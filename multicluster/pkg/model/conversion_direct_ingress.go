@@ -18,7 +18,7 @@ import (
 	"fmt"
 	"sort"
 
-	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha1"
+	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha2"
 
 	"istio.io/api/networking/v1alpha3"
 	istiomodel "istio.io/istio/pilot/pkg/model"
@@ -31,34 +31,61 @@ import (
 
 // ConvertBindingsAndExposuresDirectIngress converts a list of multicluster SEP and RDS configuration
 // into Istio configuration.  It may consult existing Istio configuration in 'store' (e.g. DestinationRule subsets)
-func ConvertBindingsAndExposuresDirectIngress(mcs []istiomodel.Config, ci ClusterInfo, store istiomodel.ConfigStore, svcs []kube_v1.Service) ([]istiomodel.Config, []kube_v1.Service, error) { // nolint: lll
+func ConvertBindingsAndExposuresDirectIngress(mcs []istiomodel.Config, ci ClusterInfo, store istiomodel.ConfigStore, existingSvcs []kube_v1.Service, opts ConversionOptions) ([]istiomodel.Config, []kube_v1.Service, error) { // nolint: lll
+	opts = opts.WithDefaults()
 	out := make([]istiomodel.Config, 0)
 	outServices := make([]kube_v1.Service, 0)
 
-	// Construct map of hostname -> DestinationRule (needed for merging for subsets)
-	drs, err := mapHostnameToDestinationRule(store)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	// Construct map of hostname -> ServiceEntry (needed for merging for multiple endpoints)
-	vss, err := mapHostnameToServiceEntry(store)
-	if err != nil {
-		return nil, nil, err
-	}
+	// Maps of namespace -> hostname -> DestinationRule (needed for merging for subsets) and
+	// namespace -> hostname -> ServiceEntry (needed for merging for multiple endpoints)
+	drsByNamespace := make(map[string]map[string]*istiomodel.Config)
+	sesByNamespace := make(map[string]map[string]*istiomodel.Config)
 
 	// Process each Multicluster Config SEP or RSB
 	for _, mc := range mcs {
 		var istio []istiomodel.Config
 		var svcs []kube_v1.Service
 		var err error
-		rsb, ok := mc.Spec.(*v1alpha1.RemoteServiceBinding)
+		rsb, ok := mc.Spec.(*v1alpha2.RemoteServiceBinding)
 		if ok {
-			istio, svcs, err = convertRSBDirectIngress(mc, rsb, vss, ci)
+			ns := getNamespace(mc)
+			ses, ok := sesByNamespace[ns]
+			if !ok {
+				ses, err = mapHostnameToServiceEntry(store, ns)
+				if err != nil {
+					return nil, nil, err
+				}
+				sesByNamespace[ns] = ses
+			}
+			withoutSourceEndpoints(ses, mc)
+			drs, ok := drsByNamespace[ns]
+			if !ok {
+				drs, err = mapHostnameToDestinationRule(store, ns)
+				if err != nil {
+					return nil, nil, err
+				}
+				drsByNamespace[ns] = drs
+			}
+			// Bindings awaiting approval are not realized
+			if BindingApproved(mc) {
+				istio, svcs, err = convertRSBDirectIngress(mc, rsb, ses, drs, existingSvcs, ci, opts)
+			}
 		}
-		sep, ok := mc.Spec.(*v1alpha1.ServiceExpositionPolicy)
+		sep, ok := mc.Spec.(*v1alpha2.ServiceExpositionPolicy)
 		if ok {
-			istio, err = convertSEPDirectIngress(mc, sep, drs, ci)
+			ns := getNamespace(mc)
+			drs, ok := drsByNamespace[ns]
+			if !ok {
+				drs, err = mapHostnameToDestinationRule(store, ns)
+				if err != nil {
+					return nil, nil, err
+				}
+				drsByNamespace[ns] = drs
+			}
+			istio, err = convertSEPDirectIngress(mc, sep, drs, existingSvcs, ci, opts)
+		}
+		if _, ok := mc.Spec.(*v1alpha2.ClusterServiceExpositionPolicy); ok {
+			istio, err = convertClusterSEP(mc, drsByNamespace, store, ci, opts)
 		}
 		if err != nil {
 			return out, outServices, multierror.Prefix(err, "Could not convert")
@@ -70,10 +97,10 @@ func ConvertBindingsAndExposuresDirectIngress(mcs []istiomodel.Config, ci Cluste
 	return uniquifyIstio(out), uniquifyServices(outServices), nil
 }
 
-// Construct map of hostname -> DestinationRule
-func mapHostnameToDestinationRule(store istiomodel.ConfigStore) (map[string]*istiomodel.Config, error) {
+// Construct map of hostname -> DestinationRule for the DestinationRules of a namespace
+func mapHostnameToDestinationRule(store istiomodel.ConfigStore, namespace string) (map[string]*istiomodel.Config, error) {
 	drs := make(map[string]*istiomodel.Config)
-	drConfigs, err := store.List(istiomodel.DestinationRule.Type, meta_v1.NamespaceDefault)
+	drConfigs, err := store.List(istiomodel.DestinationRule.Type, namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -92,10 +119,10 @@ func mapHostnameToDestinationRule(store istiomodel.ConfigStore) (map[string]*ist
 	return drs, nil
 }
 
-// Construct map of hostname -> ServiceEntry
-func mapHostnameToServiceEntry(store istiomodel.ConfigStore) (map[string]*istiomodel.Config, error) {
+// Construct map of hostname -> ServiceEntry for the ServiceEntries of a namespace
+func mapHostnameToServiceEntry(store istiomodel.ConfigStore, namespace string) (map[string]*istiomodel.Config, error) {
 	serviceEntries := make(map[string]*istiomodel.Config)
-	seConfigs, err := store.List(istiomodel.ServiceEntry.Type, meta_v1.NamespaceDefault)
+	seConfigs, err := store.List(istiomodel.ServiceEntry.Type, namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -114,6 +141,12 @@ func mapHostnameToServiceEntry(store istiomodel.ConfigStore) (map[string]*istiom
 			for protocol, port := range endpoint.Ports {
 				newEndpoint.Ports[protocol] = port
 			}
+			if len(endpoint.Labels) > 0 {
+				newEndpoint.Labels = make(map[string]string)
+				for key, value := range endpoint.Labels {
+					newEndpoint.Labels[key] = value
+				}
+			}
 			newSpec.Endpoints = append(newSpec.Endpoints, newEndpoint)
 		}
 		newSE.Spec = &newSpec
@@ -125,6 +158,24 @@ func mapHostnameToServiceEntry(store istiomodel.ConfigStore) (map[string]*istiom
 	return serviceEntries, nil
 }
 
+// withoutSourceEndpoints removes the endpoints the multicluster config contributed to the
+// ServiceEntries so that converting it again only keeps the endpoints it still binds.
+// ServiceEntries realized only for this config are forgotten and will be generated anew.
+func withoutSourceEndpoints(serviceEntries map[string]*istiomodel.Config, mc istiomodel.Config) {
+	source := ProvenanceAnnotation(mc)
+	for host, se := range serviceEntries {
+		record := GetProvenance(se.Annotations)
+		if !record.Has(source) {
+			continue
+		}
+		if record.Len() == 1 {
+			delete(serviceEntries, host)
+			continue
+		}
+		*se = WithoutSource(*se, mc)
+	}
+}
+
 // uniqueifyIstio removes duplicates (e.g. DRs for the same host exposed under different aliases),
 // favoring duplicates later in the sequence.
 func uniquifyIstio(configs []istiomodel.Config) []istiomodel.Config {
@@ -162,30 +213,114 @@ func uniquifyServices(configs []kube_v1.Service) []kube_v1.Service {
 	return unique
 }
 
-func convertRSBDirectIngress(config istiomodel.Config, rsb *v1alpha1.RemoteServiceBinding,
-	serviceEntries map[string]*istiomodel.Config, ci ClusterInfo) ([]istiomodel.Config,
-	[]kube_v1.Service, error) {
+// convertRSBDirectIngress converts a binding. 'serviceEntries' and 'drs' map
+// hostname to the ServiceEntries and DestinationRules of the namespace.
+func convertRSBDirectIngress(config istiomodel.Config, rsb *v1alpha2.RemoteServiceBinding,
+	serviceEntries, drs map[string]*istiomodel.Config, existingSvcs []kube_v1.Service, ci ClusterInfo,
+	opts ConversionOptions) ([]istiomodel.Config, []kube_v1.Service, error) {
 	out := make([]istiomodel.Config, 0)
 	outSvcs := make([]kube_v1.Service, 0)
 
+	clusters := bindingClusters(rsb)
+	shares := bindingShares(rsb)
 	for _, remote := range rsb.Remote {
 		for _, svc := range remote.Services {
-			out = append(out, *serviceToServiceEntryDirectIngress(svc, config,
-				serviceEntries, ci.IP(remote.Cluster), ci.Port(remote.Cluster)))
-			out = append(out, *serviceToDestinationRuleDirectIngress(svc, config))
-			outSvcs = append(outSvcs, *serviceToKubernetesServiceDirectIngress(svc, config))
+			svcShares := shares[rsHostname(svc)]
+
+			// A FALLBACK binding keeps the local K8s Service and reaches the remote
+			// clusters through a host of its own
+			fallback := rsb.Mode == v1alpha2.RemoteServiceBinding_FALLBACK &&
+				localService(existingSvcs, remoteServiceNamespace(svc), remoteServiceName(svc)) != nil
+			hostname, seOpts := rsHostname(svc), opts
+			if fallback {
+				// Clients resolve the local K8s Service, so the remote host needs no VIP
+				hostname, seOpts.VIPs = rsFallbackHostname(svc), nil
+			}
+
+			se, err := serviceToServiceEntryDirectIngress(svc, hostname, config, serviceEntries, ci.IP(remote.Cluster),
+				ci.Port(remote.Cluster), clusterLabels(svcShares, remote.Cluster), seOpts)
+			if err != nil {
+				return nil, nil, err
+			}
+			out = append(out, *se)
+			dr, err := serviceToDestinationRuleDirectIngress(svc, hostname, config, opts, clusters[rsHostname(svc)], svcShares)
+			if err != nil {
+				return nil, nil, err
+			}
+			if fallback {
+				dr.Spec.(*v1alpha3.DestinationRule).TrafficPolicy.OutlierDetection = outlierDetection()
+				if local := localDestinationRule(svc, config, drs); local != nil {
+					out = append(out, *local)
+				}
+			}
+			out = append(out, *dr)
+
+			if fallback || len(svcShares) > 0 || svc.Policy != nil {
+				// The route policy of the donor cluster only applies to the requests it serves
+				route, policy := weightedRoute(hostname, portClientUses(svc), svcShares), svc.Policy
+				if fallback && opts.localReady(remoteServiceNamespace(svc), remoteServiceName(svc)) {
+					route, policy = weightedRoute(rsHostname(svc), portClientUses(svc), nil), nil
+				}
+				vs, err := serviceToVirtualServiceDirectIngress(svc, config, route, policy)
+				if err != nil {
+					return nil, nil, err
+				}
+				out = append(out, *vs)
+			}
+			if fallback {
+				// The local K8s Service is kept
+				continue
+			}
+			outSvcs = append(outSvcs, *serviceToKubernetesServiceDirectIngress(svc, config, se))
 		}
 	}
 
 	return out, outSvcs, nil
 }
 
+// localDestinationRule creates a DestinationRule ejecting the failing endpoints
+// of the local K8s Service of a FALLBACK binding. The requests only go to the
+// remote clusters once the Service has no ready endpoint; meanwhile those
+// failing while ready are ejected. Returns nil if 'drs' has another rule for
+// the local host, which Istio would not merge with it.
+func localDestinationRule(rs *v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService, config istiomodel.Config,
+	drs map[string]*istiomodel.Config) *istiomodel.Config {
+	name := fmt.Sprintf("dest-rule-%s-local", remoteServiceName(rs))
+	for host, dr := range drs {
+		if dr.Name != name && IsServiceHost(host, remoteServiceName(rs), remoteServiceNamespace(rs)) {
+			return nil
+		}
+	}
+	return &istiomodel.Config{
+		ConfigMeta: istiomodel.ConfigMeta{
+			Type:        istiomodel.DestinationRule.Type,
+			Group:       istiomodel.DestinationRule.Group + istiomodel.IstioAPIGroupDomain,
+			Version:     istiomodel.DestinationRule.Version,
+			Name:        name,
+			Namespace:   config.Namespace,
+			Annotations: annotations(config),
+		},
+		Spec: &v1alpha3.DestinationRule{
+			Host: rsHostname(rs),
+			TrafficPolicy: &v1alpha3.TrafficPolicy{
+				OutlierDetection: outlierDetection(),
+			},
+		},
+	}
+}
+
 // serviceToServiceEntry() creates a ServiceEntry pointing to istio-egressgateway
-func serviceToServiceEntryDirectIngress(rs *v1alpha1.RemoteServiceBinding_RemoteCluster_RemoteService, config istiomodel.Config, serviceEntries map[string]*istiomodel.Config, ip string, port uint32) *istiomodel.Config { // nolint: lll
-	hostname := rsHostname(rs)
-	protocol := "http"
-	serviceEntry, ok := serviceEntries[hostname]
-	if !ok {
+func serviceToServiceEntryDirectIngress(rs *v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService, hostname string, config istiomodel.Config, serviceEntries map[string]*istiomodel.Config, ip string, port uint32, labels map[string]string, opts ConversionOptions) (*istiomodel.Config, error) { // nolint: lll
+	serviceEntry, existing := serviceEntries[hostname]
+	if !existing {
+		ports := make([]*v1alpha3.Port, 0, len(servicePorts(rs.Ports)))
+		for _, p := range servicePorts(rs.Ports) {
+			ports = append(ports, &v1alpha3.Port{
+				Number:   p.Number,
+				Protocol: PortProtocol(p),
+				Name:     PortName(p),
+			})
+		}
 		serviceEntry = &istiomodel.Config{
 			ConfigMeta: istiomodel.ConfigMeta{
 				Type:        istiomodel.ServiceEntry.Type,
@@ -196,14 +331,8 @@ func serviceToServiceEntryDirectIngress(rs *v1alpha1.RemoteServiceBinding_Remote
 				Annotations: annotations(config),
 			},
 			Spec: &v1alpha3.ServiceEntry{
-				Hosts: []string{rsHostname(rs)},
-				Ports: []*v1alpha3.Port{
-					&v1alpha3.Port{
-						Number:   portClientUses(rs),
-						Protocol: "HTTP",
-						Name:     "http",
-					},
-				},
+				Hosts:      []string{hostname},
+				Ports:      ports,
 				Location:   v1alpha3.ServiceEntry_MESH_EXTERNAL,
 				Resolution: v1alpha3.ServiceEntry_STATIC,
 				Endpoints:  []*v1alpha3.ServiceEntry_Endpoint{},
@@ -213,6 +342,27 @@ func serviceToServiceEntryDirectIngress(rs *v1alpha1.RemoteServiceBinding_Remote
 		serviceEntries[hostname] = serviceEntry
 	}
 
+	// Ensure serviceEntry has a VIP, if allocated
+	spec := serviceEntry.Spec.(*v1alpha3.ServiceEntry)
+	if len(spec.Addresses) == 0 {
+		addresses, err := opts.addresses(hostname)
+		if err != nil {
+			return nil, err
+		}
+		spec.Addresses = addresses
+	}
+
+	// All the ports are reached through the ingress gateway of the remote cluster
+	for _, p := range servicePorts(rs.Ports) {
+		mergeEndpoint(serviceEntry, existing, config, ip, PortName(p), port, labels)
+	}
+	return serviceEntry, nil
+}
+
+// mergeEndpoint ensures the ServiceEntry has an endpoint for 'ip', with the labels if any, and records it
+// as contributed by the config
+func mergeEndpoint(serviceEntry *istiomodel.Config, existing bool, config istiomodel.Config, ip, protocol string, port uint32,
+	labels map[string]string) {
 	// Ensure serviceEntry has an endpoint for IP
 	spec := serviceEntry.Spec.(*v1alpha3.ServiceEntry)
 	endpoint := getEndpoint(spec, ip)
@@ -226,10 +376,24 @@ func serviceToServiceEntryDirectIngress(rs *v1alpha1.RemoteServiceBinding_Remote
 
 	// Ensure serviceEntry.Endpoint has a Port for protocol
 	// TODO Check that the port matches and return error otherwise (unmergable)
-	_, ok = endpoint.Ports[protocol]
+	_, ok := endpoint.Ports[protocol]
 	if !ok {
 		endpoint.Ports[protocol] = port
 	}
+	for key, value := range labels {
+		if endpoint.Labels == nil {
+			endpoint.Labels = make(map[string]string)
+		}
+		endpoint.Labels[key] = value
+	}
+
+	// Record the endpoint as contributed by this binding, unless the ServiceEntry
+	// was not generated for multicluster configs in the first place
+	record := GetProvenance(serviceEntry.Annotations)
+	if !existing || record.Len() > 0 {
+		record.Add(ProvenanceAnnotation(config), ip)
+		serviceEntry.Annotations = record.Annotate(serviceEntry.Annotations)
+	}
 
 	// Ensure the endpoints are sorted (not needed for Istio, needed for go tests)
 	sort.Slice(spec.Endpoints, func(i, j int) bool {
@@ -237,8 +401,6 @@ func serviceToServiceEntryDirectIngress(rs *v1alpha1.RemoteServiceBinding_Remote
 		// TODO Ensure the endpoints are stable (e.g. sorted) when there are multiple matching IPs with different protocols/ports
 		// Istio doesn't need them sorted, but the tests expect stable generation
 	})
-
-	return serviceEntry
 }
 
 func getEndpoint(serviceEntry *v1alpha3.ServiceEntry, ip string) *v1alpha3.ServiceEntry_Endpoint {
@@ -251,16 +413,42 @@ func getEndpoint(serviceEntry *v1alpha3.ServiceEntry, ip string) *v1alpha3.Servi
 	return nil
 }
 
-// portClientUses yields the TCP port that clients expect to invoke
-func portClientUses(rs *v1alpha1.RemoteServiceBinding_RemoteCluster_RemoteService) uint32 {
-	if rs.Port == 0 {
-		return 80
-	}
-	return rs.Port
+// portClientUses yields the primary TCP port that clients expect to invoke
+func portClientUses(rs *v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService) uint32 {
+	return primaryPort(rs.Ports).Number
 }
 
-// serviceToDestinationRuleDirectIngress() creates a DestinationRule setting up MUTUAL (not ISTIO_MUTUAL) TLS
-func serviceToDestinationRuleDirectIngress(rs *v1alpha1.RemoteServiceBinding_RemoteCluster_RemoteService, config istiomodel.Config) *istiomodel.Config {
+// serviceToDestinationRuleDirectIngress() creates a DestinationRule setting up TLS to the remote clusters.
+// Services bound with weights or priorities get a subset per cluster and outlier detection.
+// The ports other than the primary one tell the ingress gateway their port by SNI.
+func serviceToDestinationRuleDirectIngress(rs *v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService, hostname string,
+	config istiomodel.Config, opts ConversionOptions, clusters []string, shares []clusterShare) (*istiomodel.Config, error) {
+	subjectAltNames := opts.subjectAltNames(rs, clusters)
+	tls, err := opts.tlsSettings(config, rsAliasHostname(rs), subjectAltNames)
+	if err != nil {
+		return nil, err
+	}
+	rule := &v1alpha3.DestinationRule{
+		Host: hostname,
+		TrafficPolicy: &v1alpha3.TrafficPolicy{
+			Tls: tls,
+		},
+	}
+	for _, p := range extraPorts(rs.Ports) {
+		portTLS, err := opts.tlsSettings(config, portSNIHost(rsAliasHostname(rs), rs.Ports, p), subjectAltNames)
+		if err != nil {
+			return nil, err
+		}
+		rule.TrafficPolicy.PortLevelSettings = append(rule.TrafficPolicy.PortLevelSettings,
+			&v1alpha3.TrafficPolicy_PortTrafficPolicy{
+				Port: &v1alpha3.PortSelector{Port: &v1alpha3.PortSelector_Number{Number: p.Number}},
+				Tls:  portTLS,
+			})
+	}
+	if len(shares) > 0 {
+		rule.TrafficPolicy.OutlierDetection = outlierDetection()
+		rule.Subsets = clusterSubsets(shares)
+	}
 	return &istiomodel.Config{
 		ConfigMeta: istiomodel.ConfigMeta{
 			Type:        istiomodel.DestinationRule.Type,
@@ -270,23 +458,19 @@ func serviceToDestinationRuleDirectIngress(rs *v1alpha1.RemoteServiceBinding_Rem
 			Namespace:   config.Namespace,
 			Annotations: annotations(config),
 		},
-		Spec: &v1alpha3.DestinationRule{
-			Host: rsHostname(rs),
-			TrafficPolicy: &v1alpha3.TrafficPolicy{
-				Tls: &v1alpha3.TLSSettings{
-					Mode:              v1alpha3.TLSSettings_MUTUAL,
-					ClientCertificate: "/etc/certs/cert-chain.pem",
-					PrivateKey:        "/etc/certs/key.pem",
-					CaCertificates:    "/etc/certs/root-cert.pem",
-					Sni:               rsAliasHostname(rs),
-				},
-			},
-		},
-	}
+		Spec: rule,
+	}, nil
 }
 
-// serviceToKubernetesServiceDirectIngress() creates a K8s Service so that DNS resolves to something/anything
-func serviceToKubernetesServiceDirectIngress(rs *v1alpha1.RemoteServiceBinding_RemoteCluster_RemoteService, config istiomodel.Config) *kube_v1.Service {
+// serviceToKubernetesServiceDirectIngress() creates a K8s Service so that DNS resolves the bound host.
+// The Service takes the VIP of the ServiceEntry, if one was allocated, as ClusterIP: the clients then
+// connect to the address the sidecars route. Otherwise the ClusterIP is assigned by the master.
+func serviceToKubernetesServiceDirectIngress(rs *v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService, config istiomodel.Config,
+	se *istiomodel.Config) *kube_v1.Service {
+	var clusterIP string
+	if addresses := se.Spec.(*v1alpha3.ServiceEntry).Addresses; len(addresses) > 0 {
+		clusterIP = addresses[0]
+	}
 	return &kube_v1.Service{
 		TypeMeta: meta_v1.TypeMeta{
 			Kind:       "Service",
@@ -298,35 +482,51 @@ func serviceToKubernetesServiceDirectIngress(rs *v1alpha1.RemoteServiceBinding_R
 			Annotations: annotations(config),
 		},
 		Spec: kube_v1.ServiceSpec{
-			Type: kube_v1.ServiceTypeClusterIP,
-			Ports: []kube_v1.ServicePort{
-				kube_v1.ServicePort{
-					Protocol: "TCP",
-					Port:     int32(portClientUses(rs)),
-				},
-			},
+			Type:      kube_v1.ServiceTypeClusterIP,
+			Ports:     kubernetesServicePorts(rs.Ports),
+			ClusterIP: clusterIP,
 			// No selector
-			// ClusterIP will be assigned by the master
 		},
 	}
 }
 
-func rsAliasHostname(rs *v1alpha1.RemoteServiceBinding_RemoteCluster_RemoteService) string {
+// kubernetesServicePorts returns the ports of the K8s Service of a bound
+// service, named if there are several as K8s requires
+func kubernetesServicePorts(ports []*v1alpha2.ServicePort) []kube_v1.ServicePort {
+	out := make([]kube_v1.ServicePort, 0, len(servicePorts(ports)))
+	for _, p := range servicePorts(ports) {
+		port := kube_v1.ServicePort{
+			Protocol: "TCP",
+			Port:     int32(p.Number),
+		}
+		if len(ports) > 1 {
+			port.Name = PortName(p)
+		}
+		out = append(out, port)
+	}
+	return out
+}
+
+func rsAliasHostname(rs *v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService) string {
 	// We give a .local rather than .global hostname so that we can use a K8s Service
 	// to create the DNS and keep apps from knowing the communication is multi-cluster
 	return fmt.Sprintf("%s.%s.svc.cluster.local", rs.Name, remoteServiceNamespace(rs))
 }
 
-func convertSEPDirectIngress(config istiomodel.Config, sep *v1alpha1.ServiceExpositionPolicy, drs map[string]*istiomodel.Config, ci ClusterInfo) ([]istiomodel.Config, error) {
+// convertSEPDirectIngress converts the services exposed by a SEP, the Services
+// selected by labels among 'svcs', into Istio configuration, including the
+// authorization of the remote clusters they are exposed to
+func convertSEPDirectIngress(config istiomodel.Config, sep *v1alpha2.ServiceExpositionPolicy, drs map[string]*istiomodel.Config,
+	svcs []kube_v1.Service, ci ClusterInfo, opts ConversionOptions) ([]istiomodel.Config, error) {
 	out := make([]istiomodel.Config, 0)
 
-	for _, remote := range sep.Exposed {
+	for _, remote := range ExposedServices(sep, getNamespace(config), svcs) {
 		dr, err := expositionToDestinationRuleDirectIngress(remote, config, drs)
 		if err != nil {
 			return out, err
 		}
 
-		gw, err := expositionToGatewayDirectIngress(remote, config, ci)
+		gw, err := expositionToGatewayDirectIngress(remote, config, ci, opts)
 		if err != nil {
 			return out, err
 		}
@@ -336,26 +536,64 @@ func convertSEPDirectIngress(config istiomodel.Config, sep *v1alpha1.ServiceExpo
 			return out, err
 		}
 
+		authz, err := expositionToAuthorization(remote, config, opts)
+		if err != nil {
+			return out, err
+		}
+
 		out = append(out, *dr, *gw, *vs)
+		out = append(out, authz...)
 	}
 
 	return out, nil
 }
 
+// convertClusterSEP converts the services exposed by a cluster policy in each
+// namespace it applies to, as resolved by the ClusterPolicies of the options.
+// Without them the policy exposes nothing.
+func convertClusterSEP(config istiomodel.Config, drsByNamespace map[string]map[string]*istiomodel.Config,
+	store istiomodel.ConfigStore, ci ClusterInfo, opts ConversionOptions) ([]istiomodel.Config, error) {
+	out := make([]istiomodel.Config, 0)
+	if opts.ClusterPolicies == nil {
+		return out, nil
+	}
+
+	resolved, err := opts.ClusterPolicies.NamespaceExposures(config)
+	if err != nil {
+		return out, err
+	}
+	for _, nsConfig := range resolved {
+		drs, ok := drsByNamespace[nsConfig.Namespace]
+		if !ok {
+			drs, err = mapHostnameToDestinationRule(store, nsConfig.Namespace)
+			if err != nil {
+				return out, err
+			}
+			drsByNamespace[nsConfig.Namespace] = drs
+		}
+		istio, err := convertSEPDirectIngress(nsConfig, nsConfig.Spec.(*v1alpha2.ServiceExpositionPolicy), drs, nil, ci, opts)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, istio...)
+	}
+	return out, nil
+}
+
 // 'drs' maps hostname to DestinationRule and is used to keep track of destinations exposed with different subset and/or alias
-func expositionToDestinationRuleDirectIngress(es *v1alpha1.ServiceExpositionPolicy_ExposedService,
+func expositionToDestinationRuleDirectIngress(es *v1alpha2.ServiceExpositionPolicy_ExposedService,
 	config istiomodel.Config, drs map[string]*istiomodel.Config) (*istiomodel.Config, error) {
-	hostname := fmt.Sprintf("%s.default.svc.cluster.local", es.Name)
+	hostname := exposedServiceHostname(es, config)
 
 	dr, ok := drs[hostname]
 	if !ok {
 		dr = &istiomodel.Config{
 			ConfigMeta: istiomodel.ConfigMeta{
-				Type:    istiomodel.DestinationRule.Type,
-				Group:   istiomodel.DestinationRule.Group + istiomodel.IstioAPIGroupDomain,
-				Version: istiomodel.DestinationRule.Version,
-				Name:    fmt.Sprintf("dest-rule-%s-default-notls", es.Name), // TODO avoid collisions?
-				// Namespace:   config.Namespace,
+				Type:        istiomodel.DestinationRule.Type,
+				Group:       istiomodel.DestinationRule.Group + istiomodel.IstioAPIGroupDomain,
+				Version:     istiomodel.DestinationRule.Version,
+				Name:        fmt.Sprintf("dest-rule-%s-%s-notls", es.Name, getNamespace(config)), // TODO avoid collisions?
+				Namespace:   getNamespace(config),
 				Annotations: annotations(config),
 			},
 			Spec: &v1alpha3.DestinationRule{
@@ -365,6 +603,11 @@ func expositionToDestinationRuleDirectIngress(es *v1alpha1.ServiceExpositionPoli
 		}
 
 		drs[hostname] = dr
+	} else if record := GetProvenance(dr.Annotations); record.Len() > 0 {
+		// The generated rule is shared with the other exposures of the service.
+		// Rules of the user are not recorded as generated.
+		record.Add(ProvenanceAnnotation(config))
+		dr.Annotations = record.Annotate(dr.Annotations)
 	}
 
 	// Ensure dr has a subset named 'notls' or 'notls-<orig>' for the subset
@@ -375,8 +618,8 @@ func expositionToDestinationRuleDirectIngress(es *v1alpha1.ServiceExpositionPoli
 		if es.Subset != "" {
 			origSubset := getSubset(spec, es.Subset)
 			if origSubset == nil {
-				// TODO should we fail or generate a default?
-				// return nil, fmt.Errorf("Exposed subset %q not defined in DestinationRule %s/%s", es.Subset, dr.Namespace, dr.Name)
+				// ValidateSemantics reports the undefined subset, the exposure
+				// selects all the endpoints of the service meanwhile
 				labels = nil
 			} else {
 				labels = origSubset.Labels
@@ -404,8 +647,13 @@ func expositionToDestinationRuleDirectIngress(es *v1alpha1.ServiceExpositionPoli
 	return dr, nil
 }
 
+// exposedServiceHostname returns the hostname of the local service exposed by the SEP
+func exposedServiceHostname(es *v1alpha2.ServiceExpositionPolicy_ExposedService, config istiomodel.Config) string {
+	return fmt.Sprintf("%s.%s.svc.cluster.local", es.Name, getNamespace(config))
+}
+
 // notlsSubsetName returns the name of the Subset to be used for Istio configuration
-func notlsSubsetName(es *v1alpha1.ServiceExpositionPolicy_ExposedService) string {
+func notlsSubsetName(es *v1alpha2.ServiceExpositionPolicy_ExposedService) string {
 	if es.Subset != "" {
 		return fmt.Sprintf("notls-%s", es.Subset)
 	}
@@ -422,16 +670,25 @@ func getSubset(rule *v1alpha3.DestinationRule, name string) *v1alpha3.Subset {
 	return nil
 }
 
-func expositionToGatewayDirectIngress(es *v1alpha1.ServiceExpositionPolicy_ExposedService, config istiomodel.Config, ci ClusterInfo) (*istiomodel.Config, error) {
+func expositionToGatewayDirectIngress(es *v1alpha2.ServiceExpositionPolicy_ExposedService, config istiomodel.Config,
+	ci ClusterInfo, opts ConversionOptions) (*istiomodel.Config, error) {
 	_, port := ci.Gateway()
 
+	// We give a .local rather than .global hostname so that we can use a K8s Service
+	// to create the DNS and keep apps from knowing the communication is multi-cluster
+	host := fmt.Sprintf("%s.%s.svc.cluster.local", exposedServiceName(es), getNamespace(config))
+	hosts := []string{host}
+	for _, p := range extraPorts(es.Ports) {
+		hosts = append(hosts, portSNIHost(host, es.Ports, p))
+	}
+
 	return &istiomodel.Config{
 		ConfigMeta: istiomodel.ConfigMeta{
 			Type:        istiomodel.Gateway.Type,
 			Group:       istiomodel.Gateway.Group + istiomodel.IstioAPIGroupDomain,
 			Version:     istiomodel.Gateway.Version,
 			Name:        exposedServiceGatewayName(es, config),
-			Namespace:   meta_v1.NamespaceDefault, // TODO config.Namespace?
+			Namespace:   getNamespace(config),
 			Annotations: annotations(config),
 		},
 		Spec: &v1alpha3.Gateway{
@@ -442,66 +699,65 @@ func expositionToGatewayDirectIngress(es *v1alpha1.ServiceExpositionPolicy_Expos
 						Protocol: "TLS",
 						Name:     fmt.Sprintf("%s-%s-%d", es.Name, getNamespace(config), 80),
 					},
-					// We give a .local rather than .global hostname so that we can use a K8s Service
-					// to create the DNS and keep apps from knowing the communication is multi-cluster
-					Hosts: []string{fmt.Sprintf("%s.%s.svc.cluster.local", exposedServiceName(es), getNamespace(config))},
+					Hosts: hosts,
 					Tls: &v1alpha3.Server_TLSOptions{
 						Mode: v1alpha3.Server_TLSOptions_PASSTHROUGH,
 					},
 				},
 			},
-			Selector: map[string]string{"istio": "ingressgateway"},
+			Selector: opts.IngressGateway.selector(),
 		},
 	}, nil
 }
 
-// expositionToVirtualServiceDirectIngress() creates a VirtualService with sniHosts
-func expositionToVirtualServiceDirectIngress(es *v1alpha1.ServiceExpositionPolicy_ExposedService, config istiomodel.Config, ci ClusterInfo) (*istiomodel.Config, error) {
+// expositionToVirtualServiceDirectIngress() creates a VirtualService with sniHosts, routing each port of the
+// exposed service by its SNI
+func expositionToVirtualServiceDirectIngress(es *v1alpha2.ServiceExpositionPolicy_ExposedService, config istiomodel.Config, ci ClusterInfo) (*istiomodel.Config, error) {
 	_, port := ci.Gateway()
 
+	hosts := []string{esHostname(config, es)}
+	routes := make([]*v1alpha3.TLSRoute, 0, len(servicePorts(es.Ports)))
+	for _, p := range servicePorts(es.Ports) {
+		sniHost := portSNIHost(esHostname(config, es), es.Ports, p)
+		if sniHost != hosts[0] {
+			hosts = append(hosts, sniHost)
+		}
+		routes = append(routes, &v1alpha3.TLSRoute{
+			Match: []*v1alpha3.TLSMatchAttributes{
+				&v1alpha3.TLSMatchAttributes{
+					SniHosts: []string{sniHost},
+					Port:     port,
+				},
+			},
+			Route: []*v1alpha3.DestinationWeight{
+				&v1alpha3.DestinationWeight{
+					Destination: &v1alpha3.Destination{
+						Host:   exposedServiceHostname(es, config),
+						Subset: notlsSubsetName(es),
+						Port: &v1alpha3.PortSelector{
+							Port: &v1alpha3.PortSelector_Number{
+								Number: p.Number,
+							},
+						},
+					},
+				},
+			},
+		})
+	}
+
 	return &istiomodel.Config{
 		ConfigMeta: istiomodel.ConfigMeta{
 			Type:        istiomodel.VirtualService.Type,
 			Group:       istiomodel.VirtualService.Group + istiomodel.IstioAPIGroupDomain,
 			Version:     istiomodel.VirtualService.Version,
 			Name:        fmt.Sprintf("ingressgateway-to-%s-%s", exposedServiceName(es), getNamespace(config)),
-			Namespace:   meta_v1.NamespaceDefault, // TODO config.Namespace?
+			Namespace:   getNamespace(config),
 			Annotations: annotations(config),
 		},
 		Spec: &v1alpha3.VirtualService{
-			Hosts:    []string{esHostname(config, es)},
+			Hosts:    hosts,
 			Gateways: []string{exposedServiceGatewayName(es, config)},
-			Tls: []*v1alpha3.TLSRoute{
-				&v1alpha3.TLSRoute{
-					Match: []*v1alpha3.TLSMatchAttributes{
-						&v1alpha3.TLSMatchAttributes{
-							SniHosts: []string{esHostname(config, es)},
-							Port:     port,
-						},
-					},
-					Route: []*v1alpha3.DestinationWeight{
-						&v1alpha3.DestinationWeight{
-							Destination: &v1alpha3.Destination{
-								Host:   fmt.Sprintf("%s.%s.svc.cluster.local", es.Name, meta_v1.NamespaceDefault),
-								Subset: notlsSubsetName(es),
-								Port: &v1alpha3.PortSelector{
-									Port: &v1alpha3.PortSelector_Number{
-										Number: portServiceExposes(es),
-									},
-								},
-							},
-						},
-					},
-				},
-			},
+			Tls:      routes,
 		},
 	}, nil
 }
-
-// portServiceExposes yields the TCP port the K8s service listens on
-func portServiceExposes(es *v1alpha1.ServiceExpositionPolicy_ExposedService) uint32 {
-	if es.Port == 0 {
-		return 80
-	}
-	return es.Port
-}
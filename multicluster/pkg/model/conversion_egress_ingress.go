@@ -16,90 +16,70 @@ package model
 
 import (
 	"fmt"
-	"strings"
 
-	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha1"
+	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha2"
 
 	"istio.io/api/networking/v1alpha3"
 	istiomodel "istio.io/istio/pilot/pkg/model"
 
 	multierror "github.com/hashicorp/go-multierror"
-	"k8s.io/api/core/v1"
+	kube_v1 "k8s.io/api/core/v1"
 )
 
-func remoteServiceNamespace(rs *v1alpha1.RemoteServiceBinding_RemoteCluster_RemoteService) string {
+func remoteServiceNamespace(rs *v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService) string {
 	if rs.Namespace != "" {
 		return rs.Namespace
 	}
 
-	return v1.NamespaceDefault
+	return kube_v1.NamespaceDefault
 }
 
-func rsHostname(rs *v1alpha1.RemoteServiceBinding_RemoteCluster_RemoteService) string {
+func rsHostname(rs *v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService) string {
 	// We give a .local rather than .global hostname so that we can use a K8s Service
 	// to create the DNS and keep apps from knowing the communication is multi-cluster
 	return fmt.Sprintf("%s.%s.svc.cluster.local", remoteServiceName(rs), remoteServiceNamespace(rs))
 }
 
-// serviceToServiceEntry() creates a ServiceEntry pointing to istio-egressgateway
-func serviceToServiceEntry(rs *v1alpha1.RemoteServiceBinding_RemoteCluster_RemoteService, config istiomodel.Config) *istiomodel.Config {
+// serviceToServiceEntry() creates a ServiceEntry pointing to the egress gateway
+func serviceToServiceEntry(rs *v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService, config istiomodel.Config,
+	opts ConversionOptions) (*istiomodel.Config, error) {
+	addresses, err := opts.addresses(rsHostname(rs))
+	if err != nil {
+		return nil, err
+	}
+	port := primaryPort(rs.Ports)
 	return &istiomodel.Config{
 		ConfigMeta: istiomodel.ConfigMeta{
 			Type:        istiomodel.ServiceEntry.Type,
 			Group:       istiomodel.ServiceEntry.Group + istiomodel.IstioAPIGroupDomain,
 			Version:     istiomodel.ServiceEntry.Version,
-			Name:        fmt.Sprintf("service-entry-%s", config.Name), // TODO avoid collisions?
+			Name:        fmt.Sprintf("service-entry-%s", remoteServiceName(rs)),
 			Namespace:   config.Namespace,
 			Annotations: annotations(config),
 		},
 		Spec: &v1alpha3.ServiceEntry{
-			Hosts: []string{rsHostname(rs)},
+			Hosts:     []string{rsHostname(rs)},
+			Addresses: addresses,
 			Ports: []*v1alpha3.Port{
 				&v1alpha3.Port{
-					Number:   80,
-					Protocol: "HTTP",
-					Name:     "http",
+					Number:   port.Number,
+					Protocol: PortProtocol(port),
+					Name:     PortName(port),
 				},
 			},
 			Location:   v1alpha3.ServiceEntry_MESH_EXTERNAL,
 			Resolution: v1alpha3.ServiceEntry_DNS,
 			Endpoints: []*v1alpha3.ServiceEntry_Endpoint{
 				&v1alpha3.ServiceEntry_Endpoint{
-					Address: fmt.Sprintf("istio-egressgateway.%s.svc.cluster.local", IstioSystemNamespace),
-					Ports:   map[string]uint32{"http": 80},
-				},
-			},
-		},
-	}
-}
-
-// serviceToDestinationRule() creates a DestinationRule setting up MUTUAL (not ISTIO_MUTUAL) TLS
-func serviceToDestinationRule(rs *v1alpha1.RemoteServiceBinding_RemoteCluster_RemoteService, config istiomodel.Config) *istiomodel.Config {
-	return &istiomodel.Config{
-		ConfigMeta: istiomodel.ConfigMeta{
-			Type:        istiomodel.DestinationRule.Type,
-			Group:       istiomodel.DestinationRule.Group + istiomodel.IstioAPIGroupDomain,
-			Version:     istiomodel.DestinationRule.Version,
-			Name:        fmt.Sprintf("dest-rule-%s-%s", config.Name, rs.Namespace), // TODO avoid collisions?
-			Namespace:   config.Namespace,
-			Annotations: annotations(config),
-		},
-		Spec: &v1alpha3.DestinationRule{
-			Host: rsHostname(rs),
-			TrafficPolicy: &v1alpha3.TrafficPolicy{
-				Tls: &v1alpha3.TLSSettings{
-					Mode:              v1alpha3.TLSSettings_MUTUAL,
-					ClientCertificate: "/etc/certs/cert-chain.pem",
-					PrivateKey:        "/etc/certs/key.pem",
-					CaCertificates:    "/etc/certs/root-cert.pem",
-					Sni:               rsHostname(rs),
+					Address: opts.EgressGateway.Hostname(),
+					Ports:   map[string]uint32{PortName(port): opts.EgressGateway.Port},
 				},
 			},
 		},
-	}
+	}, nil
 }
 
-func remoteServiceName(rs *v1alpha1.RemoteServiceBinding_RemoteCluster_RemoteService) string {
+func remoteServiceName(rs *v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService) string {
 	if rs.Alias != "" {
 		return rs.Alias
 	}
@@ -107,18 +87,19 @@ func remoteServiceName(rs *v1alpha1.RemoteServiceBinding_RemoteCluster_RemoteSer
 	return rs.Name
 }
 
-func bindingGatewayName(rs *v1alpha1.RemoteServiceBinding_RemoteCluster_RemoteService) string {
-	return fmt.Sprintf("istio-egressgateway-%s-%s", rs.Name, rs.Namespace)
+func bindingGatewayName(rs *v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService) string {
+	return fmt.Sprintf("istio-egressgateway-%s-%s", rs.Name, remoteServiceNamespace(rs))
 }
 
 // serviceToGateway() creates a Gateway with TLS PASSTHROUGH
-func serviceToGateway(rs *v1alpha1.RemoteServiceBinding_RemoteCluster_RemoteService, config istiomodel.Config) *istiomodel.Config {
+func serviceToGateway(rs *v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService, config istiomodel.Config,
+	opts ConversionOptions) *istiomodel.Config {
 	return &istiomodel.Config{
 		ConfigMeta: istiomodel.ConfigMeta{
 			Type:        istiomodel.Gateway.Type,
 			Group:       istiomodel.Gateway.Group + istiomodel.IstioAPIGroupDomain,
 			Version:     istiomodel.Gateway.Version,
-			Name:        bindingGatewayName(rs), // TODO avoid collisions?
+			Name:        bindingGatewayName(rs),
 			Namespace:   config.Namespace,
 			Annotations: annotations(config),
 		},
@@ -126,52 +107,51 @@ func serviceToGateway(rs *v1alpha1.RemoteServiceBinding_RemoteCluster_RemoteServ
 			Servers: []*v1alpha3.Server{
 				&v1alpha3.Server{
 					Port: &v1alpha3.Port{
-						Number:   80,
+						Number:   opts.EgressGateway.Port,
 						Protocol: "TLS",
-						Name:     fmt.Sprintf("%s-%s-%d", remoteServiceName(rs), rs.Namespace, 80),
+						Name:     fmt.Sprintf("%s-%s-%d", rs.Name, remoteServiceNamespace(rs), opts.EgressGateway.Port),
 					},
-					Hosts: []string{rsHostname(rs)},
+					Hosts: []string{rsAliasHostname(rs)},
 					Tls: &v1alpha3.Server_TLSOptions{
 						Mode: v1alpha3.Server_TLSOptions_PASSTHROUGH,
 					},
 				},
 			},
-			Selector: map[string]string{"istio": "egressgateway"},
+			Selector: opts.EgressGateway.selector(),
 		},
 	}
 }
 
-// serviceToVirtualService() creates a VirtualService with sniHosts
-func serviceToVirtualService(remote *v1alpha1.RemoteServiceBinding_RemoteCluster,
-	rs *v1alpha1.RemoteServiceBinding_RemoteCluster_RemoteService,
-	config istiomodel.Config) *istiomodel.Config {
+// serviceToVirtualService() creates a VirtualService with sniHosts routing to the ingress gateways of the remote clusters
+func serviceToVirtualService(rs *v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService, config istiomodel.Config,
+	opts ConversionOptions) *istiomodel.Config {
 	return &istiomodel.Config{
 		ConfigMeta: istiomodel.ConfigMeta{
 			Type:        istiomodel.VirtualService.Type,
 			Group:       istiomodel.VirtualService.Group + istiomodel.IstioAPIGroupDomain,
 			Version:     istiomodel.VirtualService.Version,
-			Name:        fmt.Sprintf("egressgateway-to-ingressgateway-%s-%s", rs.Name, rs.Namespace), // TODO avoid collisions?
+			Name:        fmt.Sprintf("egressgateway-to-ingressgateway-%s-%s", rs.Name, remoteServiceNamespace(rs)),
 			Namespace:   config.Namespace,
 			Annotations: annotations(config),
 		},
 		Spec: &v1alpha3.VirtualService{
-			Hosts:    []string{rsHostname(rs)},
+			Hosts:    []string{rsAliasHostname(rs)},
 			Gateways: []string{bindingGatewayName(rs)},
 			Tls: []*v1alpha3.TLSRoute{
 				&v1alpha3.TLSRoute{
 					Match: []*v1alpha3.TLSMatchAttributes{
 						&v1alpha3.TLSMatchAttributes{
-							SniHosts: []string{rsHostname(rs)},
-							Port:     80,
+							SniHosts: []string{rsAliasHostname(rs)},
+							Port:     opts.EgressGateway.Port,
 						},
 					},
 					Route: []*v1alpha3.DestinationWeight{
 						&v1alpha3.DestinationWeight{
 							Destination: &v1alpha3.Destination{
-								Host: clusterHostname(remote),
+								Host: remoteGatewaysHostname(rs),
 								Port: &v1alpha3.PortSelector{
 									Port: &v1alpha3.PortSelector_Number{
-										Number: 80,
+										Number: opts.EgressGateway.Port,
 									},
 								},
 							},
@@ -183,87 +163,101 @@ func serviceToVirtualService(remote *v1alpha1.RemoteServiceBinding_RemoteCluster
 	}
 }
 
-// clusterToServiceEntry() creates a ServiceEntry pointing to a remote cluster
-func clusterToServiceEntry(remote *v1alpha1.RemoteServiceBinding_RemoteCluster,
-	ip string, port uint32, config istiomodel.Config) *istiomodel.Config {
-	return &istiomodel.Config{
-		ConfigMeta: istiomodel.ConfigMeta{
-			Type:        istiomodel.ServiceEntry.Type,
-			Group:       istiomodel.ServiceEntry.Group + istiomodel.IstioAPIGroupDomain,
-			Version:     istiomodel.ServiceEntry.Version,
-			Name:        fmt.Sprintf("service-entry-ingress-gateway-%s", remote.Cluster),
-			Namespace:   config.Namespace,
-			Annotations: annotations(config),
-		},
-		Spec: &v1alpha3.ServiceEntry{
-			Hosts:     []string{clusterHostname(remote)},
-			Addresses: []string{"127.8.8.8"}, // dummy
-			Ports: []*v1alpha3.Port{
-				&v1alpha3.Port{
-					Number:   80,
-					Protocol: "TCP",
-					Name:     "tcp",
-				},
+// remoteGatewaysHostname is the host the egress gateway forwards calls to a remote service to.
+// It only names the ServiceEntry of the remote ingress gateways: the calls pass through with
+// the SNI of the .local hostname, so it follows Istio's .global convention for remote services.
+func remoteGatewaysHostname(rs *v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService) string {
+	return fmt.Sprintf("%s.%s.global", rs.Name, remoteServiceNamespace(rs))
+}
+
+// serviceToRemoteGatewaysServiceEntry() creates a ServiceEntry pointing to the ingress gateways of
+// the remote clusters.  'serviceEntries' maps hostname to the ServiceEntries of the namespace and is
+// used to balance the calls among every cluster the service is bound from.  The ServiceEntry port
+// matches the egress gateway; the port of each remote gateway is set on its endpoint.
+func serviceToRemoteGatewaysServiceEntry(rs *v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService, config istiomodel.Config,
+	serviceEntries map[string]*istiomodel.Config, ip string, port uint32, opts ConversionOptions) *istiomodel.Config {
+	hostname := remoteGatewaysHostname(rs)
+	protocol := "tls"
+	serviceEntry, existing := serviceEntries[hostname]
+	if !existing {
+		serviceEntry = &istiomodel.Config{
+			ConfigMeta: istiomodel.ConfigMeta{
+				Type:        istiomodel.ServiceEntry.Type,
+				Group:       istiomodel.ServiceEntry.Group + istiomodel.IstioAPIGroupDomain,
+				Version:     istiomodel.ServiceEntry.Version,
+				Name:        fmt.Sprintf("service-entry-ingress-gateways-%s-%s", rs.Name, remoteServiceNamespace(rs)),
+				Namespace:   config.Namespace,
+				Annotations: annotations(config),
 			},
-			Location:   v1alpha3.ServiceEntry_MESH_EXTERNAL,
-			Resolution: v1alpha3.ServiceEntry_DNS,
-			Endpoints: []*v1alpha3.ServiceEntry_Endpoint{
-				&v1alpha3.ServiceEntry_Endpoint{
-					Address: ip,
-					Ports:   map[string]uint32{"tcp": port},
+			Spec: &v1alpha3.ServiceEntry{
+				Hosts: []string{hostname},
+				Ports: []*v1alpha3.Port{
+					&v1alpha3.Port{
+						Number:   opts.EgressGateway.Port,
+						Protocol: "TLS",
+						Name:     protocol,
+					},
 				},
+				Location:   v1alpha3.ServiceEntry_MESH_EXTERNAL,
+				Resolution: v1alpha3.ServiceEntry_STATIC,
+				Endpoints:  []*v1alpha3.ServiceEntry_Endpoint{},
 			},
-		},
+		}
+
+		serviceEntries[hostname] = serviceEntry
 	}
+
+	mergeEndpoint(serviceEntry, existing, config, ip, protocol, port, nil)
+	return serviceEntry
 }
 
-func convertRSB(config istiomodel.Config, rsb *v1alpha1.RemoteServiceBinding, ci ClusterInfo) ([]istiomodel.Config, error) {
+func convertRSB(config istiomodel.Config, rsb *v1alpha2.RemoteServiceBinding, serviceEntries map[string]*istiomodel.Config,
+	ci ClusterInfo, opts ConversionOptions) ([]istiomodel.Config, []kube_v1.Service, error) {
 	out := make([]istiomodel.Config, 0)
+	outSvcs := make([]kube_v1.Service, 0)
+
+	if rsb.Mode == v1alpha2.RemoteServiceBinding_FALLBACK {
+		return nil, nil, fmt.Errorf("%s bindings are not supported by the %s style", rsb.Mode, EgressIngressStyle)
+	}
 
+	clusters := bindingClusters(rsb)
 	for _, remote := range rsb.Remote {
 		for _, svc := range remote.Services {
-			out = append(out, *serviceToServiceEntry(svc, config))
-			out = append(out, *serviceToDestinationRule(svc, config))
-			out = append(out, *serviceToGateway(svc, config))
-			out = append(out, *serviceToVirtualService(remote, svc, config))
+			// The egress gateway forwards a single port per bound service
+			if len(svc.Ports) > 1 {
+				return nil, nil, fmt.Errorf("binding %s with several ports is not supported by the %s style",
+					remoteServiceName(svc), EgressIngressStyle)
+			}
+			dr, err := serviceToDestinationRuleDirectIngress(svc, rsHostname(svc), config, opts, clusters[rsHostname(svc)], nil)
+			if err != nil {
+				return nil, nil, err
+			}
+			se, err := serviceToServiceEntry(svc, config, opts)
+			if err != nil {
+				return nil, nil, err
+			}
+			out = append(out, *se, *dr)
+			out = append(out, *serviceToGateway(svc, config, opts))
+			out = append(out, *serviceToVirtualService(svc, config, opts))
+			if svc.Policy != nil {
+				// The sidecars apply the route policy of the donor cluster
+				vs, err := serviceToVirtualServiceDirectIngress(svc, config,
+					weightedRoute(rsHostname(svc), portClientUses(svc), nil), svc.Policy)
+				if err != nil {
+					return nil, nil, err
+				}
+				out = append(out, *vs)
+			}
+			out = append(out, *serviceToRemoteGatewaysServiceEntry(svc, config, serviceEntries,
+				ci.IP(remote.Cluster), ci.Port(remote.Cluster), opts))
+			outSvcs = append(outSvcs, *serviceToKubernetesServiceDirectIngress(svc, config, se))
 		}
-		out = append(out, *clusterToServiceEntry(remote, ci.IP(remote.Cluster), ci.Port(remote.Cluster), config))
 	}
 
-	return out, nil
-}
-
-func clusterHostname(remote *v1alpha1.RemoteServiceBinding_RemoteCluster) string {
-	return fmt.Sprintf("%s.myorg", strings.ToLower(remote.Cluster))
-}
-
-func expositionToDestinationRule(es *v1alpha1.ServiceExpositionPolicy_ExposedService, config istiomodel.Config) (*istiomodel.Config, error) {
-	return &istiomodel.Config{
-		ConfigMeta: istiomodel.ConfigMeta{
-			Type:        istiomodel.DestinationRule.Type,
-			Group:       istiomodel.DestinationRule.Group + istiomodel.IstioAPIGroupDomain,
-			Version:     istiomodel.DestinationRule.Version,
-			Name:        fmt.Sprintf("dest-rule-%s-default-notls", es.Name), // TODO avoid collisions?
-			Namespace:   config.Namespace,
-			Annotations: annotations(config),
-		},
-		Spec: &v1alpha3.DestinationRule{
-			Host: fmt.Sprintf("%s.default.svc.cluster.local", es.Name),
-			Subsets: []*v1alpha3.Subset{
-				&v1alpha3.Subset{
-					Name: "notls",
-					TrafficPolicy: &v1alpha3.TrafficPolicy{
-						Tls: &v1alpha3.TLSSettings{
-							Mode: v1alpha3.TLSSettings_DISABLE,
-						},
-					},
-				},
-			},
-		},
-	}, nil
+	return out, outSvcs, nil
 }
 
-func exposedServiceName(es *v1alpha1.ServiceExpositionPolicy_ExposedService) string {
+func exposedServiceName(es *v1alpha2.ServiceExpositionPolicy_ExposedService) string {
 	if es.Alias != "" {
 		return es.Alias
 	}
@@ -271,7 +265,7 @@ func exposedServiceName(es *v1alpha1.ServiceExpositionPolicy_ExposedService) str
 	return es.Name
 }
 
-func exposedServiceGatewayName(es *v1alpha1.ServiceExpositionPolicy_ExposedService, config istiomodel.Config) string {
+func exposedServiceGatewayName(es *v1alpha2.ServiceExpositionPolicy_ExposedService, config istiomodel.Config) string {
 	return fmt.Sprintf("istio-ingressgateway-%s-%s", exposedServiceName(es), getNamespace(config)) // TODO avoid collisions?
 }
 
@@ -280,146 +274,97 @@ func getNamespace(config istiomodel.Config) string {
 		return config.Namespace
 	}
 
-	return v1.NamespaceDefault
+	return kube_v1.NamespaceDefault
 }
 
-func expositionToGateway(es *v1alpha1.ServiceExpositionPolicy_ExposedService, config istiomodel.Config) (*istiomodel.Config, error) {
-	return &istiomodel.Config{
-		ConfigMeta: istiomodel.ConfigMeta{
-			Type:        istiomodel.Gateway.Type,
-			Group:       istiomodel.Gateway.Group + istiomodel.IstioAPIGroupDomain,
-			Version:     istiomodel.Gateway.Version,
-			Name:        exposedServiceGatewayName(es, config),
-			Namespace:   config.Namespace,
-			Annotations: annotations(config),
-		},
-		Spec: &v1alpha3.Gateway{
-			Servers: []*v1alpha3.Server{
-				&v1alpha3.Server{
-					Port: &v1alpha3.Port{
-						Number:   80,
-						Protocol: "TLS",
-						Name:     fmt.Sprintf("%s-%s-%d", es.Name, getNamespace(config), 80),
-					},
-					// We give a .local rather than .global hostname so that we can use a K8s Service
-					// to create the DNS and keep apps from knowing the communication is multi-cluster
-					Hosts: []string{fmt.Sprintf("%s.%s.svc.cluster.local", exposedServiceName(es), getNamespace(config))},
-					Tls: &v1alpha3.Server_TLSOptions{
-						Mode: v1alpha3.Server_TLSOptions_PASSTHROUGH,
-					},
-				},
-			},
-			Selector: map[string]string{"istio": "ingressgateway"},
-		},
-	}, nil
-}
-
-func esHostname(config istiomodel.Config, es *v1alpha1.ServiceExpositionPolicy_ExposedService) string {
+func esHostname(config istiomodel.Config, es *v1alpha2.ServiceExpositionPolicy_ExposedService) string {
 	// We give a .local rather than .global hostname so that we can use a K8s Service
 	// to create the DNS and keep apps from knowing the communication is multi-cluster
 	return fmt.Sprintf("%s.%s.svc.cluster.local", exposedServiceName(es), getNamespace(config))
 }
 
-// expositionToVirtualService() creates a VirtualService with sniHosts
-func expositionToVirtualService(es *v1alpha1.ServiceExpositionPolicy_ExposedService, config istiomodel.Config) (*istiomodel.Config, error) {
-	return &istiomodel.Config{
-		ConfigMeta: istiomodel.ConfigMeta{
-			Type:        istiomodel.VirtualService.Type,
-			Group:       istiomodel.VirtualService.Group + istiomodel.IstioAPIGroupDomain,
-			Version:     istiomodel.VirtualService.Version,
-			Name:        fmt.Sprintf("ingressgateway-to-%s-%s", es.Name, getNamespace(config)),
-			Namespace:   config.Namespace,
-			Annotations: annotations(config),
-		},
-		Spec: &v1alpha3.VirtualService{
-			Hosts:    []string{esHostname(config, es)},
-			Gateways: []string{exposedServiceGatewayName(es, config)},
-			Tls: []*v1alpha3.TLSRoute{
-				&v1alpha3.TLSRoute{
-					Match: []*v1alpha3.TLSMatchAttributes{
-						&v1alpha3.TLSMatchAttributes{
-							SniHosts: []string{esHostname(config, es)},
-							Port:     80,
-						},
-					},
-					Route: []*v1alpha3.DestinationWeight{
-						&v1alpha3.DestinationWeight{
-							Destination: &v1alpha3.Destination{
-								Host:   fmt.Sprintf("%s.%s.svc.cluster.local", es.Name, getNamespace(config)),
-								Subset: "notls",
-								Port: &v1alpha3.PortSelector{
-									Port: &v1alpha3.PortSelector_Number{
-										Number: 80,
-									},
-								},
-							},
-						},
-					},
-				},
-			},
-		},
-	}, nil
-}
-
-func convertSEP(config istiomodel.Config, sep *v1alpha1.ServiceExpositionPolicy) ([]istiomodel.Config, error) {
+// ConvertBindingsAndExposuresEgressIngress converts a list of multicluster SEP and RSB configuration
+// into Istio configuration routing calls to remote clusters through the local egress gateway.
+// It may consult existing Istio configuration in 'store' (e.g. DestinationRule subsets)
+func ConvertBindingsAndExposuresEgressIngress(mcs []istiomodel.Config, ci ClusterInfo, store istiomodel.ConfigStore, existingSvcs []kube_v1.Service, opts ConversionOptions) ([]istiomodel.Config, []kube_v1.Service, error) { // nolint: lll
+	opts = opts.WithDefaults()
 	out := make([]istiomodel.Config, 0)
+	outServices := make([]kube_v1.Service, 0)
 
-	for _, remote := range sep.Exposed {
-		dr, err := expositionToDestinationRule(remote, config)
-		if err != nil {
-			return out, err
-		}
-
-		gw, err := expositionToGateway(remote, config)
-		if err != nil {
-			return out, err
-		}
-
-		vs, err := expositionToVirtualService(remote, config)
-		if err != nil {
-			return out, err
-		}
-
-		out = append(out, *dr, *gw, *vs)
-	}
-
-	return out, nil
-}
-
-// ConvertBindingsAndExposuresEgressIngress converts multicluster desired state into Istio state
-func ConvertBindingsAndExposuresEgressIngress(mcs []istiomodel.Config, ci ClusterInfo) ([]istiomodel.Config, error) {
-	out := make([]istiomodel.Config, 0)
+	// Maps of namespace -> hostname -> DestinationRule (needed for merging for subsets) and
+	// namespace -> hostname -> ServiceEntry (needed for merging for multiple remote gateways)
+	drsByNamespace := make(map[string]map[string]*istiomodel.Config)
+	sesByNamespace := make(map[string]map[string]*istiomodel.Config)
 
+	// Process each Multicluster Config SEP or RSB
 	for _, mc := range mcs {
 		var istio []istiomodel.Config
+		var svcs []kube_v1.Service
 		var err error
-		rsb, ok := mc.Spec.(*v1alpha1.RemoteServiceBinding)
+		rsb, ok := mc.Spec.(*v1alpha2.RemoteServiceBinding)
 		if ok {
-			istio, err = convertRSB(mc, rsb, ci)
+			ns := getNamespace(mc)
+			ses, ok := sesByNamespace[ns]
+			if !ok {
+				ses, err = mapHostnameToServiceEntry(store, ns)
+				if err != nil {
+					return nil, nil, err
+				}
+				sesByNamespace[ns] = ses
+			}
+			withoutSourceEndpoints(ses, mc)
+			// Bindings awaiting approval are not realized
+			if BindingApproved(mc) {
+				istio, svcs, err = convertRSB(mc, rsb, ses, ci, opts)
+			}
 		}
-		sep, ok := mc.Spec.(*v1alpha1.ServiceExpositionPolicy)
+		sep, ok := mc.Spec.(*v1alpha2.ServiceExpositionPolicy)
 		if ok {
-			istio, err = convertSEP(mc, sep)
+			ns := getNamespace(mc)
+			drs, ok := drsByNamespace[ns]
+			if !ok {
+				drs, err = mapHostnameToDestinationRule(store, ns)
+				if err != nil {
+					return nil, nil, err
+				}
+				drsByNamespace[ns] = drs
+			}
+			istio, err = convertSEPDirectIngress(mc, sep, drs, existingSvcs, ci, opts)
+		}
+		if _, ok := mc.Spec.(*v1alpha2.ClusterServiceExpositionPolicy); ok {
+			istio, err = convertClusterSEP(mc, drsByNamespace, store, ci, opts)
 		}
 		if err != nil {
-			return out, multierror.Prefix(err, "Could not convert")
+			return out, outServices, multierror.Prefix(err, "Could not convert")
 		}
 		out = append(out, istio...)
+		outServices = append(outServices, svcs...)
 	}
 
-	return out, nil
+	return uniquifyIstio(out), uniquifyServices(outServices), nil
 }
 
-// ProvenanceAnnotation() returns the annotation value that traces back to a configuration
+// provenanceKinds abbreviates the types of the configs in their ProvenanceAnnotation()
+var provenanceKinds = map[string]string{
+	ServiceExpositionPolicy.Type:        "sep",
+	RemoteServiceBinding.Type:           "rsb",
+	ClusterServiceExpositionPolicy.Type: "csep",
+}
+
+// ProvenanceAnnotation() returns the annotation value that traces back to a configuration:
+// "<kind>/<namespace>.<name>", so that configs of different kinds may share a name
 func ProvenanceAnnotation(config istiomodel.Config) string {
-	return fmt.Sprintf("%s.%s", namespace(config), config.Name)
+	if config.Type == ClusterServiceExpositionPolicy.Type {
+		return clusterProvenance(config)
+	}
+	return fmt.Sprintf("%s/%s.%s", provenanceKinds[config.Type], namespace(config), config.Name)
 }
 
 func namespace(config istiomodel.Config) string {
 	if config.Namespace != "" {
 		return config.Namespace
 	}
-	return v1.NamespaceDefault
+	return kube_v1.NamespaceDefault
 }
 
 func annotations(config istiomodel.Config) map[string]string {
@@ -0,0 +1,283 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+
+	"istio.io/api/networking/v1alpha3"
+	istiomodel "istio.io/istio/pilot/pkg/model"
+
+	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha2"
+
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+const (
+	// DefaultIngressGatewayService is the K8s Service of the ingress gateway of a default Istio install
+	DefaultIngressGatewayService = "istio-ingressgateway"
+
+	// DefaultEgressGatewayService is the K8s Service of the egress gateway of a default Istio install
+	DefaultEgressGatewayService = "istio-egressgateway"
+
+	// DefaultEgressGatewayPort is the TLS port of the egress gateway of a default Istio install
+	DefaultEgressGatewayPort = 443
+
+	// TLSModeAnnotationKey is the key to an annotation on a RemoteServiceBinding
+	// overriding the TLS mode of the cluster's ConversionOptions
+	TLSModeAnnotationKey = "multicluster.istio.io/tls-mode"
+
+	// TLSModeMutual presents the certificates of TLSOptions to remote clusters
+	TLSModeMutual = "MUTUAL"
+
+	// TLSModeIstioMutual presents the Istio certificates of the sidecar to
+	// remote clusters, which then must share the root of trust
+	TLSModeIstioMutual = "ISTIO_MUTUAL"
+)
+
+// ConversionOptions describes the local Istio install that the generated Istio
+// configuration depends on. Unset values default to a default Istio install,
+// see WithDefaults().
+type ConversionOptions struct {
+	// ControlPlaneNamespace is the namespace where the Istio components run
+	ControlPlaneNamespace string `yaml:"ControlPlaneNamespace,omitempty"`
+
+	// IngressGateway is the gateway exposing local services to remote clusters
+	IngressGateway GatewayOptions `yaml:"IngressGateway,omitempty"`
+
+	// EgressGateway is the gateway that calls to remote clusters go through
+	// with the EGRESS_INGRESS style
+	EgressGateway GatewayOptions `yaml:"EgressGateway,omitempty"`
+
+	// TLS holds the credentials presented to the ingress gateways of remote clusters
+	TLS TLSOptions `yaml:"TLS,omitempty"`
+
+	// TrustDomains maps remote cluster IDs to their trust domain. The
+	// identities of services bound from those clusters are verified.
+	TrustDomains map[string]string `yaml:"-"`
+
+	// ClusterGroups are the groups of clusters that the clusters lists of the
+	// exposed services may refer to
+	ClusterGroups ClusterGroups `yaml:"-"`
+
+	// VIPs, if set, allocates the virtual IPs of the ServiceEntries of bound
+	// remote services. Without it the ServiceEntries have no addresses.
+	VIPs VIPAllocator `yaml:"-"`
+
+	// Readiness, if set, tells whether local K8s Services have ready endpoints.
+	// FALLBACK bindings only route to the remote clusters once the local Service
+	// has none. Without it local Services are assumed ready.
+	Readiness ServiceReadiness `yaml:"-"`
+
+	// ClusterPolicies, if set, resolves where ClusterServiceExpositionPolicies
+	// apply. Without it they expose nothing.
+	ClusterPolicies ClusterPolicyScope `yaml:"-"`
+}
+
+// VIPAllocator hands out stable virtual IPs to the hosts of generated
+// ServiceEntries, so that TCP clients without SNI or Host headers can be
+// routed by destination address
+type VIPAllocator interface {
+	// Allocate returns the VIP of the host, allocating one if it has none
+	Allocate(host string) (string, error)
+}
+
+// ServiceReadiness tells whether local K8s Services can serve requests
+type ServiceReadiness interface {
+	// Ready returns true if the Service of the namespace has a ready endpoint
+	Ready(namespace, name string) bool
+}
+
+// localReady returns true if the local K8s Service has ready endpoints
+func (o ConversionOptions) localReady(namespace, name string) bool {
+	return o.Readiness == nil || o.Readiness.Ready(namespace, name)
+}
+
+// GatewayOptions identifies an Istio gateway deployment
+type GatewayOptions struct {
+	// Service is the name of the K8s Service of the gateway
+	Service string `yaml:"Service,omitempty"`
+
+	// Namespace is the namespace of the gateway, by default the control-plane namespace
+	Namespace string `yaml:"Namespace,omitempty"`
+
+	// Selector holds the labels of the gateway pods used by Gateway configs
+	Selector map[string]string `yaml:"Selector,omitempty"`
+
+	// Port is the TLS port of the gateway's K8s Service that sidecars call.  Only
+	// the egress gateway uses it; remote ingress gateways advertise their port
+	// in the cluster configuration.
+	Port uint32 `yaml:"Port,omitempty"`
+}
+
+// TLSOptions holds the credentials used for mutual TLS with remote clusters
+type TLSOptions struct {
+	// Mode is TLSModeMutual (the default) or TLSModeIstioMutual
+	Mode string `yaml:"Mode,omitempty"`
+
+	// CredentialName names the secret holding the certificates when served
+	// by SDS rather than mounted as files. The DestinationRule API of the
+	// Istio version supported here has no credentialName yet, so it is
+	// rejected by Validate().
+	CredentialName string `yaml:"CredentialName,omitempty"`
+
+	// Paths, in the sidecar or gateway proxy, of the certificate files for TLSModeMutual
+	ClientCertificate string `yaml:"ClientCertificate,omitempty"`
+	PrivateKey        string `yaml:"PrivateKey,omitempty"`
+	CaCertificates    string `yaml:"CaCertificates,omitempty"`
+}
+
+// DefaultConversionOptions returns the options for a default Istio install
+func DefaultConversionOptions() ConversionOptions {
+	return ConversionOptions{}.WithDefaults()
+}
+
+// WithDefaults returns a copy of the options with the unset values defaulted
+func (o ConversionOptions) WithDefaults() ConversionOptions {
+	if o.ControlPlaneNamespace == "" {
+		o.ControlPlaneNamespace = IstioSystemNamespace
+	}
+	o.IngressGateway = o.IngressGateway.withDefaults(DefaultIngressGatewayService, "ingressgateway", o.ControlPlaneNamespace)
+	o.EgressGateway = o.EgressGateway.withDefaults(DefaultEgressGatewayService, "egressgateway", o.ControlPlaneNamespace)
+	if o.EgressGateway.Port == 0 {
+		o.EgressGateway.Port = DefaultEgressGatewayPort
+	}
+	if o.TLS.ClientCertificate == "" {
+		o.TLS.ClientCertificate = "/etc/certs/cert-chain.pem"
+	}
+	if o.TLS.PrivateKey == "" {
+		o.TLS.PrivateKey = "/etc/certs/key.pem"
+	}
+	if o.TLS.CaCertificates == "" {
+		o.TLS.CaCertificates = "/etc/certs/root-cert.pem"
+	}
+	return o
+}
+
+// Validate checks the options can be realized
+func (o ConversionOptions) Validate() error {
+	if err := validateTLSMode(o.TLS.Mode); err != nil {
+		return err
+	}
+	if o.TLS.CredentialName != "" {
+		return fmt.Errorf("TLS credentialName %q is not supported: the Istio DestinationRule API in use has no SDS credentials",
+			o.TLS.CredentialName)
+	}
+	return nil
+}
+
+func validateTLSMode(mode string) error {
+	switch mode {
+	case "", TLSModeMutual, TLSModeIstioMutual:
+		return nil
+	}
+	return fmt.Errorf("unknown TLS mode %q, expected %s or %s", mode, TLSModeMutual, TLSModeIstioMutual)
+}
+
+// tlsSettings returns the client TLS settings for a service bound by the
+// RemoteServiceBinding, which may override the TLS mode
+func (o ConversionOptions) tlsSettings(config istiomodel.Config, sni string, subjectAltNames []string) (*v1alpha3.TLSSettings, error) {
+	mode := o.TLS.Mode
+	if override, ok := config.Annotations[TLSModeAnnotationKey]; ok {
+		mode = override
+	}
+	if err := validateTLSMode(mode); err != nil {
+		return nil, err
+	}
+
+	if mode == TLSModeIstioMutual {
+		return &v1alpha3.TLSSettings{
+			Mode:            v1alpha3.TLSSettings_ISTIO_MUTUAL,
+			SubjectAltNames: subjectAltNames,
+			Sni:             sni,
+		}, nil
+	}
+	return &v1alpha3.TLSSettings{
+		Mode:              v1alpha3.TLSSettings_MUTUAL,
+		ClientCertificate: o.TLS.ClientCertificate,
+		PrivateKey:        o.TLS.PrivateKey,
+		CaCertificates:    o.TLS.CaCertificates,
+		SubjectAltNames:   subjectAltNames,
+		Sni:               sni,
+	}, nil
+}
+
+// addresses returns the ServiceEntry addresses for a host, nil unless VIPs are allocated
+func (o ConversionOptions) addresses(host string) ([]string, error) {
+	if o.VIPs == nil {
+		return nil, nil
+	}
+	vip, err := o.VIPs.Allocate(host)
+	if err != nil {
+		return nil, multierror.Prefix(err, fmt.Sprintf("no VIP for %s:", host))
+	}
+	return []string{vip}, nil
+}
+
+// subjectAltNames returns the SPIFFE identities the remote service may have
+// in the trust domains of the clusters binding it. The remote workloads are
+// expected to run as a service account named after the service.
+func (o ConversionOptions) subjectAltNames(rs *v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService,
+	clusters []string) []string {
+	names := make(map[string]bool)
+	for _, cluster := range clusters {
+		if td := o.TrustDomains[cluster]; td != "" {
+			names[fmt.Sprintf("spiffe://%s/ns/%s/sa/%s", td, remoteServiceNamespace(rs), rs.Name)] = true
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return sortedKeys(names)
+}
+
+// bindingClusters maps the local hostnames of the services of the binding to
+// the remote clusters they are bound from
+func bindingClusters(rsb *v1alpha2.RemoteServiceBinding) map[string][]string {
+	out := make(map[string][]string)
+	for _, remote := range rsb.Remote {
+		for _, svc := range remote.Services {
+			out[rsHostname(svc)] = append(out[rsHostname(svc)], remote.Cluster)
+		}
+	}
+	return out
+}
+
+func (g GatewayOptions) withDefaults(service, istioLabel, namespace string) GatewayOptions {
+	if g.Service == "" {
+		g.Service = service
+	}
+	if g.Namespace == "" {
+		g.Namespace = namespace
+	}
+	if len(g.Selector) == 0 {
+		g.Selector = map[string]string{"istio": istioLabel}
+	}
+	return g
+}
+
+// Hostname returns the cluster-local hostname of the gateway's K8s Service
+func (g GatewayOptions) Hostname() string {
+	return fmt.Sprintf("%s.%s.svc.cluster.local", g.Service, g.Namespace)
+}
+
+// selector returns a copy of the gateway selector for a generated Gateway
+func (g GatewayOptions) selector() map[string]string {
+	out := make(map[string]string, len(g.Selector))
+	for k, v := range g.Selector {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,103 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	istiomodel "istio.io/istio/pilot/pkg/model"
+
+	kube_v1 "k8s.io/api/core/v1"
+)
+
+// ConversionStyle converts multicluster desired state into the Istio and
+// Kubernetes state implementing it. Styles differ in how the traffic between
+// clusters flows, e.g. directly to the remote ingress gateway or through a
+// local egress gateway.
+type ConversionStyle interface {
+	// Convert converts the SEPs and RSBs. It may consult the existing Istio
+	// configuration in 'store' and the existing K8s Services 'svcs' to merge
+	// with them.
+	Convert(mcs []istiomodel.Config, ci ClusterInfo, store istiomodel.ConfigStore, svcs []kube_v1.Service,
+		opts ConversionOptions) ([]istiomodel.Config, []kube_v1.Service, error)
+}
+
+// ConversionStyleFunc is an adapter to use a function as a ConversionStyle
+type ConversionStyleFunc func(mcs []istiomodel.Config, ci ClusterInfo, store istiomodel.ConfigStore,
+	svcs []kube_v1.Service, opts ConversionOptions) ([]istiomodel.Config, []kube_v1.Service, error)
+
+// Convert calls f
+func (f ConversionStyleFunc) Convert(mcs []istiomodel.Config, ci ClusterInfo, store istiomodel.ConfigStore,
+	svcs []kube_v1.Service, opts ConversionOptions) ([]istiomodel.Config, []kube_v1.Service, error) {
+	return f(mcs, ci, store, svcs, opts)
+}
+
+var (
+	stylesMutex sync.RWMutex
+	styles      = make(map[string]ConversionStyle)
+)
+
+func init() {
+	RegisterConversionStyle(DirectIngressStyle, ConversionStyleFunc(ConvertBindingsAndExposuresDirectIngress))
+	RegisterConversionStyle(EgressIngressStyle, ConversionStyleFunc(ConvertBindingsAndExposuresEgressIngress))
+}
+
+// RegisterConversionStyle makes a style available by name. It panics if the
+// name is already registered.
+func RegisterConversionStyle(name string, style ConversionStyle) {
+	stylesMutex.Lock()
+	defer stylesMutex.Unlock()
+	if style == nil {
+		panic("conversion style " + name + " is nil")
+	}
+	if _, dup := styles[name]; dup {
+		panic("conversion style " + name + " is registered twice")
+	}
+	styles[name] = style
+}
+
+// GetConversionStyle returns the style registered with the name, or the
+// DefaultConversionStyle for an empty name
+func GetConversionStyle(name string) (ConversionStyle, error) {
+	if name == "" {
+		name = DefaultConversionStyle
+	}
+	stylesMutex.RLock()
+	defer stylesMutex.RUnlock()
+	style, ok := styles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown conversion style %q, expected one of %s", name, strings.Join(conversionStyles(), "|"))
+	}
+	return style, nil
+}
+
+// ConversionStyles returns the sorted names of the registered styles
+func ConversionStyles() []string {
+	stylesMutex.RLock()
+	defer stylesMutex.RUnlock()
+	return conversionStyles()
+}
+
+func conversionStyles() []string {
+	out := make([]string, 0, len(styles))
+	for name := range styles {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
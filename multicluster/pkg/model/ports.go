@@ -0,0 +1,87 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha2"
+)
+
+// PortProtocols are the protocols of the ports of the exposed and bound
+// services, named like the protocols of the Istio ServiceEntry ports
+var PortProtocols = map[string]bool{
+	"HTTP":  true,
+	"HTTP2": true,
+	"GRPC":  true,
+	"TCP":   true,
+}
+
+const (
+	// defaultPort is the port of the services exposed or bound without ports
+	defaultPort = 80
+
+	// defaultProtocol is the protocol of the ports without one
+	defaultProtocol = "HTTP"
+)
+
+// servicePorts returns the ports of a service, the default port if it has none
+func servicePorts(ports []*v1alpha2.ServicePort) []*v1alpha2.ServicePort {
+	if len(ports) == 0 {
+		return []*v1alpha2.ServicePort{{Number: defaultPort}}
+	}
+	return ports
+}
+
+// primaryPort returns the first port of a service, which the weights,
+// fallback and route policies of the bindings apply to
+func primaryPort(ports []*v1alpha2.ServicePort) *v1alpha2.ServicePort {
+	return servicePorts(ports)[0]
+}
+
+// extraPorts returns the ports of a service other than its primary port
+func extraPorts(ports []*v1alpha2.ServicePort) []*v1alpha2.ServicePort {
+	if len(ports) < 2 {
+		return nil
+	}
+	return ports[1:]
+}
+
+// PortProtocol returns the protocol of a port, HTTP by default
+func PortProtocol(port *v1alpha2.ServicePort) string {
+	if port.Protocol == "" {
+		return defaultProtocol
+	}
+	return port.Protocol
+}
+
+// PortName returns the name of a port, its lower case protocol by default
+func PortName(port *v1alpha2.ServicePort) string {
+	if port.Name == "" {
+		return strings.ToLower(PortProtocol(port))
+	}
+	return port.Name
+}
+
+// portSNIHost returns the SNI the requests to a port of a service exposed
+// under the host use through the ingress gateway. The primary port uses the
+// host itself, the others are told apart by their number.
+func portSNIHost(host string, ports []*v1alpha2.ServicePort, port *v1alpha2.ServicePort) string {
+	if port.Number == primaryPort(ports).Number {
+		return host
+	}
+	return fmt.Sprintf("%d.%s", port.Number, host)
+}
@@ -0,0 +1,206 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"sort"
+	"strings"
+
+	"istio.io/api/networking/v1alpha3"
+	istiomodel "istio.io/istio/pilot/pkg/model"
+)
+
+// Provenance is the record, kept in the ProvenanceAnnotationKey annotation, of
+// the multicluster configs a generated resource was realized for. A resource
+// may be shared by several configs, e.g. a ServiceEntry merging the endpoints
+// of services bound from several remote clusters, and is only removed with
+// its last source.
+//
+// The annotation value lists the sources separated by ';'. Each source is the
+// value of ProvenanceAnnotation() for the config, optionally followed by '='
+// and the ServiceEntry endpoint addresses it contributed separated by ','.
+// For example "rsb/default.reviews=10.0.0.1;rsb/default.reviews-b=10.0.0.2".
+//
+// Sources recorded before they named the kind of their config, such as
+// "default.reviews", are still found by the source of any config of that
+// namespace and name, and are replaced by it when it is recorded again.
+type Provenance struct {
+	sources map[string]map[string]bool
+}
+
+// ParseProvenance parses the value of a provenance annotation. Values written
+// before endpoints were recorded hold a single source and are parsed as such.
+func ParseProvenance(value string) *Provenance {
+	p := &Provenance{sources: make(map[string]map[string]bool)}
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		endpoints := []string{}
+		if len(parts) == 2 && parts[1] != "" {
+			endpoints = strings.Split(parts[1], ",")
+		}
+		p.Add(parts[0], endpoints...)
+	}
+	return p
+}
+
+// GetProvenance returns the provenance record of a resource's annotations
+func GetProvenance(annotations map[string]string) *Provenance {
+	return ParseProvenance(annotations[ProvenanceAnnotationKey])
+}
+
+// Len returns the number of sources
+func (p *Provenance) Len() int {
+	return len(p.sources)
+}
+
+// Has returns true if the source is recorded
+func (p *Provenance) Has(source string) bool {
+	_, ok := p.recorded(source)
+	return ok
+}
+
+// Endpoints returns the sorted endpoint addresses contributed by the source
+func (p *Provenance) Endpoints(source string) []string {
+	recorded, _ := p.recorded(source)
+	return sortedKeys(p.sources[recorded])
+}
+
+// Add records the source, and the endpoint addresses it contributed, keeping
+// what was already recorded for it
+func (p *Provenance) Add(source string, endpoints ...string) {
+	if recorded, ok := p.recorded(source); ok && recorded != source {
+		// Replace the legacy source
+		p.sources[source] = p.sources[recorded]
+		delete(p.sources, recorded)
+	}
+	if _, ok := p.sources[source]; !ok {
+		p.sources[source] = make(map[string]bool)
+	}
+	for _, endpoint := range endpoints {
+		p.sources[source][endpoint] = true
+	}
+}
+
+// Remove forgets the source. It returns the endpoint addresses that were only
+// contributed by this source.
+func (p *Provenance) Remove(source string) []string {
+	recorded, ok := p.recorded(source)
+	if !ok {
+		return []string{}
+	}
+	endpoints := p.sources[recorded]
+	delete(p.sources, recorded)
+
+	orphaned := make([]string, 0)
+	for _, endpoint := range sortedKeys(endpoints) {
+		shared := false
+		for _, other := range p.sources {
+			if other[endpoint] {
+				shared = true
+				break
+			}
+		}
+		if !shared {
+			orphaned = append(orphaned, endpoint)
+		}
+	}
+	return orphaned
+}
+
+// recorded returns how the source is recorded: as is or, if only recorded
+// before sources named their kind, in the legacy form
+func (p *Provenance) recorded(source string) (string, bool) {
+	if _, ok := p.sources[source]; ok {
+		return source, true
+	}
+	if i := strings.Index(source, "/"); i >= 0 {
+		if _, ok := p.sources[source[i+1:]]; ok {
+			return source[i+1:], true
+		}
+	}
+	return "", false
+}
+
+// String formats the record as an annotation value. Sources and endpoints are
+// sorted so that the value is stable.
+func (p *Provenance) String() string {
+	entries := make([]string, 0, len(p.sources))
+	sources := make([]string, 0, len(p.sources))
+	for source := range p.sources {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	for _, source := range sources {
+		entry := source
+		if endpoints := p.Endpoints(source); len(endpoints) > 0 {
+			entry += "=" + strings.Join(endpoints, ",")
+		}
+		entries = append(entries, entry)
+	}
+	return strings.Join(entries, ";")
+}
+
+// Annotate returns a copy of the annotations holding this record
+func (p *Provenance) Annotate(annotations map[string]string) map[string]string {
+	out := make(map[string]string, len(annotations)+1)
+	for k, v := range annotations {
+		out[k] = v
+	}
+	if p.Len() == 0 {
+		delete(out, ProvenanceAnnotationKey)
+	} else {
+		out[ProvenanceAnnotationKey] = p.String()
+	}
+	return out
+}
+
+// WithoutSource returns a copy of the Istio config no longer realized for the
+// multicluster config. ServiceEntry endpoints only the config contributed are
+// removed as well. The config's Spec is not modified.
+func WithoutSource(config istiomodel.Config, mc istiomodel.Config) istiomodel.Config {
+	record := GetProvenance(config.Annotations)
+	orphaned := record.Remove(ProvenanceAnnotation(mc))
+	config.Annotations = record.Annotate(config.Annotations)
+
+	se, ok := config.Spec.(*v1alpha3.ServiceEntry)
+	if ok && len(orphaned) > 0 {
+		removed := make(map[string]bool, len(orphaned))
+		for _, address := range orphaned {
+			removed[address] = true
+		}
+		newSpec := *se
+		newSpec.Endpoints = make([]*v1alpha3.ServiceEntry_Endpoint, 0, len(se.Endpoints))
+		for _, endpoint := range se.Endpoints {
+			if !removed[endpoint.Address] {
+				newSpec.Endpoints = append(newSpec.Endpoints, endpoint)
+			}
+		}
+		config.Spec = &newSpec
+	}
+	return config
+}
+
+func sortedKeys(set map[string]bool) []string {
+	out := make([]string, 0, len(set))
+	for key := range set {
+		out = append(out, key)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,116 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	istiomodel "istio.io/istio/pilot/pkg/model"
+
+	kube_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha2"
+)
+
+// SelectsServices returns true if the policy exposes the K8s Services
+// selected by labels, i.e. if its exposures change with the Services
+func SelectsServices(sep *v1alpha2.ServiceExpositionPolicy) bool {
+	for _, es := range sep.Exposed {
+		if len(es.Selector) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ExposedServices returns the services exposed by the policy in the
+// namespace. The exposures selecting K8s Services by labels are replaced by
+// one exposure per selected Service of 'svcs', in the order of their names.
+func ExposedServices(sep *v1alpha2.ServiceExpositionPolicy, namespace string,
+	svcs []kube_v1.Service) []*v1alpha2.ServiceExpositionPolicy_ExposedService {
+	if !SelectsServices(sep) {
+		return sep.Exposed
+	}
+
+	out := make([]*v1alpha2.ServiceExpositionPolicy_ExposedService, 0, len(sep.Exposed))
+	for _, es := range sep.Exposed {
+		if len(es.Selector) == 0 {
+			out = append(out, es)
+			continue
+		}
+		for _, svc := range selectedServices(es.Selector, namespace, svcs) {
+			out = append(out, selectedExposure(es, svc))
+		}
+	}
+	return out
+}
+
+// selectedServices returns the K8s Services of the namespace matching the
+// labels, sorted by name. The Services created by the agent are never selected.
+func selectedServices(selector map[string]string, namespace string, svcs []kube_v1.Service) []*kube_v1.Service {
+	sel := labels.SelectorFromSet(labels.Set(selector))
+	out := make([]*kube_v1.Service, 0)
+	for i, svc := range svcs {
+		if svc.Namespace == namespace && GetProvenance(svc.Annotations).Len() == 0 &&
+			sel.Matches(labels.Set(svc.Labels)) {
+			out = append(out, &svcs[i])
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// selectedExposure returns the exposure of a K8s Service selected by the
+// labels of 'es'. The Service is exposed under the alias of 'es' suffixed by
+// its name, on the ports of 'es' or else on its own ports.
+func selectedExposure(es *v1alpha2.ServiceExpositionPolicy_ExposedService,
+	svc *kube_v1.Service) *v1alpha2.ServiceExpositionPolicy_ExposedService {
+	out := &v1alpha2.ServiceExpositionPolicy_ExposedService{
+		Name:             svc.Name,
+		Ports:            es.Ports,
+		Clusters:         es.Clusters,
+		ExcludedClusters: es.ExcludedClusters,
+	}
+	if es.Alias != "" {
+		out.Alias = fmt.Sprintf("%s-%s", es.Alias, svc.Name)
+	}
+	if len(out.Ports) == 0 {
+		out.Ports = kubernetesPorts(svc)
+	}
+	return out
+}
+
+// kubernetesPorts returns the ports of a K8s Service. Their protocol is told
+// by the prefix of their name as Istio does, TCP if it isn't a known one.
+func kubernetesPorts(svc *kube_v1.Service) []*v1alpha2.ServicePort {
+	out := make([]*v1alpha2.ServicePort, 0, len(svc.Spec.Ports))
+	for _, p := range svc.Spec.Ports {
+		port := &v1alpha2.ServicePort{
+			Number:   uint32(p.Port),
+			Protocol: "TCP",
+		}
+		if istiomodel.IsDNS1123Label(p.Name) {
+			port.Name = p.Name
+		}
+		if protocol := strings.ToUpper(strings.SplitN(p.Name, "-", 2)[0]); PortProtocols[protocol] {
+			port.Protocol = protocol
+		}
+		out = append(out, port)
+	}
+	return out
+}
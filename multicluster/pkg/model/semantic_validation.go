@@ -0,0 +1,321 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"sort"
+
+	multierror "github.com/hashicorp/go-multierror"
+
+	"istio.io/api/networking/v1alpha3"
+	istiomodel "istio.io/istio/pilot/pkg/model"
+
+	kube_v1 "k8s.io/api/core/v1"
+
+	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha2"
+)
+
+// FieldError is an error of a field of a Multi-cluster config found by
+// ValidateSemantics
+type FieldError struct {
+	// Kind of the config, e.g. "ServiceExpositionPolicy"
+	Kind      string
+	Namespace string
+	Name      string
+
+	// Field is the path of the field, e.g. "spec.exposed[1].alias"
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s %s/%s: %s: %s", e.Kind, e.Namespace, e.Name, e.Field, e.Message)
+}
+
+// ClusterRegistry tells the clusters known to the local cluster
+type ClusterRegistry interface {
+	// IsPeer returns true if the cluster ID names a peer of the local cluster
+	IsPeer(id string) bool
+
+	// ConversionStyleName returns the name of the conversion style the
+	// Multi-cluster config is realized with
+	ConversionStyleName(config istiomodel.Config) (string, error)
+}
+
+// ValidateSemantics checks the SEPs and RSBs against each other and against
+// the cluster state, beyond what the schema validation of each config does:
+//   - two services exposed under the same alias in a namespace, reported for
+//     the most recent exposure
+//   - an exposed subset not defined by the DestinationRule of the service
+//   - an exposed port the K8s Service does not have
+//   - an alias derived for a Service selected by labels that is too long
+//   - a binding from a cluster that is not a peer
+//   - a binding with an unknown approval phase
+//   - a binding setting weights, a mode or several ports its conversion style
+//     does not support
+//
+// The DestinationRules are read from the store and the peers from the
+// registry; the checks are skipped if they are nil. Ports are only checked,
+// and selectors only expanded, for the Services of the list. The errors are
+// *FieldError, combined with multierror.
+func ValidateSemantics(mcs []istiomodel.Config, clusters ClusterRegistry, store istiomodel.ConfigStore,
+	svcs []kube_v1.Service) error {
+	// Sort the configs so the oldest of two conflicting exposures is kept.
+	// Configs not created yet, e.g. under admission, come last.
+	sorted := make([]istiomodel.Config, len(mcs))
+	copy(sorted, mcs)
+	sort.Slice(sorted, func(i, j int) bool {
+		ti, tj := sorted[i].CreationTimestamp, sorted[j].CreationTimestamp
+		if !ti.Equal(&tj) {
+			return !ti.IsZero() && (tj.IsZero() || ti.Before(&tj))
+		}
+		if sorted[i].Namespace != sorted[j].Namespace {
+			return sorted[i].Namespace < sorted[j].Namespace
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	var errs error
+	aliases := make(map[string]exposure)
+	for _, config := range sorted {
+		switch spec := config.Spec.(type) {
+		case *v1alpha2.ServiceExpositionPolicy:
+			errs = appendErrors(errs, validateExposures(config, spec, aliases, store, svcs))
+		case *v1alpha2.RemoteServiceBinding:
+			errs = appendErrors(errs, validateBindingClusters(config, spec, clusters))
+			errs = appendErrors(errs, validateBindingStyle(config, spec, clusters))
+			errs = appendErrors(errs, validateBindingApproval(config))
+		}
+	}
+	return errs
+}
+
+// FieldErrorsOf returns the errors of ValidateSemantics about a config
+func FieldErrorsOf(err error, config istiomodel.Config) []*FieldError {
+	var out []*FieldError
+	for _, err := range flattenErrors(err) {
+		if fe, ok := err.(*FieldError); ok && fe.Namespace == getNamespace(config) && fe.Name == config.Name &&
+			fe.Kind == configKind(config) {
+			out = append(out, fe)
+		}
+	}
+	return out
+}
+
+func flattenErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+	merr, ok := err.(*multierror.Error)
+	if !ok {
+		return []error{err}
+	}
+	var out []error
+	for _, err := range merr.Errors {
+		out = append(out, flattenErrors(err)...)
+	}
+	return out
+}
+
+// exposure is a service exposed by a SEP, recorded under its alias
+type exposure struct {
+	config istiomodel.Config
+	es     *v1alpha2.ServiceExpositionPolicy_ExposedService
+}
+
+func validateExposures(config istiomodel.Config, sep *v1alpha2.ServiceExpositionPolicy, aliases map[string]exposure,
+	store istiomodel.ConfigStore, svcs []kube_v1.Service) error {
+	var errs error
+	namespace := getNamespace(config)
+	for i, entry := range sep.Exposed {
+		field := fmt.Sprintf("spec.exposed[%d]", i)
+		nameField := field + ".name"
+		exposed := []*v1alpha2.ServiceExpositionPolicy_ExposedService{entry}
+		if len(entry.Selector) > 0 {
+			nameField = field + ".selector"
+			exposed = ExposedServices(&v1alpha2.ServiceExpositionPolicy{
+				Exposed: exposed,
+			}, namespace, svcs)
+		}
+		for _, es := range exposed {
+			errs = appendErrors(errs, validateExposure(config, field, nameField, es, aliases, store, svcs))
+		}
+	}
+	return errs
+}
+
+// validateExposure checks a service exposed by the entry of a SEP at 'field'.
+// The name of the service is set by 'nameField', a selector or its name.
+func validateExposure(config istiomodel.Config, field, nameField string, es *v1alpha2.ServiceExpositionPolicy_ExposedService,
+	aliases map[string]exposure, store istiomodel.ConfigStore, svcs []kube_v1.Service) error {
+	var errs error
+	namespace := getNamespace(config)
+	if len(es.Alias) > 63 {
+		errs = appendErrors(errs, fieldError(config, field+".alias", "alias %q of service %q is too long",
+			es.Alias, es.Name))
+	}
+
+	key := namespace + "/" + exposedServiceName(es)
+	if first, ok := aliases[key]; !ok {
+		aliases[key] = exposure{config: config, es: es}
+	} else if first.es.Name != es.Name || first.es.Subset != es.Subset {
+		aliasField := field + ".alias"
+		if es.Alias == "" {
+			aliasField = nameField
+		}
+		errs = appendErrors(errs, fieldError(config, aliasField, "%q already exposes %s by %s %s/%s",
+			exposedServiceName(es), describeExposed(first.es), configKind(first.config),
+			getNamespace(first.config), first.config.Name))
+	}
+
+	if es.Subset != "" && store != nil {
+		errs = appendErrors(errs, validateExposedSubset(config, field+".subset", es, store))
+	}
+
+	if svc := localService(svcs, namespace, es.Name); svc != nil {
+		for j, port := range es.Ports {
+			if !hasPort(svc, port.Number) {
+				errs = appendErrors(errs, fieldError(config, fmt.Sprintf("%s.ports[%d].number", field, j),
+					"port %d is not a port of K8s Service %s/%s", port.Number, svc.Namespace, svc.Name))
+			}
+		}
+	}
+	return errs
+}
+
+// validateExposedSubset checks that the DestinationRule of the exposed
+// service defines the subset. The rules generated for Multi-cluster configs
+// are not considered.
+func validateExposedSubset(config istiomodel.Config, field string, es *v1alpha2.ServiceExpositionPolicy_ExposedService,
+	store istiomodel.ConfigStore) error {
+	namespace := getNamespace(config)
+	drs, err := store.List(istiomodel.DestinationRule.Type, namespace)
+	if err != nil {
+		return err
+	}
+	for _, dr := range drs {
+		spec, ok := dr.Spec.(*v1alpha3.DestinationRule)
+		if !ok || GetProvenance(dr.Annotations).Len() > 0 || !IsServiceHost(spec.Host, es.Name, namespace) {
+			continue
+		}
+		if getSubset(spec, es.Subset) == nil {
+			return fieldError(config, field, "subset %q is not defined in DestinationRule %s/%s",
+				es.Subset, dr.Namespace, dr.Name)
+		}
+		return nil
+	}
+	return fieldError(config, field, "subset %q is not defined: service %q has no DestinationRule", es.Subset, es.Name)
+}
+
+func validateBindingClusters(config istiomodel.Config, rsb *v1alpha2.RemoteServiceBinding, clusters ClusterRegistry) error {
+	if clusters == nil {
+		return nil
+	}
+	var errs error
+	for i, remote := range rsb.Remote {
+		if remote.Cluster != "" && !clusters.IsPeer(remote.Cluster) {
+			errs = appendErrors(errs, fieldError(config, fmt.Sprintf("spec.remote[%d].cluster", i),
+				"cluster %q is not a peer in the cluster configuration", remote.Cluster))
+		}
+	}
+	return errs
+}
+
+// validateBindingStyle checks that the conversion style of the binding can
+// realize it. The EGRESS_INGRESS style routes every request through the
+// egress gateway: it cannot split them among the clusters by weight, fall
+// back to a local Service, or forward more than one port of a service.
+func validateBindingStyle(config istiomodel.Config, rsb *v1alpha2.RemoteServiceBinding, clusters ClusterRegistry) error {
+	if clusters == nil {
+		return nil
+	}
+	style, err := clusters.ConversionStyleName(config)
+	if err != nil {
+		return fieldError(config, "spec.remote", "%v", err)
+	}
+	if style != EgressIngressStyle {
+		return nil
+	}
+	var errs error
+	if rsb.Mode == v1alpha2.RemoteServiceBinding_FALLBACK {
+		errs = appendErrors(errs, fieldError(config, "spec.mode",
+			"%s bindings are not supported by the %s conversion style", rsb.Mode, style))
+	}
+	for i, remote := range rsb.Remote {
+		if remote.Weight != 0 {
+			errs = appendErrors(errs, fieldError(config, fmt.Sprintf("spec.remote[%d].weight", i),
+				"weights are not supported by the %s conversion style", style))
+		}
+		for j, svc := range remote.Services {
+			if len(svc.Ports) > 1 {
+				errs = appendErrors(errs, fieldError(config, fmt.Sprintf("spec.remote[%d].services[%d].ports", i, j),
+					"several ports are not supported by the %s conversion style", style))
+			}
+			if svc.Weight != 0 {
+				errs = appendErrors(errs, fieldError(config, fmt.Sprintf("spec.remote[%d].services[%d].weight", i, j),
+					"weights are not supported by the %s conversion style", style))
+			}
+		}
+	}
+	return errs
+}
+
+// IsServiceHost returns true if the host, as written in an Istio config of
+// the namespace, refers to the K8s Service of that name and namespace
+func IsServiceHost(host, name, namespace string) bool {
+	switch host {
+	case name, name + "." + namespace, name + "." + namespace + ".svc",
+		name + "." + namespace + ".svc.cluster.local":
+		return true
+	}
+	return false
+}
+
+func hasPort(svc *kube_v1.Service, port uint32) bool {
+	for _, p := range svc.Spec.Ports {
+		if uint32(p.Port) == port {
+			return true
+		}
+	}
+	return false
+}
+
+func describeExposed(es *v1alpha2.ServiceExpositionPolicy_ExposedService) string {
+	if es.Subset != "" {
+		return fmt.Sprintf("service %q subset %q", es.Name, es.Subset)
+	}
+	return fmt.Sprintf("service %q", es.Name)
+}
+
+func fieldError(config istiomodel.Config, field, format string, args ...interface{}) *FieldError {
+	return &FieldError{
+		Kind:      configKind(config),
+		Namespace: getNamespace(config),
+		Name:      config.Name,
+		Field:     field,
+		Message:   fmt.Sprintf(format, args...),
+	}
+}
+
+func configKind(config istiomodel.Config) string {
+	switch config.Spec.(type) {
+	case *v1alpha2.ServiceExpositionPolicy:
+		return "ServiceExpositionPolicy"
+	case *v1alpha2.RemoteServiceBinding:
+		return "RemoteServiceBinding"
+	}
+	return config.Type
+}
@@ -0,0 +1,341 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha2"
+
+	types "github.com/gogo/protobuf/types"
+	multierror "github.com/hashicorp/go-multierror"
+	"istio.io/api/networking/v1alpha3"
+	istiomodel "istio.io/istio/pilot/pkg/model"
+
+	kube_v1 "k8s.io/api/core/v1"
+)
+
+// ClusterLabelKey labels the ServiceEntry endpoints of a service bound with
+// weights with the remote cluster they reach, so that
+// DestinationRule subsets can select the endpoints of each cluster
+const ClusterLabelKey = "cluster"
+
+// clusterShare is the weight a binding gives to a remote cluster for one of
+// the services bound from it
+type clusterShare struct {
+	cluster string
+	weight  uint32
+}
+
+// bindingShares maps the local hostnames of the services of the binding to
+// the shares of the remote clusters they are bound from.  Services for which
+// neither they nor their clusters set a weight are left out; they are load
+// balanced over the endpoints of all their clusters.
+func bindingShares(rsb *v1alpha2.RemoteServiceBinding) map[string][]clusterShare {
+	out := make(map[string][]clusterShare)
+	weighted := make(map[string]bool)
+	for _, remote := range rsb.Remote {
+		for _, svc := range remote.Services {
+			hostname := rsHostname(svc)
+			if hasShare(out[hostname], remote.Cluster) {
+				continue
+			}
+			// The weight of a service overrides the one of its cluster
+			weight := remote.Weight
+			if svc.Weight != 0 {
+				weight = svc.Weight
+			}
+			out[hostname] = append(out[hostname], clusterShare{
+				cluster: remote.Cluster,
+				weight:  weight,
+			})
+			if weight != 0 {
+				weighted[hostname] = true
+			}
+		}
+	}
+	for hostname := range out {
+		if !weighted[hostname] {
+			delete(out, hostname)
+		}
+	}
+	return out
+}
+
+func hasShare(shares []clusterShare, cluster string) bool {
+	for _, share := range shares {
+		if share.cluster == cluster {
+			return true
+		}
+	}
+	return false
+}
+
+// clusterLabels returns the labels of the ServiceEntry endpoints reaching the
+// cluster, nil if the service is not bound with weights
+func clusterLabels(shares []clusterShare, cluster string) map[string]string {
+	if len(shares) == 0 {
+		return nil
+	}
+	return map[string]string{ClusterLabelKey: cluster}
+}
+
+// clusterSubsetName is the name of the DestinationRule subset of the endpoints reaching the cluster
+func clusterSubsetName(cluster string) string {
+	return strings.ToLower(cluster)
+}
+
+// clusterSubsets returns a DestinationRule subset per cluster binding the service
+func clusterSubsets(shares []clusterShare) []*v1alpha3.Subset {
+	subsets := make([]*v1alpha3.Subset, 0, len(shares))
+	for _, share := range shares {
+		subsets = append(subsets, &v1alpha3.Subset{
+			Name:   clusterSubsetName(share.cluster),
+			Labels: map[string]string{ClusterLabelKey: share.cluster},
+		})
+	}
+	return subsets
+}
+
+// outlierDetection ejects the remote ingress gateways failing to serve a bound
+// service, so that the requests go to the gateways of the other clusters
+func outlierDetection() *v1alpha3.OutlierDetection {
+	return &v1alpha3.OutlierDetection{
+		ConsecutiveErrors:  5,
+		Interval:           types.DurationProto(10 * time.Second),
+		BaseEjectionTime:   types.DurationProto(30 * time.Second),
+		MaxEjectionPercent: 100,
+	}
+}
+
+// routeWeights splits 100 percent of the requests among the shares in
+// proportion to their weights, or evenly if no share has a weight.  The
+// rounding remainder goes to the first shares.
+func routeWeights(shares []clusterShare) []int32 {
+	var total uint32
+	for _, share := range shares {
+		total += share.weight
+	}
+
+	weights := make([]int32, len(shares))
+	remainder := int32(100)
+	for i, share := range shares {
+		if total == 0 {
+			weights[i] = int32(100 / len(shares))
+		} else {
+			weights[i] = int32(uint64(share.weight) * 100 / uint64(total))
+		}
+		remainder -= weights[i]
+	}
+	for i := 0; remainder > 0; i = (i + 1) % len(weights) {
+		if total == 0 || shares[i].weight > 0 {
+			weights[i]++
+			remainder--
+		}
+	}
+	return weights
+}
+
+// weightedRoute splits the requests to the host among the subsets of the
+// clusters of the shares, or sends them all to the host without shares
+func weightedRoute(hostname string, port uint32, shares []clusterShare) []*v1alpha3.DestinationWeight {
+	destination := func(subset string) *v1alpha3.Destination {
+		return &v1alpha3.Destination{
+			Host:   hostname,
+			Subset: subset,
+			Port: &v1alpha3.PortSelector{
+				Port: &v1alpha3.PortSelector_Number{
+					Number: port,
+				},
+			},
+		}
+	}
+	if len(shares) == 0 {
+		return []*v1alpha3.DestinationWeight{{Destination: destination("")}}
+	}
+
+	weights := routeWeights(shares)
+	route := make([]*v1alpha3.DestinationWeight, 0, len(shares))
+	for i, share := range shares {
+		if weights[i] == 0 {
+			continue
+		}
+		route = append(route, &v1alpha3.DestinationWeight{
+			Destination: destination(clusterSubsetName(share.cluster)),
+			Weight:      weights[i],
+		})
+	}
+	if len(route) == 1 {
+		route[0].Weight = 0
+	}
+	return route
+}
+
+// serviceToVirtualServiceDirectIngress() creates a VirtualService routing the
+// requests to a bound service, with the route policy of the donor cluster if any
+func serviceToVirtualServiceDirectIngress(rs *v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService, config istiomodel.Config,
+	route []*v1alpha3.DestinationWeight, policy *v1alpha2.RoutePolicy) (*istiomodel.Config, error) {
+	httpRoute := &v1alpha3.HTTPRoute{
+		Route: route,
+	}
+	if err := applyRoutePolicy(httpRoute, policy); err != nil {
+		return nil, multierror.Prefix(err, fmt.Sprintf("invalid route policy of %s:", rs.Name))
+	}
+	return &istiomodel.Config{
+		ConfigMeta: istiomodel.ConfigMeta{
+			Type:        istiomodel.VirtualService.Type,
+			Group:       istiomodel.VirtualService.Group + istiomodel.IstioAPIGroupDomain,
+			Version:     istiomodel.VirtualService.Version,
+			Name:        fmt.Sprintf("virtual-service-%s", remoteServiceName(rs)),
+			Namespace:   config.Namespace,
+			Annotations: annotations(config),
+		},
+		Spec: &v1alpha3.VirtualService{
+			Hosts: []string{rsHostname(rs)},
+			Http:  []*v1alpha3.HTTPRoute{httpRoute},
+		},
+	}, nil
+}
+
+// applyRoutePolicy sets the timeout, retries and faults of the policy on the route
+func applyRoutePolicy(route *v1alpha3.HTTPRoute, policy *v1alpha2.RoutePolicy) error {
+	if policy == nil {
+		return nil
+	}
+
+	var errs error
+	var err error
+	if route.Timeout, err = durationProto(policy.Timeout); err != nil {
+		errs = multierror.Append(errs, multierror.Prefix(err, "timeout:"))
+	}
+	if retries := policy.Retries; retries != nil {
+		route.Retries = &v1alpha3.HTTPRetry{
+			Attempts: retries.Attempts,
+		}
+		if route.Retries.PerTryTimeout, err = durationProto(retries.PerTryTimeout); err != nil {
+			errs = multierror.Append(errs, multierror.Prefix(err, "retries.perTryTimeout:"))
+		}
+	}
+	if fault := policy.Fault; fault != nil {
+		route.Fault = &v1alpha3.HTTPFaultInjection{}
+		if delay := fault.Delay; delay != nil {
+			fixedDelay, err := durationProto(delay.FixedDelay)
+			if err != nil {
+				errs = multierror.Append(errs, multierror.Prefix(err, "fault.delay.fixedDelay:"))
+			}
+			route.Fault.Delay = &v1alpha3.HTTPFaultInjection_Delay{
+				Percent:       delay.Percent,
+				HttpDelayType: &v1alpha3.HTTPFaultInjection_Delay_FixedDelay{FixedDelay: fixedDelay},
+			}
+		}
+		if abort := fault.Abort; abort != nil {
+			route.Fault.Abort = &v1alpha3.HTTPFaultInjection_Abort{
+				Percent:   abort.Percent,
+				ErrorType: &v1alpha3.HTTPFaultInjection_Abort_HttpStatus{HttpStatus: abort.HttpStatus},
+			}
+		}
+	}
+	return errs
+}
+
+// RoutePolicyOf returns the timeout, retries and faults of the route, for
+// publishing them to the clusters the service is exposed to. Returns nil if
+// the route has none.
+func RoutePolicyOf(route *v1alpha3.HTTPRoute) *v1alpha2.RoutePolicy {
+	if route.Timeout == nil && route.Retries == nil && route.Fault == nil {
+		return nil
+	}
+	policy := &v1alpha2.RoutePolicy{
+		Timeout: durationString(route.Timeout),
+	}
+	if retries := route.Retries; retries != nil {
+		policy.Retries = &v1alpha2.RoutePolicy_Retries{
+			Attempts:      retries.Attempts,
+			PerTryTimeout: durationString(retries.PerTryTimeout),
+		}
+	}
+	if fault := route.Fault; fault != nil {
+		policy.Fault = &v1alpha2.RoutePolicy_Fault{}
+		if delay := fault.Delay; delay != nil {
+			policy.Fault.Delay = &v1alpha2.RoutePolicy_Fault_Delay{
+				Percent:    delay.Percent,
+				FixedDelay: durationString(delay.GetFixedDelay()),
+			}
+		}
+		if abort := fault.Abort; abort != nil {
+			policy.Fault.Abort = &v1alpha2.RoutePolicy_Fault_Abort{
+				Percent:    abort.Percent,
+				HttpStatus: abort.GetHttpStatus(),
+			}
+		}
+	}
+	return policy
+}
+
+// durationString formats a duration like "1.5s", "" if nil or invalid
+func durationString(duration *types.Duration) string {
+	d, err := types.DurationFromProto(duration)
+	if duration == nil || err != nil {
+		return ""
+	}
+	return d.String()
+}
+
+// durationProto parses a duration like "1.5s", nil if empty
+func durationProto(duration string) (*types.Duration, error) {
+	if duration == "" {
+		return nil, nil
+	}
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return nil, err
+	}
+	return types.DurationProto(d), nil
+}
+
+// rsFallbackHostname is the host of the remote clusters of a service bound in
+// FALLBACK mode. Only the VirtualService of the local host routes to it.
+func rsFallbackHostname(rs *v1alpha2.RemoteServiceBinding_RemoteCluster_RemoteService) string {
+	return fmt.Sprintf("%s.%s.fallback", remoteServiceName(rs), remoteServiceNamespace(rs))
+}
+
+// localService returns the K8s Service of the namespace with the name, unless
+// there is none or it was generated for multicluster configs
+func localService(svcs []kube_v1.Service, namespace, name string) *kube_v1.Service {
+	for i, svc := range svcs {
+		if svc.Name == name && svc.Namespace == namespace && GetProvenance(svc.Annotations).Len() == 0 {
+			return &svcs[i]
+		}
+	}
+	return nil
+}
+
+// FallsBackFrom returns true if the binding is in FALLBACK mode for the local
+// K8s Service, i.e. if its realization depends on the Service readiness
+func FallsBackFrom(rsb *v1alpha2.RemoteServiceBinding, namespace, name string) bool {
+	if rsb.Mode != v1alpha2.RemoteServiceBinding_FALLBACK {
+		return false
+	}
+	for _, remote := range rsb.Remote {
+		for _, svc := range remote.Services {
+			if remoteServiceNamespace(svc) == namespace && remoteServiceName(svc) == name {
+				return true
+			}
+		}
+	}
+	return false
+}
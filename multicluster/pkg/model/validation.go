@@ -8,7 +8,9 @@ import (
 
 	istiomodel "istio.io/istio/pilot/pkg/model"
 
-	multicluster "github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	multicluster "github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha2"
 )
 
 // ValidateServiceExpositionPolicy checks service exposition policy specifications
@@ -32,15 +34,66 @@ func ValidateServiceExpositionPolicy(name, namespace string, msg proto.Message)
 
 func validateExposedService(vs *multicluster.ServiceExpositionPolicy_ExposedService) error {
 	var errs error
-	if !istiomodel.IsDNS1123Label(vs.Name) {
+	if len(vs.Selector) > 0 {
+		if vs.Name != "" {
+			errs = multierror.Append(errs, fmt.Errorf("name and selector cannot both be set"))
+		}
+		if vs.Subset != "" {
+			errs = multierror.Append(errs, fmt.Errorf("subset cannot be set with a selector"))
+		}
+		errs = appendErrors(errs, validateSelector(vs.Selector))
+	} else if !istiomodel.IsDNS1123Label(vs.Name) {
 		errs = multierror.Append(errs, fmt.Errorf("invalid name: %q", vs.Name))
 	}
 	if vs.Alias != "" && !istiomodel.IsDNS1123Label(vs.Alias) {
 		errs = multierror.Append(errs, fmt.Errorf("invalid alias: %q", vs.Alias))
 	}
+	errs = appendErrors(errs, validateServicePorts(vs.Ports))
+
+	// Leaving "Clusters" empty means any trusted cluster can talk to the service
+	for _, cluster := range vs.Clusters {
+		errs = appendErrors(errs, ValidateClusterPattern(cluster))
+	}
+	for _, cluster := range vs.ExcludedClusters {
+		errs = appendErrors(errs, ValidateClusterPattern(cluster))
+	}
+	return errs
+}
+
+// ValidateClusterServiceExpositionPolicy checks cluster service exposition policy specifications
+func ValidateClusterServiceExpositionPolicy(name, namespace string, msg proto.Message) (errs error) {
+	value, ok := msg.(*multicluster.ClusterServiceExpositionPolicy)
+	if !ok {
+		errs = appendErrors(errs, fmt.Errorf("cannot cast to ClusterServiceExpositionPolicy: %#v", msg))
+		return
+	}
+
+	if err := validateSelector(value.NamespaceSelector); err != nil {
+		errs = appendErrors(errs, fmt.Errorf("invalid namespace selector: %v", err))
+	}
+	if len(value.Exposed) == 0 {
+		errs = appendErrors(errs, fmt.Errorf("policy must have at least one exposition"))
+	} else {
+		for _, exposed := range value.Exposed {
+			errs = appendErrors(errs, validateExposedService(exposed))
+		}
+	}
+
+	return errs
+}
 
-	// TODO should we validate that at least one Cluster is present?  Or does leaving
-	// "Clusters" empty mean any cluster can talk to the service (public API)?
+// validateSelector checks the labels selecting K8s Services are valid K8s
+// label keys and values
+func validateSelector(selector map[string]string) error {
+	var errs error
+	for key, value := range selector {
+		for _, msg := range validation.IsQualifiedName(key) {
+			errs = multierror.Append(errs, fmt.Errorf("invalid selector key %q: %s", key, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(value) {
+			errs = multierror.Append(errs, fmt.Errorf("invalid selector value %q: %s", value, msg))
+		}
+	}
 	return errs
 }
 
@@ -60,11 +113,42 @@ func ValidateRemoteServiceBinding(name, namespace string, msg proto.Message) (er
 		if remoteCluster.GetCluster() == "" {
 			errs = appendErrors(errs, fmt.Errorf("cluster cannot be empty"))
 		}
+		for _, svc := range remoteCluster.Services {
+			errs = appendErrors(errs, validateServicePorts(svc.Ports))
+		}
 	}
 
 	return errs
 }
 
+// validateServicePorts checks the ports of an exposed or bound service have
+// distinct numbers and names, and a supported protocol
+func validateServicePorts(ports []*multicluster.ServicePort) error {
+	var errs error
+	numbers := make(map[uint32]bool)
+	names := make(map[string]bool)
+	for _, port := range ports {
+		if err := istiomodel.ValidatePort(int(port.Number)); err != nil {
+			errs = multierror.Append(errs, err)
+		} else if numbers[port.Number] {
+			errs = multierror.Append(errs, fmt.Errorf("duplicate port: %d", port.Number))
+		}
+		numbers[port.Number] = true
+
+		if port.Protocol != "" && !PortProtocols[port.Protocol] {
+			errs = multierror.Append(errs, fmt.Errorf("unsupported protocol %q of port %d", port.Protocol, port.Number))
+		}
+		name := PortName(port)
+		if !istiomodel.IsDNS1123Label(name) {
+			errs = multierror.Append(errs, fmt.Errorf("invalid name %q of port %d", name, port.Number))
+		} else if names[name] {
+			errs = multierror.Append(errs, fmt.Errorf("duplicate port name: %q", name))
+		}
+		names[name] = true
+	}
+	return errs
+}
+
 // wrapper around multierror.Append that enforces the invariant that if all input errors are nil, the output
 // error is nil (allowing validation without branching).
 func appendErrors(err error, errs ...error) error {
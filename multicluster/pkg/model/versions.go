@@ -0,0 +1,145 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	golangproto "github.com/golang/protobuf/proto"
+
+	istiomodel "istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/log"
+
+	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha1"
+	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha2"
+)
+
+// V1alpha2SpecAnnotationKey is the annotation of a v1alpha1 config holding
+// the JSON of the v1alpha2 spec it was converted from, when v1alpha1 can't
+// express all of it
+const V1alpha2SpecAnnotationKey = "multicluster.istio.io/v1alpha2-spec"
+
+// ConfigFromV1alpha1 converts a Multi-cluster config with a v1alpha1 spec to
+// v1alpha2. The v1alpha2 spec kept by ConfigToV1alpha1 is restored if the
+// v1alpha1 spec is still the one it was converted to; otherwise the v1alpha1
+// spec was changed since and wins. Configs with v1alpha2 specs are returned
+// as is.
+func ConfigFromV1alpha1(config istiomodel.Config) (istiomodel.Config, error) {
+	var spec golangproto.Message
+	switch in := config.Spec.(type) {
+	case *v1alpha1.ServiceExpositionPolicy:
+		spec = v1alpha2.ServiceExpositionPolicyFromV1alpha1(in)
+	case *v1alpha1.RemoteServiceBinding:
+		spec = v1alpha2.RemoteServiceBindingFromV1alpha1(in)
+	default:
+		return config, nil
+	}
+
+	if stashed, ok := config.Annotations[V1alpha2SpecAnnotationKey]; ok {
+		schema, _ := MultiClusterConfigTypes.GetByType(config.Type)
+		kept, err := schema.FromJSON(stashed)
+		if err != nil {
+			return config, fmt.Errorf("cannot parse the %s annotation of %s %s/%s: %v",
+				V1alpha2SpecAnnotationKey, config.Type, config.Namespace, config.Name, err)
+		}
+		if restored, err := specToV1alpha1(kept); err == nil && proto.Equal(restored, config.Spec) {
+			spec = kept
+		} else {
+			log.Warnf("%s %s/%s was changed at v1alpha1, the v1alpha2 fields of its %s annotation are dropped",
+				config.Type, config.Namespace, config.Name, V1alpha2SpecAnnotationKey)
+		}
+	}
+
+	out := config
+	out.Version = MultiClusterConfigTypes[0].Version
+	out.Annotations = withoutAnnotation(config.Annotations, V1alpha2SpecAnnotationKey)
+	out.Spec = spec
+	return out, nil
+}
+
+// ConfigToV1alpha1 converts a Multi-cluster config with a v1alpha2 spec to
+// v1alpha1. If v1alpha1 can't express all of the spec, the v1alpha2 spec is
+// kept as JSON in the V1alpha2SpecAnnotationKey annotation, so that
+// ConfigFromV1alpha1 restores it. Configs with v1alpha1 specs are returned as
+// is.
+func ConfigToV1alpha1(config istiomodel.Config) (istiomodel.Config, error) {
+	var spec golangproto.Message
+	lossless := true
+	switch in := config.Spec.(type) {
+	case *v1alpha2.ServiceExpositionPolicy:
+		spec, lossless = v1alpha2.ServiceExpositionPolicyToV1alpha1(in)
+	case *v1alpha2.RemoteServiceBinding:
+		spec, lossless = v1alpha2.RemoteServiceBindingToV1alpha1(in)
+	default:
+		return config, nil
+	}
+
+	out := config
+	out.Version = V1alpha1ConfigTypes[0].Version
+	out.Annotations = withoutAnnotation(config.Annotations, V1alpha2SpecAnnotationKey)
+	if !lossless {
+		stashed, err := istiomodel.ToJSON(config.Spec)
+		if err != nil {
+			return config, err
+		}
+		if out.Annotations == nil {
+			out.Annotations = make(map[string]string)
+		}
+		out.Annotations[V1alpha2SpecAnnotationKey] = stashed
+	}
+	out.Spec = spec
+	return out, nil
+}
+
+// specToV1alpha1 converts a v1alpha2 spec to v1alpha1
+func specToV1alpha1(spec golangproto.Message) (golangproto.Message, error) {
+	switch in := spec.(type) {
+	case *v1alpha2.ServiceExpositionPolicy:
+		out, _ := v1alpha2.ServiceExpositionPolicyToV1alpha1(in)
+		return out, nil
+	case *v1alpha2.RemoteServiceBinding:
+		out, _ := v1alpha2.RemoteServiceBindingToV1alpha1(in)
+		return out, nil
+	}
+	return nil, fmt.Errorf("unexpected spec %T", spec)
+}
+
+// validateV1alpha1 validates v1alpha1 specs as converted to v1alpha2
+func validateV1alpha1(validate func(name, namespace string, msg golangproto.Message) error) func(name, namespace string,
+	msg golangproto.Message) error {
+	return func(name, namespace string, msg golangproto.Message) error {
+		config, err := ConfigFromV1alpha1(istiomodel.Config{Spec: msg})
+		if err != nil {
+			return err
+		}
+		return validate(name, namespace, config.Spec)
+	}
+}
+
+// withoutAnnotation returns a copy of the annotations without the key, the
+// annotations themselves if they don't have it
+func withoutAnnotation(annotations map[string]string, key string) map[string]string {
+	if _, ok := annotations[key]; !ok {
+		return annotations
+	}
+	out := make(map[string]string, len(annotations)-1)
+	for k, v := range annotations {
+		if k != key {
+			out[k] = v
+		}
+	}
+	return out
+}
@@ -18,10 +18,7 @@ import (
 	"fmt"
 	"reflect"
 
-	multierror "github.com/hashicorp/go-multierror"
-
 	istiomodel "istio.io/istio/pilot/pkg/model"
-	"istio.io/istio/pkg/log"
 
 	"github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/model"
 
@@ -43,10 +40,32 @@ type ConfigChanges struct {
 	Kubernetes    *KubernetesChanges
 }
 
+// ServiceLister lists existing K8s Services, typically from an informer cache
+type ServiceLister interface {
+	// List returns the Services of a namespace, or of all namespaces for kube_v1.NamespaceAll
+	List(namespace string) ([]kube_v1.Service, error)
+}
+
+// ServiceList is a ServiceLister for a fixed list of Services
+type ServiceList []kube_v1.Service
+
+// List returns the Services of the list that are in the namespace
+func (l ServiceList) List(namespace string) ([]kube_v1.Service, error) {
+	out := make([]kube_v1.Service, 0)
+	for _, svc := range l {
+		if namespace == kube_v1.NamespaceAll || getK8sNamespace(svc) == namespace {
+			out = append(out, svc)
+		}
+	}
+	return out, nil
+}
+
 type reconciler struct {
 	store       istiomodel.ConfigStore
-	services    []kube_v1.Service
+	services    ServiceLister
 	clusterInfo model.ClusterInfo
+	style       model.ConversionStyle
+	options     model.ConversionOptions
 }
 
 // Reconciler merges new multicluster desired state config with existing Istio and K8s configuration producing the desired state
@@ -56,25 +75,76 @@ type Reconciler interface {
 	DeleteMulticlusterConfig(config istiomodel.Config) (*ConfigChanges, error)
 }
 
-// NewReconciler creates a Reconciler to merge existing configuration with Multicluster configuration
-func NewReconciler(store istiomodel.ConfigStore, services []kube_v1.Service, clusterInfo model.ClusterInfo) Reconciler {
+// NewReconciler creates a Reconciler to merge existing configuration with Multicluster configuration.
+// The configuration is generated in the conversion style, for the local Istio install the options describe.
+func NewReconciler(store istiomodel.ConfigStore, services ServiceLister, clusterInfo model.ClusterInfo,
+	style model.ConversionStyle, options model.ConversionOptions) Reconciler {
 	return &reconciler{
 		store:       store,
 		services:    services,
 		clusterInfo: clusterInfo,
+		style:       style,
+		options:     options,
 	}
 }
 
 // AddMulticlusterConfig takes an Istio config store and a new RemoteServiceBinding or ServiceExpositionPolicy
 // and returns the new and modified Istio configurations needed to implement the desired multicluster config.
+// Configurations still realized for an earlier config of the same name, e.g. one deleted while its deletion
+// could not be handled, are pruned as by ModifyMulticlusterConfig.
 func (r *reconciler) AddMulticlusterConfig(newconfig istiomodel.Config) (*ConfigChanges, error) {
+	return r.ModifyMulticlusterConfig(newconfig)
+}
 
-	istioConfigs, svcs, err := model.ConvertBindingsAndExposures2(
-		[]istiomodel.Config{newconfig}, r.clusterInfo, r.store, r.services)
+// ModifyMulticlusterConfig takes an Istio config store and a modified RemoteServiceBinding or ServiceExpositionPolicy
+// and returns the new, modified and deleted Istio configurations needed to implement the desired multicluster config.
+// Configurations realized for the previous version of the config, known by their provenance annotation, are deleted
+// when the new version no longer needs them.
+func (r *reconciler) ModifyMulticlusterConfig(config istiomodel.Config) (*ConfigChanges, error) {
+
+	istioConfigs, svcs, existingSvcs, err := r.desiredState(config)
 	if err != nil {
 		return nil, err
 	}
 
+	stale, err := r.findStale(config, istioConfigs, svcs, existingSvcs)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := r.changesTo(config, istioConfigs, svcs, existingSvcs)
+	if err != nil {
+		return nil, err
+	}
+	changes.Modifications = append(changes.Modifications, stale.modifications...)
+	changes.Deletions = stale.deletions
+	changes.Kubernetes.Modifications = append(changes.Kubernetes.Modifications, stale.svcModifications...)
+	changes.Kubernetes.Deletions = stale.svcDeletions
+	return changes, nil
+}
+
+// desiredState converts the multicluster config into the Istio configs and K8s Services implementing it.
+// The existing K8s Services of the config's namespace are returned as well.
+func (r *reconciler) desiredState(config istiomodel.Config) ([]istiomodel.Config, []kube_v1.Service, []kube_v1.Service, error) {
+	existingSvcs, err := r.services.List(getNamespace(config))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	istioConfigs, svcs, err := model.ConvertBindingsAndExposures2(r.style,
+		[]istiomodel.Config{config}, r.clusterInfo, r.store, existingSvcs, r.options)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return istioConfigs, svcs, existingSvcs, nil
+}
+
+// changesTo returns the additions and modifications bringing the existing configuration to the desired state
+// of the multicluster config. Existing generated resources keep the record of their other sources. Fails if
+// an existing K8s Service has another ClusterIP than the VIP the desired one takes, as it cannot be changed.
+func (r *reconciler) changesTo(config istiomodel.Config, istioConfigs []istiomodel.Config,
+	svcs []kube_v1.Service, existingSvcs []kube_v1.Service) (*ConfigChanges, error) {
+
+	source := model.ProvenanceAnnotation(config)
 	outAdditions := make([]istiomodel.Config, 0)
 	outModifications := make([]istiomodel.Config, 0)
 	for _, istioConfig := range istioConfigs {
@@ -82,13 +152,15 @@ func (r *reconciler) AddMulticlusterConfig(newconfig istiomodel.Config) (*Config
 		if !ok {
 			outAdditions = append(outAdditions, istioConfig)
 		} else {
-			if !reflect.DeepEqual(istioConfig.Spec, orig.Spec) {
+			istioConfig.Annotations = mergeProvenance(istioConfig.Annotations, orig.Annotations, source)
+			if !reflect.DeepEqual(istioConfig.Spec, orig.Spec) ||
+				istioConfig.Annotations[model.ProvenanceAnnotationKey] != orig.Annotations[model.ProvenanceAnnotationKey] {
 				outModifications = append(outModifications, istioConfig)
 			}
 		}
 	}
 
-	origSvcs := indexServices(r.services, svcIndex)
+	origSvcs := indexServices(existingSvcs, svcIndex)
 	svcAdditions := make([]kube_v1.Service, 0)
 	svcModifications := make([]kube_v1.Service, 0)
 	for _, svc := range svcs {
@@ -96,113 +168,89 @@ func (r *reconciler) AddMulticlusterConfig(newconfig istiomodel.Config) (*Config
 		if !ok {
 			svcAdditions = append(svcAdditions, svc)
 		} else {
+			svc.Annotations = mergeProvenance(svc.Annotations, orig.Annotations, source)
+			if svc.Spec.ClusterIP != "" && svc.Spec.ClusterIP != orig.Spec.ClusterIP {
+				return nil, fmt.Errorf("K8s Service %s.%s has ClusterIP %s rather than the VIP %s of its host "+
+					"and must be deleted to be recreated with it", orig.Name, orig.Namespace, orig.Spec.ClusterIP,
+					svc.Spec.ClusterIP)
+			}
 			// Compare, but don't include generated immutable field ClusterIP in comparison
 			origNoIP := orig.Spec
-			origNoIP.ClusterIP = ""
-			if !reflect.DeepEqual(svc.Spec, origNoIP) {
+			origNoIP.ClusterIP = svc.Spec.ClusterIP
+			if !reflect.DeepEqual(svc.Spec, origNoIP) ||
+				svc.Annotations[model.ProvenanceAnnotationKey] != orig.Annotations[model.ProvenanceAnnotationKey] {
 				// New version is different in some way besides ClusterIP.  Make a new one,
 				// but use the UID and ClusterIP of the old one so that we survive K8s
 				// immutability requirement on ClusterIP.
-				newSpec := svc.Spec
-				origSpec := orig.Spec
-				newSpec.ClusterIP = origSpec.ClusterIP
+				svc.Spec.ClusterIP = orig.Spec.ClusterIP
 				svc.UID = orig.UID
+				svc.ResourceVersion = orig.ResourceVersion
 				svcModifications = append(svcModifications, svc)
 			}
-			// TODO merge Annotations if multiple remote clusters offer service
 		}
 	}
 
 	return &ConfigChanges{
 		Additions:     outAdditions,
 		Modifications: outModifications,
+		Deletions:     make([]istiomodel.Config, 0),
 		Kubernetes: &KubernetesChanges{
 			Additions:     svcAdditions,
 			Modifications: svcModifications,
+			Deletions:     make([]kube_v1.Service, 0),
 		},
 	}, nil
 }
 
-// ModifyMulticlusterConfig takes an Istio config store and a modified RemoteServiceBinding or ServiceExpositionPolicy
-// and returns the new and modified Istio configurations needed to implement the desired multicluster config.
-func (r *reconciler) ModifyMulticlusterConfig(config istiomodel.Config) (*ConfigChanges, error) {
-
-	// Modifying RSB or SEP usually only modifies Istio config, but it might generate new if Istio config was deleted.
-	// So don't bother validating that Istio and K8s Services exist, just do what "Add" would do.
-	return r.AddMulticlusterConfig(config)
+// mergeProvenance returns the desired annotations holding the existing provenance record, with
+// what is recorded for the source replaced by the desired record. Existing resources without a
+// record were not generated for multicluster configs and keep the desired annotations unchanged.
+func mergeProvenance(desired, existing map[string]string, source string) map[string]string {
+	record := model.GetProvenance(existing)
+	if record.Len() == 0 {
+		return desired
+	}
+	record.Remove(source)
+	record.Add(source, model.GetProvenance(desired).Endpoints(source)...)
+	return record.Annotate(desired)
 }
 
 // DeleteMulticlusterConfig takes an Istio config store and a deleted RemoteServiceBinding or ServiceExpositionPolicy
 // and returns the Istio configurations that should be removed to disable the multicluster config.
-// Only the Type, Name, and Namespace of the output configs is guaranteed usable.
+// What was realized for the config is found by its provenance record, whatever conversion style realized it.
+// Resources still realized for other configs only lose this source.
+// A config that realized nothing, such as a binding awaiting approval, has no changes.
+// Only the Type, Name, and Namespace of the output configs is guaranteed usable, and the Spec of ServiceEntries.
 func (r *reconciler) DeleteMulticlusterConfig(config istiomodel.Config) (*ConfigChanges, error) {
 
-	var err error
-	istioConfigs, svcs, err := model.ConvertBindingsAndExposures2(
-		[]istiomodel.Config{config}, r.clusterInfo, r.store, r.services)
+	existingSvcs, err := r.services.List(getNamespace(config))
 	if err != nil {
 		return nil, err
 	}
-
-	outDeletions := make([]istiomodel.Config, 0)
-	for _, istioConfig := range istioConfigs {
-		orig, ok := r.store.Get(istioConfig.Type, istioConfig.Name, getNamespace(istioConfig))
-		if !ok {
-			err = multierror.Append(err, fmt.Errorf("%s %s.%s should have been realized by %s %s.%s; skipping",
-				config.Type, config.Name, config.Namespace,
-				istioConfig.Type, istioConfig.Name, getNamespace(istioConfig)))
-		} else {
-			// Only delete if our annotation is present
-			_, ok := orig.Annotations[model.ProvenanceAnnotationKey]
-			if ok {
-				istioConfig.Spec = nil // Don't let caller see the details, their job is to delete based on Kind and Name
-				outDeletions = append(outDeletions, istioConfig)
-			} else {
-				log.Infof("Ignoring unprovenanced %s %s.%s when reconciling deletion",
-					istioConfig.Type, istioConfig.Name, getNamespace(istioConfig))
-			}
-		}
-	}
-
-	origSvcs := indexServices(r.services, svcIndex)
-	svcModifications := make([]kube_v1.Service, 0)
-	svcDeletions := make([]kube_v1.Service, 0)
-	for _, svc := range svcs {
-		orig, ok := origSvcs[svcIndex(svc)]
-		if ok {
-			// There is a service.  If there is an annotation for us, and it is the only one,
-			// delete the service.  If there is annotations for multiple remotes, modify to remove this remote.
-			// Only delete if our annotation is present
-			origAnn, ok := orig.Annotations[model.ProvenanceAnnotationKey]
-			if ok {
-				if lastRemote(origAnn, config) {
-					svcDeletions = append(svcDeletions, svc)
-				} else {
-					newSpec := svc.Spec
-					origSpec := orig.Spec
-					newSpec.ClusterIP = origSpec.ClusterIP
-					svc.UID = orig.UID
-					svcModifications = append(svcModifications, svc)
-				}
-			} else {
-				log.Infof("Ignoring unprovenanced K8s Service %s.%s when reconciling deletion",
-					svc.Name, getK8sNamespace(svc))
-			}
-		}
+	stale, err := r.findStale(config, []istiomodel.Config{}, []kube_v1.Service{}, existingSvcs)
+	if err != nil {
+		return nil, err
 	}
 
-	return &ConfigChanges{
-		Deletions: outDeletions,
+	changes := &ConfigChanges{
+		Additions:     make([]istiomodel.Config, 0),
+		Modifications: stale.modifications,
+		Deletions:     stale.deletions,
 		Kubernetes: &KubernetesChanges{
-			Modifications: svcModifications,
-			Deletions:     svcDeletions,
+			Additions:     make([]kube_v1.Service, 0),
+			Modifications: stale.svcModifications,
+			Deletions:     stale.svcDeletions,
 		},
-	}, err
+	}
+	return changes, nil
 }
 
-// lastRemote() returns true for annotations that only refer to this cluster.
-func lastRemote(annotation string, config istiomodel.Config) bool {
-	return annotation == model.ProvenanceAnnotation(config)
+// withoutServiceSource returns a copy of the K8s Service no longer realized for the source
+func withoutServiceSource(svc kube_v1.Service, source string) kube_v1.Service {
+	record := model.GetProvenance(svc.Annotations)
+	record.Remove(source)
+	svc.Annotations = record.Annotate(svc.Annotations)
+	return svc
 }
 
 func indexServices(svcs []kube_v1.Service, indexFunc func(config kube_v1.Service) string) map[string]kube_v1.Service {
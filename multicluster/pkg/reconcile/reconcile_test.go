@@ -20,6 +20,7 @@ import (
 	"io/ioutil"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	multierror "github.com/hashicorp/go-multierror"
@@ -44,6 +45,20 @@ type debugClusterInfo struct {
 	ports   map[string]uint32
 }
 
+// exposureConversionOptions returns the default options with the trust
+// domains of the peers of cluster_a.yaml, the exposures are converted with
+func exposureConversionOptions() mcmodel.ConversionOptions {
+	opts := mcmodel.DefaultConversionOptions()
+	opts.TrustDomains = map[string]string{
+		"acceptor-cluster-1": "acceptor-cluster-1.example.com",
+		"acceptor-cluster-2": "acceptor-cluster-2.example.com",
+		"cluster-1":          "cluster-1.example.com",
+		"cluster-b":          "cluster-b.example.com",
+		"cluster1":           "cluster1.example.com",
+	}
+	return opts
+}
+
 func TestReconcileBinding(t *testing.T) {
 	ci := debugClusterInfo{
 		ips: map[string]string{
@@ -90,21 +105,62 @@ func TestReconcileBinding(t *testing.T) {
 			style: mcmodel.DirectIngressStyle},
 		// Case 3: Deleting
 		{deleted: loadConfig("reviews-binding.yaml", t),
+			istioConfig: loadIstioConfigList("reviews-directingress-binding-nonamespace.yaml.golden", t),
+			initialServices: loadK8sServiceListFrom("reviews-directingress-binding-clusterip-starter.yaml",
+				"../test/expose-binding/", t),
+			style:     mcmodel.DirectIngressStyle,
+			deletions: loadIstioConfigList("reviews-directingress-binding-nonamespace.yaml.golden", t),
+			svcDeletions: loadK8sServiceListFrom("reviews-directingress-binding-clusterip-starter.yaml",
+				"../test/expose-binding/", t),
+		},
+		// Case 4: Deleting a binding sharing its ServiceEntry and K8s Service with another only removes its endpoint
+		{deleted: loadConfig("reviews-binding.yaml", t),
+			istioConfig: loadIstioConfigListFrom("reviews-directingress-binding-shared-starter.yaml",
+				"../test/expose-binding/", t),
+			initialServices: loadK8sServiceListFrom("reviews-directingress-binding-shared-starter.yaml",
+				"../test/expose-binding/", t),
+			style:            mcmodel.DirectIngressStyle,
+			modifications:    loadIstioConfigList("reviews-directingress-binding-shared-deleted.yaml.golden", t),
+			svcModifications: loadK8sServiceList("reviews-directingress-binding-shared-deleted.yaml.golden", t),
+		},
+		// Case 5: Modifying to bind another service removes what was realized for the dropped one
+		{modified: loadConfig("reviews-binding-ratings-only.yaml", t),
+			istioConfig: loadIstioConfigList("reviews-directingress-binding-nonamespace.yaml.golden", t),
+			initialServices: loadK8sServiceListFrom("reviews-directingress-binding-clusterip-starter.yaml",
+				"../test/expose-binding/", t),
+			style:        mcmodel.DirectIngressStyle,
+			additions:    loadIstioConfigList("reviews-directingress-binding-ratings-only.yaml.golden", t),
+			svcAdditions: loadK8sServiceList("reviews-directingress-binding-ratings-only.yaml.golden", t),
+			deletions:    loadIstioConfigList("reviews-directingress-binding-nonamespace.yaml.golden", t),
+			svcDeletions: loadK8sServiceListFrom("reviews-directingress-binding-clusterip-starter.yaml",
+				"../test/expose-binding/", t),
+		},
+		// Case 6: Egress Ingress style, deleting a binding sharing the remote gateways only removes its endpoint
+		{deleted: loadConfig("reviews-binding.yaml", t),
+			istioConfig: loadIstioConfigListFrom("egressingress-reviews-binding-shared-starter.yaml",
+				"../test/expose-binding/", t),
+			style:         mcmodel.EgressIngressStyle,
+			modifications: loadIstioConfigList("egressingress-reviews-binding-shared-deleted.yaml.golden", t),
+		},
+		// Case 7: Adding a binding whose earlier version's deletion was not handled removes what it no longer binds
+		{added: loadConfig("reviews-binding-ratings-only.yaml", t),
 			istioConfig: loadIstioConfigList("reviews-directingress-binding-nonamespace.yaml.golden", t),
 			initialServices: loadK8sServiceListFrom("reviews-directingress-binding-clusterip-starter.yaml",
 				"../test/expose-binding/", t),
 			style:        mcmodel.DirectIngressStyle,
+			additions:    loadIstioConfigList("reviews-directingress-binding-ratings-only.yaml.golden", t),
+			svcAdditions: loadK8sServiceList("reviews-directingress-binding-ratings-only.yaml.golden", t),
 			deletions:    loadIstioConfigList("reviews-directingress-binding-nonamespace.yaml.golden", t),
-			svcDeletions: loadK8sServiceList("reviews-directingress-binding-nonamespace.yaml.golden", t),
+			svcDeletions: loadK8sServiceListFrom("reviews-directingress-binding-clusterip-starter.yaml",
+				"../test/expose-binding/", t),
 		},
 	}
 
 	for i, tc := range tt {
 		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
-			if tc.style != "" {
-				os.Setenv(mcmodel.IstioConversionStyleKey, tc.style) // nolint: errcheck
-			} else {
-				os.Setenv(mcmodel.IstioConversionStyleKey, mcmodel.EgressIngressStyle) // nolint: errcheck
+			style, err := mcmodel.GetConversionStyle(tc.style)
+			if err != nil {
+				t.Fatal(err)
 			}
 
 			cs, err := createDebugConfigStore(tc.istioConfig)
@@ -112,7 +168,7 @@ func TestReconcileBinding(t *testing.T) {
 				t.Error(err)
 			}
 
-			r := NewReconciler(cs, tc.initialServices, ci)
+			r := NewReconciler(cs, ServiceList(tc.initialServices), ci, style, mcmodel.DefaultConversionOptions())
 			var errAdditions error
 			var errModifications error
 			var errDeletions error
@@ -136,6 +192,14 @@ func TestReconcileBinding(t *testing.T) {
 					if err != nil {
 						t.Error(multierror.Prefix(err, "Generated K8s modifications unexpected:"))
 					}
+					err = checkEqualConfigMetas(addChanges.Deletions, tc.deletions)
+					if err != nil {
+						t.Error(multierror.Prefix(err, "Proposed deletions unexpected:"))
+					}
+					err = checkEqualServices(addChanges.Kubernetes.Deletions, tc.svcDeletions)
+					if err != nil {
+						t.Error(multierror.Prefix(err, "Generated K8s deletions unexpected:"))
+					}
 				}
 			}
 
@@ -143,14 +207,30 @@ func TestReconcileBinding(t *testing.T) {
 				var modChanges *ConfigChanges
 				modChanges, errModifications = r.ModifyMulticlusterConfig(*tc.modified)
 				if errModifications == nil {
+					err = checkEqualConfigs(modChanges.Additions, tc.additions)
+					if err != nil {
+						t.Error(multierror.Prefix(err, "Generated additions unexpected"))
+					}
 					err = checkEqualConfigs(modChanges.Modifications, tc.modifications)
 					if err != nil {
 						t.Error(multierror.Prefix(err, "Generated modifications unexpected"))
 					}
+					err = checkEqualConfigMetas(modChanges.Deletions, tc.deletions)
+					if err != nil {
+						t.Error(multierror.Prefix(err, "Proposed deletions unexpected"))
+					}
+					err = checkEqualServices(modChanges.Kubernetes.Additions, tc.svcAdditions)
+					if err != nil {
+						t.Error(multierror.Prefix(err, "Generated K8s additions unexpected"))
+					}
 					err = checkEqualServices(modChanges.Kubernetes.Modifications, tc.svcModifications)
 					if err != nil {
 						t.Error(multierror.Prefix(err, "Generated modifications unexpected"))
 					}
+					err = checkEqualServices(modChanges.Kubernetes.Deletions, tc.svcDeletions)
+					if err != nil {
+						t.Error(multierror.Prefix(err, "Generated K8s deletions unexpected"))
+					}
 				}
 			}
 
@@ -162,6 +242,14 @@ func TestReconcileBinding(t *testing.T) {
 					if err != nil {
 						t.Error(multierror.Prefix(err, "Proposed deletions unexpected"))
 					}
+					err = checkEqualConfigs(delChanges.Modifications, tc.modifications)
+					if err != nil {
+						t.Error(multierror.Prefix(err, "Generated modifications unexpected:"))
+					}
+					err = checkEqualProvenance(delChanges.Modifications, tc.modifications)
+					if err != nil {
+						t.Error(err)
+					}
 					err = checkEqualServices(delChanges.Kubernetes.Deletions, tc.svcDeletions)
 					if err != nil {
 						t.Error(multierror.Prefix(err, "Generated K8s deletions unexpected:"))
@@ -217,23 +305,27 @@ func TestReconcileExposure(t *testing.T) {
 		svcAdditions     []kube_v1.Service
 		svcModifications []kube_v1.Service
 		// TODO svcDeletions []kube_v1.Service
-		wantException bool
-		style         string
+		wantException  bool
+		style          string
+		noTrustDomains bool
 	}{
 		// Case 0: if we have already configured, adding again won't change things
 		{added: loadConfig("rshriram-demo-exposure.yaml", t),
-			istioConfig: loadIstioConfigList("rshriram-demo-exposure.yaml.golden", t)},
+			istioConfig: loadIstioConfigList("rshriram-demo-exposure.yaml.golden", t),
+			style:       mcmodel.EgressIngressStyle},
 		// Case 1: If we have nothing configured, adding creates things
 		{added: loadConfig("rshriram-demo-exposure.yaml", t),
-			additions: loadIstioConfigList("rshriram-demo-exposure.yaml.golden", t)},
+			additions: loadIstioConfigList("rshriram-demo-exposure.yaml.golden", t),
+			style:     mcmodel.EgressIngressStyle},
 		// Case 2: If we delete, the config should go away
 		{deleted: loadConfig("rshriram-demo-exposure.yaml", t),
 			istioConfig: loadIstioConfigList("rshriram-demo-exposure.yaml.golden", t),
+			style:       mcmodel.EgressIngressStyle,
 			deletions: []istiomodel.Config{
 				istiomodel.Config{
 					ConfigMeta: istiomodel.ConfigMeta{
 						Type:      "destination-rule",
-						Name:      "dest-rule-server-default-notls",
+						Name:      "dest-rule-server-ns2-notls",
 						Namespace: "ns2",
 					},
 				},
@@ -251,11 +343,25 @@ func TestReconcileExposure(t *testing.T) {
 						Namespace: "ns2",
 					},
 				},
+				istiomodel.Config{
+					ConfigMeta: istiomodel.ConfigMeta{
+						Type:      "service-role",
+						Name:      "remote-clusters-to-server-ns2",
+						Namespace: "ns2",
+					},
+				},
+				istiomodel.Config{
+					ConfigMeta: istiomodel.ConfigMeta{
+						Type:      "service-role-binding",
+						Name:      "remote-clusters-to-server-ns2",
+						Namespace: "ns2",
+					},
+				},
 			},
 		},
-		// Case 3: If we delete things never realized, we should (internally) fail reconciliation
+		// Case 3: Deleting things never realized changes nothing
 		{deleted: loadConfig("rshriram-demo-exposure.yaml", t),
-			wantException: true},
+			style: mcmodel.EgressIngressStyle},
 		// Case 4: Direct Ingress style
 		{added: loadConfig("rshriram-demo-exposure.yaml", t),
 			istioConfig: loadIstioConfigList("banix-demo-exposure.yaml.golden", t),
@@ -272,14 +378,26 @@ func TestReconcileExposure(t *testing.T) {
 			additions:     loadIstioConfigList("reviews-sni-exposure-additions.yaml.golden", t),
 			modifications: loadIstioConfigList("reviews-sni-exposure-modifications.yaml.golden", t),
 			style:         mcmodel.DirectIngressStyle},
+		// Case 7: Direct Ingress style, modifying to expose all of the service removes the subset exposure
+		{modified: loadConfig("reviews-exposure-v1-widened.yaml", t),
+			istioConfig: append(loadIstioConfigList("reviews-sni-exposure-v1-only-additions.yaml.golden", t),
+				loadIstioConfigList("reviews-sni-exposure-v1-only-modifications.yaml.golden", t)...),
+			additions:     loadIstioConfigList("reviews-sni-exposure-additions.yaml.golden", t),
+			modifications: loadIstioConfigList("reviews-sni-exposure-modifications.yaml.golden", t),
+			deletions:     loadIstioConfigList("reviews-sni-exposure-v1-only-additions.yaml.golden", t),
+			style:         mcmodel.DirectIngressStyle},
+		// Case 8: Exposing to peers of unknown trust domains fails rather than admitting any caller
+		{added: loadConfig("rshriram-demo-exposure.yaml", t),
+			noTrustDomains: true,
+			wantException:  true,
+			style:          mcmodel.DirectIngressStyle},
 	}
 
 	for i, tc := range tt {
 		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
-			if tc.style != "" {
-				os.Setenv(mcmodel.IstioConversionStyleKey, tc.style) // nolint: errcheck
-			} else {
-				os.Setenv(mcmodel.IstioConversionStyleKey, mcmodel.EgressIngressStyle) // nolint: errcheck
+			style, err := mcmodel.GetConversionStyle(tc.style)
+			if err != nil {
+				t.Fatal(err)
 			}
 
 			cs, err := createDebugConfigStore(tc.istioConfig)
@@ -287,7 +405,11 @@ func TestReconcileExposure(t *testing.T) {
 				t.Error(err)
 			}
 
-			r := NewReconciler(cs, tc.initialServices, ci)
+			opts := exposureConversionOptions()
+			if tc.noTrustDomains {
+				opts.TrustDomains = nil
+			}
+			r := NewReconciler(cs, ServiceList(tc.initialServices), ci, style, opts)
 			var errAdditions error
 			var errModifications error
 			var errDeletions error
@@ -318,10 +440,18 @@ func TestReconcileExposure(t *testing.T) {
 				var modChanges *ConfigChanges
 				modChanges, errModifications = r.ModifyMulticlusterConfig(*tc.modified)
 				if errModifications == nil {
+					err = checkEqualConfigs(modChanges.Additions, tc.additions)
+					if err != nil {
+						t.Error(multierror.Prefix(err, "Generated additions unexpected"))
+					}
 					err = checkEqualConfigs(modChanges.Modifications, tc.modifications)
 					if err != nil {
 						t.Error(multierror.Prefix(err, "Generated modifications unexpected"))
 					}
+					err = checkEqualConfigMetas(modChanges.Deletions, tc.deletions)
+					if err != nil {
+						t.Error(multierror.Prefix(err, "Proposed deletions unexpected"))
+					}
 					err = checkEqualServices(modChanges.Kubernetes.Modifications, tc.svcModifications)
 					if err != nil {
 						t.Error(multierror.Prefix(err, "Generated modifications unexpected"))
@@ -360,6 +490,96 @@ func TestReconcileExposure(t *testing.T) {
 	}
 }
 
+// An exposure and a binding of the same name are told apart by the kind in
+// their provenance. Records written before the kind was recorded still belong
+// to the configs of their namespace and name, and take the kind once rewritten.
+func TestReconcileSameName(t *testing.T) {
+	ci := debugClusterInfo{
+		ips:   map[string]string{"cluster2": "127.0.0.1"},
+		ports: map[string]uint32{"cluster2": 80},
+	}
+	binding := loadConfig("reviews-binding.yaml", t)
+
+	tt := []struct {
+		name             string
+		added            *istiomodel.Config
+		deleted          *istiomodel.Config
+		legacy           bool
+		wantDeletions    int
+		wantSvcDeletions int
+	}{
+		{name: "exposure deleted", deleted: loadConfig("reviews-exposure.yaml", t)},
+		{name: "binding deleted", deleted: binding, wantDeletions: 2, wantSvcDeletions: 1},
+		{name: "legacy binding deleted", deleted: binding, legacy: true, wantDeletions: 2, wantSvcDeletions: 1},
+		{name: "legacy binding added", added: binding, legacy: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			istioConfig := loadIstioConfigList("reviews-directingress-binding-nonamespace.yaml.golden", t)
+			svcs := loadK8sServiceListFrom("reviews-directingress-binding-clusterip-starter.yaml", "../test/expose-binding/", t)
+			if tc.legacy {
+				for i := range istioConfig {
+					istioConfig[i].Annotations = legacyProvenance(istioConfig[i].Annotations)
+				}
+				for i := range svcs {
+					svcs[i].Annotations = legacyProvenance(svcs[i].Annotations)
+				}
+			}
+			cs, err := createDebugConfigStore(istioConfig)
+			if err != nil {
+				t.Fatal(err)
+			}
+			style, err := mcmodel.GetConversionStyle(mcmodel.DirectIngressStyle)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := NewReconciler(cs, ServiceList(svcs), ci, style, exposureConversionOptions())
+
+			var changes *ConfigChanges
+			if tc.deleted != nil {
+				changes, err = r.DeleteMulticlusterConfig(*tc.deleted)
+			} else {
+				changes, err = r.AddMulticlusterConfig(*tc.added)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(changes.Deletions) != tc.wantDeletions || len(changes.Kubernetes.Deletions) != tc.wantSvcDeletions {
+				t.Errorf("Got %d deletions and %d K8s deletions, want %d and %d", len(changes.Deletions),
+					len(changes.Kubernetes.Deletions), tc.wantDeletions, tc.wantSvcDeletions)
+			}
+			if len(changes.Additions) != 0 || len(changes.Kubernetes.Additions) != 0 {
+				t.Errorf("Unexpected additions %v, %v", changes.Additions, changes.Kubernetes.Additions)
+			}
+			if tc.added != nil {
+				// The legacy records are rewritten with the kind
+				if len(changes.Modifications) != len(istioConfig) || len(changes.Kubernetes.Modifications) != len(svcs) {
+					t.Errorf("Got %d modifications and %d K8s modifications, want %d and %d", len(changes.Modifications),
+						len(changes.Kubernetes.Modifications), len(istioConfig), len(svcs))
+				}
+				want := mcmodel.ProvenanceAnnotation(*tc.added)
+				for _, config := range changes.Modifications {
+					if got := config.Annotations[mcmodel.ProvenanceAnnotationKey]; !strings.HasPrefix(got, want) {
+						t.Errorf("Provenance of %s %s is %q, want %q", config.Type, config.Name, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+// legacyProvenance returns the annotations with the provenance sources in the
+// form they had before they named the kind of their config
+func legacyProvenance(annotations map[string]string) map[string]string {
+	out := make(map[string]string, len(annotations))
+	for k, v := range annotations {
+		out[k] = v
+	}
+	out[mcmodel.ProvenanceAnnotationKey] = strings.Replace(out[mcmodel.ProvenanceAnnotationKey], "rsb/", "", -1)
+	return out
+}
+
 func loadConfig(fname string, t *testing.T) *istiomodel.Config {
 	configs := loadConfigList(fname, t)
 
@@ -585,6 +805,23 @@ func (ci debugClusterInfo) Port(name string) uint32 {
 	return 8080 // dummy value for unknown clusters
 }
 
+// checkEqualProvenance compares the provenance records of configs found in both lists
+func checkEqualProvenance(configs []istiomodel.Config, expected []istiomodel.Config) error {
+	lookup := indexConfigs(expected)
+	for _, config := range configs {
+		expectedConfig, ok := lookup[config.Namespace][config.Name][config.Type]
+		if !ok {
+			continue
+		}
+		got := config.Annotations[mcmodel.ProvenanceAnnotationKey]
+		wanted := expectedConfig.Annotations[mcmodel.ProvenanceAnnotationKey]
+		if got != wanted {
+			return fmt.Errorf("provenance of %s %s.%s is %q (expected %q)", config.Type, config.Name, config.Namespace, got, wanted)
+		}
+	}
+	return nil
+}
+
 func checkEqualServices(svcs []kube_v1.Service, expected []kube_v1.Service) error {
 	if len(svcs) != len(expected) {
 		return fmt.Errorf("service definitions don't match: different number of elements %d vs expected %d (%#v vs expected %#v)",
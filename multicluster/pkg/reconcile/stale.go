@@ -0,0 +1,238 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcile
+
+import (
+	"fmt"
+	"reflect"
+
+	"istio.io/api/networking/v1alpha3"
+	istiomodel "istio.io/istio/pilot/pkg/model"
+
+	"github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/model"
+
+	kube_v1 "k8s.io/api/core/v1"
+)
+
+// generatedTypes are the Istio config types realized for multicluster configs
+var generatedTypes = []string{
+	istiomodel.VirtualService.Type,
+	istiomodel.Gateway.Type,
+	istiomodel.DestinationRule.Type,
+	istiomodel.ServiceEntry.Type,
+	istiomodel.ServiceRole.Type,
+	istiomodel.ServiceRoleBinding.Type,
+}
+
+// staleChanges holds the changes removing what an earlier version of a
+// multicluster config realized and its current version no longer needs
+type staleChanges struct {
+	modifications    []istiomodel.Config
+	deletions        []istiomodel.Config
+	svcModifications []kube_v1.Service
+	svcDeletions     []kube_v1.Service
+}
+
+// findStale compares the resources realized for the multicluster config, found
+// by their provenance record, with its desired state. Resources not desired
+// anymore are deleted, or only lose this source if they have other sources.
+// DestinationRule subsets only routed to by the no longer desired
+// VirtualServices are removed; desired DestinationRules are pruned in place.
+func (r *reconciler) findStale(mcConfig istiomodel.Config, desired []istiomodel.Config,
+	desiredSvcs []kube_v1.Service, existingSvcs []kube_v1.Service) (*staleChanges, error) {
+
+	source := model.ProvenanceAnnotation(mcConfig)
+	owned := make([]istiomodel.Config, 0)
+	live := make([]istiomodel.Config, 0)
+	for _, typ := range generatedTypes {
+		configs, err := r.store.List(typ, kube_v1.NamespaceAll)
+		if err != nil {
+			return nil, err
+		}
+		for _, existing := range configs {
+			if model.GetProvenance(existing.Annotations).Has(source) {
+				owned = append(owned, existing)
+			} else if typ == istiomodel.VirtualService.Type {
+				live = append(live, existing)
+			}
+		}
+	}
+
+	desiredKeys := make(map[string]bool)
+	for _, config := range desired {
+		desiredKeys[configIndex(config)] = true
+		if config.Type == istiomodel.VirtualService.Type {
+			live = append(live, config)
+		}
+	}
+
+	// Subsets our earlier VirtualServices routed to, which are kept only while
+	// a desired or foreign VirtualService still routes to them
+	candidates := routedSubsets(owned)
+	inUse := routedSubsets(live)
+	unused := make(map[string]map[string]bool)
+	for host, subsets := range candidates {
+		for subset := range subsets {
+			if !inUse[host][subset] {
+				if unused[host] == nil {
+					unused[host] = make(map[string]bool)
+				}
+				unused[host][subset] = true
+			}
+		}
+	}
+
+	out := &staleChanges{
+		modifications:    make([]istiomodel.Config, 0),
+		deletions:        make([]istiomodel.Config, 0),
+		svcModifications: make([]kube_v1.Service, 0),
+		svcDeletions:     make([]kube_v1.Service, 0),
+	}
+
+	for i, config := range desired {
+		if config.Type == istiomodel.DestinationRule.Type {
+			desired[i] = pruneSubsets(config, unused)
+		}
+	}
+
+	ownedKeys := make(map[string]bool)
+	for _, config := range owned {
+		ownedKeys[configIndex(config)] = true
+		if desiredKeys[configIndex(config)] {
+			continue
+		}
+		if model.GetProvenance(config.Annotations).Len() > 1 {
+			// Still realized for other sources
+			out.modifications = append(out.modifications, pruneSubsets(model.WithoutSource(config, mcConfig), unused))
+			continue
+		}
+		if config.Type == istiomodel.DestinationRule.Type && len(inUse[drHost(config)]) > 0 {
+			// Another VirtualService still routes to this DestinationRule
+			if pruned := pruneSubsets(config, unused); !reflect.DeepEqual(pruned.Spec, config.Spec) {
+				out.modifications = append(out.modifications, pruned)
+			}
+			continue
+		}
+		if config.Type != istiomodel.ServiceEntry.Type {
+			// Only the Type, Name and Namespace are needed for deleting. The
+			// ServiceEntries keep their hosts, whose VIPs are freed.
+			config.Spec = nil
+		}
+		out.deletions = append(out.deletions, config)
+	}
+
+	// Foreign DestinationRules we added subsets to
+	if len(unused) > 0 {
+		drs, err := r.store.List(istiomodel.DestinationRule.Type, kube_v1.NamespaceAll)
+		if err != nil {
+			return nil, err
+		}
+		for _, dr := range drs {
+			if desiredKeys[configIndex(dr)] || ownedKeys[configIndex(dr)] {
+				continue
+			}
+			if pruned := pruneSubsets(dr, unused); !reflect.DeepEqual(pruned.Spec, dr.Spec) {
+				out.modifications = append(out.modifications, pruned)
+			}
+		}
+	}
+
+	desiredSvcKeys := make(map[string]bool)
+	for _, svc := range desiredSvcs {
+		desiredSvcKeys[svcIndex(svc)] = true
+	}
+	for _, svc := range existingSvcs {
+		record := model.GetProvenance(svc.Annotations)
+		if !record.Has(source) || desiredSvcKeys[svcIndex(svc)] {
+			continue
+		}
+		if record.Len() > 1 {
+			out.svcModifications = append(out.svcModifications, withoutServiceSource(svc, source))
+		} else {
+			out.svcDeletions = append(out.svcDeletions, svc)
+		}
+	}
+
+	return out, nil
+}
+
+// routedSubsets returns the subsets, by host, the VirtualServices route to
+func routedSubsets(configs []istiomodel.Config) map[string]map[string]bool {
+	out := make(map[string]map[string]bool)
+	add := func(destination *v1alpha3.Destination) {
+		if destination == nil || destination.Subset == "" {
+			return
+		}
+		if out[destination.Host] == nil {
+			out[destination.Host] = make(map[string]bool)
+		}
+		out[destination.Host][destination.Subset] = true
+	}
+
+	for _, config := range configs {
+		vs, ok := config.Spec.(*v1alpha3.VirtualService)
+		if !ok {
+			continue
+		}
+		for _, route := range vs.Http {
+			for _, dw := range route.Route {
+				add(dw.Destination)
+			}
+			if route.Mirror != nil {
+				add(route.Mirror)
+			}
+		}
+		for _, route := range vs.Tls {
+			for _, dw := range route.Route {
+				add(dw.Destination)
+			}
+		}
+		for _, route := range vs.Tcp {
+			for _, dw := range route.Route {
+				add(dw.Destination)
+			}
+		}
+	}
+	return out
+}
+
+// pruneSubsets returns a copy of the DestinationRule without the unused subsets of its host
+func pruneSubsets(config istiomodel.Config, unused map[string]map[string]bool) istiomodel.Config {
+	spec, ok := config.Spec.(*v1alpha3.DestinationRule)
+	if !ok || len(unused[spec.Host]) == 0 {
+		return config
+	}
+
+	newSpec := *spec
+	newSpec.Subsets = make([]*v1alpha3.Subset, 0, len(spec.Subsets))
+	for _, subset := range spec.Subsets {
+		if !unused[spec.Host][subset.Name] {
+			newSpec.Subsets = append(newSpec.Subsets, subset)
+		}
+	}
+	config.Spec = &newSpec
+	return config
+}
+
+func drHost(config istiomodel.Config) string {
+	if spec, ok := config.Spec.(*v1alpha3.DestinationRule); ok {
+		return spec.Host
+	}
+	return ""
+}
+
+func configIndex(config istiomodel.Config) string {
+	return fmt.Sprintf("%s+%s+%s", config.Type, config.Namespace, config.Name)
+}
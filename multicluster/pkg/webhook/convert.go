@@ -0,0 +1,160 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	istiomodel "istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/log"
+
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	mccrd "github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/config/kube/crd"
+	mcmodel "github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/model"
+)
+
+// ConvertPath is the path the webhook serves the ConversionReviews on
+const ConvertPath = "/convert"
+
+// ConversionReview is the apiextensions.k8s.io/v1 ConversionReview the API
+// server sends to convert the Multi-cluster configs between the versions of
+// their CRDs. The apiextensions types are not available in the vendored API.
+type ConversionReview struct {
+	meta_v1.TypeMeta `json:",inline"`
+	Request          *ConversionRequest  `json:"request,omitempty"`
+	Response         *ConversionResponse `json:"response,omitempty"`
+}
+
+// ConversionRequest holds the objects to convert to the desired API version
+type ConversionRequest struct {
+	UID               types.UID              `json:"uid"`
+	DesiredAPIVersion string                 `json:"desiredAPIVersion"`
+	Objects           []runtime.RawExtension `json:"objects"`
+}
+
+// ConversionResponse holds the converted objects, in the order of the request
+type ConversionResponse struct {
+	UID              types.UID              `json:"uid"`
+	ConvertedObjects []runtime.RawExtension `json:"convertedObjects"`
+	Result           meta_v1.Status         `json:"result"`
+}
+
+// Handler function for the ConversionReview requests of the API server. The
+// review is written back with the response filled in.
+func (wh *Webhook) handleConversion(w http.ResponseWriter, req *http.Request) {
+	if ct := req.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		http.Error(w, fmt.Sprintf("unsupported content type %q", ct), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	review := ConversionReview{}
+	if err := json.NewDecoder(req.Body).Decode(&review); err != nil || review.Request == nil {
+		http.Error(w, fmt.Sprintf("invalid ConversionReview: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	review.Response = convertObjects(review.Request)
+	review.Request = nil
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&review); err != nil {
+		log.Errorf("Failed to write the ConversionReview response: %v", err)
+	}
+}
+
+// convertObjects converts the objects of a conversion request. The request
+// fails as a whole if any object can't be converted.
+func convertObjects(req *ConversionRequest) *ConversionResponse {
+	resp := &ConversionResponse{UID: req.UID}
+	desired, err := schema.ParseGroupVersion(req.DesiredAPIVersion)
+	if err == nil {
+		resp.ConvertedObjects = make([]runtime.RawExtension, len(req.Objects))
+		for i, object := range req.Objects {
+			if resp.ConvertedObjects[i].Raw, err = convertObject(object.Raw, desired); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		resp.ConvertedObjects = nil
+		resp.Result = meta_v1.Status{
+			Status:  meta_v1.StatusFailure,
+			Message: err.Error(),
+		}
+		return resp
+	}
+	resp.Result = meta_v1.Status{Status: meta_v1.StatusSuccess}
+	return resp
+}
+
+// convertObject converts the JSON of a Multi-cluster config to the API
+// version. Only the spec, the annotations holding the specs v1alpha1 can't
+// express and the apiVersion change, the rest of the object is kept as is.
+func convertObject(raw []byte, desired schema.GroupVersion) ([]byte, error) {
+	obj := mccrd.IstioKind{}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, fmt.Errorf("cannot decode the object: %v", err)
+	}
+	s, exists := mcmodel.MultiClusterConfigTypes.GetByType(mccrd.CamelCaseToKabobCase(obj.Kind))
+	if !exists {
+		return nil, fmt.Errorf("unknown kind %s", obj.Kind)
+	}
+	config, err := mccrd.ConvertObject(s, &obj, "")
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the %s %s/%s: %v", obj.Kind, obj.Namespace, obj.Name, err)
+	}
+
+	switch desired {
+	case schema.GroupVersion{Group: mccrd.ResourceGroup(&s), Version: s.Version}:
+	case schema.GroupVersion{Group: mccrd.ResourceGroup(&s), Version: mcmodel.V1alpha1ConfigTypes[0].Version}:
+		converted, err := mcmodel.ConfigToV1alpha1(*config)
+		if err != nil {
+			return nil, err
+		}
+		config = &converted
+	default:
+		return nil, fmt.Errorf("cannot convert the %s %s/%s to %s", obj.Kind, obj.Namespace, obj.Name, desired)
+	}
+	spec, err := istiomodel.ToJSONMap(config.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update the raw object rather than obj, which only has some of the metadata
+	out := make(map[string]interface{})
+	if err = json.Unmarshal(raw, &out); err != nil {
+		return nil, err
+	}
+	out["apiVersion"] = desired.String()
+	out["spec"] = spec
+	metadata, _ := out["metadata"].(map[string]interface{})
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+		out["metadata"] = metadata
+	}
+	if len(config.Annotations) > 0 {
+		metadata["annotations"] = config.Annotations
+	} else {
+		delete(metadata, "annotations")
+	}
+	return json.Marshal(out)
+}
@@ -0,0 +1,178 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/ghodss/yaml"
+
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const multiPortSEP = `
+apiVersion: multicluster.istio.io/v1alpha2
+kind: ServiceExpositionPolicy
+metadata:
+  name: reviews
+  namespace: default
+  uid: 7c4a
+spec:
+  exposed:
+  - name: reviews
+    ports:
+    - number: 9080
+    - number: 9090
+      protocol: GRPC`
+
+const multiPortSEPV1alpha1 = `
+apiVersion: multicluster.istio.io/v1alpha1
+kind: ServiceExpositionPolicy
+metadata:
+  name: reviews
+  namespace: default
+  uid: 7c4a
+  annotations:
+    multicluster.istio.io/v1alpha2-spec: '{"exposed":[{"name":"reviews","ports":[{"number":9080},{"number":9090,"protocol":"GRPC"}]}]}'
+spec:
+  exposed:
+  - name: reviews
+    port: 9080`
+
+func TestConversion(t *testing.T) {
+	server := httptest.NewServer(NewWebhook(0, "", "", nil, nil, nil, nil).Handler())
+	defer server.Close()
+
+	tt := []struct {
+		name    string
+		desired string
+		object  string
+		want    string // the converted object, none if the conversion fails
+	}{
+		{name: "v1alpha2 to v1alpha1",
+			desired: "multicluster.istio.io/v1alpha1",
+			object:  multiPortSEP,
+			want:    multiPortSEPV1alpha1},
+		{name: "v1alpha1 to v1alpha2",
+			desired: "multicluster.istio.io/v1alpha2",
+			object:  multiPortSEPV1alpha1,
+			want:    multiPortSEP},
+		{name: "v1alpha1 binding to v1alpha2",
+			desired: "multicluster.istio.io/v1alpha2",
+			object: `
+apiVersion: multicluster.istio.io/v1alpha1
+kind: RemoteServiceBinding
+metadata:
+  name: ratings
+spec:
+  remote:
+  - cluster: cluster-c
+    services:
+    - name: ratings
+      port: 9080
+  mode: FALLBACK
+status: {}`,
+			want: `
+apiVersion: multicluster.istio.io/v1alpha2
+kind: RemoteServiceBinding
+metadata:
+  name: ratings
+spec:
+  remote:
+  - cluster: cluster-c
+    services:
+    - name: ratings
+      ports:
+      - number: 9080
+  mode: FALLBACK
+status: {}`},
+		{name: "unknown version",
+			desired: "multicluster.istio.io/v2",
+			object:  multiPortSEP},
+		{name: "unknown kind",
+			desired: "multicluster.istio.io/v1alpha1",
+			object:  `{"apiVersion": "v1", "kind": "Service", "metadata": {"name": "reviews"}}`},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			raw, err := yaml.YAMLToJSON([]byte(tc.object))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := &ConversionRequest{
+				UID:               "uid-1",
+				DesiredAPIVersion: tc.desired,
+				Objects:           []runtime.RawExtension{{Raw: raw}},
+			}
+			resp := postConversion(t, server.URL+ConvertPath, &ConversionReview{Request: req})
+			if resp.UID != req.UID {
+				t.Errorf("got UID %q, want %q", resp.UID, req.UID)
+			}
+
+			if tc.want == "" {
+				if resp.Result.Status != meta_v1.StatusFailure || len(resp.ConvertedObjects) != 0 {
+					t.Errorf("got status %q and %d objects, want a failure", resp.Result.Status, len(resp.ConvertedObjects))
+				}
+				return
+			}
+			if resp.Result.Status != meta_v1.StatusSuccess || len(resp.ConvertedObjects) != 1 {
+				t.Fatalf("got status %q (%s) and %d objects, want 1 object", resp.Result.Status, resp.Result.Message,
+					len(resp.ConvertedObjects))
+			}
+			var got, want interface{}
+			if err = json.Unmarshal(resp.ConvertedObjects[0].Raw, &got); err != nil {
+				t.Fatal(err)
+			}
+			if err = yaml.Unmarshal([]byte(tc.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				gotYAML, _ := yaml.Marshal(got)
+				t.Errorf("got\n%s\nwant%s", gotYAML, tc.want)
+			}
+		})
+	}
+}
+
+func postConversion(t *testing.T, url string, review *ConversionReview) *ConversionResponse {
+	t.Helper()
+	body, err := json.Marshal(review)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close() // nolint: errcheck
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d", resp.StatusCode)
+	}
+	out := ConversionReview{}
+	if err = json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Response == nil {
+		t.Fatal("the review has no response")
+	}
+	return out.Response
+}
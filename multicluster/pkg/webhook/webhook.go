@@ -0,0 +1,274 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhook implements a validating admission webhook rejecting the
+// invalid Multi-cluster configs when they are applied, rather than having the
+// agent fail to realize them later. It also converts the configs between the
+// versions of their CRDs.
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	multierror "github.com/hashicorp/go-multierror"
+
+	istiomodel "istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/log"
+
+	admission "k8s.io/api/admission/v1beta1"
+	kube_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mccrd "github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/config/kube/crd"
+	mcmodel "github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/model"
+	"github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/reconcile"
+)
+
+// ValidatePath is the path the webhook serves the AdmissionReviews on
+const ValidatePath = "/validate"
+
+// Webhook is a validating admission webhook for the Multi-cluster configs.
+// A config is rejected if it fails the validation of its schema or the
+// semantic validation against the other Multi-cluster configs, the Istio
+// configs and the K8s Services of the cluster.
+type Webhook struct {
+	httpServer http.Server
+	certFile   string
+	keyFile    string
+
+	clusters   mcmodel.ClusterRegistry
+	store      istiomodel.ConfigStore
+	istioStore istiomodel.ConfigStore
+	services   reconcile.ServiceLister
+}
+
+// NewWebhook creates a webhook serving HTTPS on the port with the certificate
+// and key files. The Multi-cluster configs are validated against the
+// Multi-cluster configs of the store, the Istio configs of the Istio store
+// and the K8s Services; the checks needing a nil store, lister or registry
+// are skipped. The webhook will start listening only when the Run() function
+// is called.
+func NewWebhook(port uint16, certFile, keyFile string, clusters mcmodel.ClusterRegistry,
+	store, istioStore istiomodel.ConfigStore, services reconcile.ServiceLister) *Webhook {
+	wh := &Webhook{
+		certFile:   certFile,
+		keyFile:    keyFile,
+		clusters:   clusters,
+		store:      store,
+		istioStore: istioStore,
+		services:   services,
+	}
+	router := mux.NewRouter()
+	_ = router.NewRoute().Path(ValidatePath).Methods("POST").HandlerFunc(wh.handleReview)
+	_ = router.NewRoute().Path(ConvertPath).Methods("POST").HandlerFunc(wh.handleConversion)
+	wh.httpServer = http.Server{
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		Addr:         fmt.Sprintf(":%d", port),
+		Handler:      router,
+	}
+	return wh
+}
+
+// Handler returns the HTTP handler of the webhook
+func (wh *Webhook) Handler() http.Handler {
+	return wh.httpServer.Handler
+}
+
+// Run will start listening and serving requests in a go routine
+func (wh *Webhook) Run() {
+	go func() {
+		if err := wh.httpServer.ListenAndServeTLS(wh.certFile, wh.keyFile); err != nil && err != http.ErrServerClosed {
+			log.Errora(err)
+		}
+	}()
+}
+
+// Close cleans up resources used by the webhook
+func (wh *Webhook) Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := wh.httpServer.Shutdown(ctx); err != nil {
+		log.Errora("Failed to shutdown the webhook server", err)
+	}
+	cancel()
+	log.Debug("Webhook server closed")
+}
+
+// Handler function for the AdmissionReview requests of the API server. The
+// review is written back with the response filled in.
+func (wh *Webhook) handleReview(w http.ResponseWriter, req *http.Request) {
+	if ct := req.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		http.Error(w, fmt.Sprintf("unsupported content type %q", ct), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	review := admission.AdmissionReview{}
+	if err := json.NewDecoder(req.Body).Decode(&review); err != nil || review.Request == nil {
+		http.Error(w, fmt.Sprintf("invalid AdmissionReview: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	review.Response = wh.review(review.Request)
+	review.Response.UID = review.Request.UID
+	review.Request = nil
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&review); err != nil {
+		log.Errorf("Failed to write the AdmissionReview response: %v", err)
+	}
+}
+
+// review admits or rejects the config of an admission request
+func (wh *Webhook) review(req *admission.AdmissionRequest) *admission.AdmissionResponse {
+	if req.Operation == admission.Delete {
+		return &admission.AdmissionResponse{Allowed: true}
+	}
+	schema, exists := mcmodel.MultiClusterConfigTypes.GetByType(mccrd.CamelCaseToKabobCase(req.Kind.Kind))
+	if !exists {
+		// Not ours to judge, the webhook was registered for more kinds
+		log.Warnf("Admitting unknown kind %s of %s/%s", req.Kind.Kind, req.Namespace, req.Name)
+		return &admission.AdmissionResponse{Allowed: true}
+	}
+
+	config, err := decodeConfig(schema, req, req.Object.Raw)
+	if err != nil {
+		return deny(err)
+	}
+
+	if err = schema.Validate(config.Name, config.Namespace, config.Spec); err != nil {
+		return deny(multierror.Prefix(err, fmt.Sprintf("%s %s/%s is invalid:", req.Kind.Kind, config.Namespace, config.Name)))
+	}
+	err = wh.validateSemantics(*config)
+	if err != nil && req.Operation == admission.Update && len(req.OldObject.Raw) > 0 {
+		// Only the errors introduced by the update are denied, so that the
+		// status or approval of a config can be updated even if it became
+		// invalid after it was applied, e.g. when a peer left
+		if old, oldErr := decodeConfig(schema, req, req.OldObject.Raw); oldErr == nil {
+			err = introducedErrors(err, wh.validateSemantics(*old))
+		}
+	}
+	if err != nil {
+		return deny(err)
+	}
+	return &admission.AdmissionResponse{Allowed: true}
+}
+
+// decodeConfig decodes the raw object of the admission request into a config
+// of the schema
+func decodeConfig(schema istiomodel.ProtoSchema, req *admission.AdmissionRequest, raw []byte) (*istiomodel.Config, error) {
+	obj := mccrd.IstioKind{}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, fmt.Errorf("cannot decode the %s: %v", req.Kind.Kind, err)
+	}
+	if obj.Namespace == "" {
+		obj.Namespace = req.Namespace
+	}
+	if obj.APIVersion == "" {
+		obj.APIVersion = req.Kind.Group + "/" + req.Kind.Version
+	}
+	config, err := mccrd.ConvertObject(schema, &obj, "")
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the %s: %v", req.Kind.Kind, err)
+	}
+	return config, nil
+}
+
+// introducedErrors returns the errors of the updated config that the config
+// did not have before the update
+func introducedErrors(errs, oldErrs error) error {
+	existing := make(map[string]bool)
+	for _, err := range errorList(oldErrs) {
+		existing[err.Error()] = true
+	}
+	var introduced error
+	for _, err := range errorList(errs) {
+		if !existing[err.Error()] {
+			introduced = multierror.Append(introduced, err)
+		}
+	}
+	return introduced
+}
+
+// errorList returns the errors combined in err
+func errorList(err error) []error {
+	if merr, ok := err.(*multierror.Error); ok {
+		return merr.Errors
+	}
+	if err != nil {
+		return []error{err}
+	}
+	return nil
+}
+
+// validateSemantics returns the semantic errors of the config, validated
+// with the other Multi-cluster configs as if it was applied
+func (wh *Webhook) validateSemantics(config istiomodel.Config) error {
+	var mcs []istiomodel.Config
+	if wh.store != nil {
+		for _, typ := range mcmodel.MultiClusterConfigTypes.Types() {
+			configs, err := wh.store.List(typ, kube_v1.NamespaceAll)
+			if err != nil {
+				return multierror.Prefix(err, "cannot list the Multi-cluster configs:")
+			}
+			for _, c := range configs {
+				if c.Type != config.Type || c.Namespace != config.Namespace || c.Name != config.Name {
+					mcs = append(mcs, c)
+				}
+			}
+		}
+	}
+	mcs = append(mcs, config)
+
+	var svcs []kube_v1.Service
+	if wh.services != nil {
+		var err error
+		if svcs, err = wh.services.List(config.Namespace); err != nil {
+			return multierror.Prefix(err, "cannot list the K8s Services:")
+		}
+	}
+
+	var errs error
+	err := mcmodel.ValidateSemantics(mcs, wh.clusters, wh.istioStore, svcs)
+	for _, fe := range mcmodel.FieldErrorsOf(err, config) {
+		errs = multierror.Append(errs, fe)
+	}
+	return errs
+}
+
+// deny returns a response rejecting the request with the errors as message
+func deny(err error) *admission.AdmissionResponse {
+	message := err.Error()
+	if merr, ok := err.(*multierror.Error); ok {
+		messages := make([]string, len(merr.Errors))
+		for i, err := range merr.Errors {
+			messages[i] = err.Error()
+		}
+		message = strings.Join(messages, "; ")
+	}
+	return &admission.AdmissionResponse{
+		Allowed: false,
+		Result: &meta_v1.Status{
+			Status:  meta_v1.StatusFailure,
+			Reason:  meta_v1.StatusReasonInvalid,
+			Message: message,
+			Code:    http.StatusUnprocessableEntity,
+		},
+	}
+}
@@ -0,0 +1,293 @@
+// (C) Copyright IBM Corp. 2018. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+
+	"istio.io/istio/pilot/pkg/config/memory"
+	istiomodel "istio.io/istio/pilot/pkg/model"
+
+	admission "k8s.io/api/admission/v1beta1"
+	kube_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/istio-ecosystem/wharf-multicluster-sync/api/multicluster/v1alpha2"
+	mcmodel "github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/model"
+	"github.com/istio-ecosystem/wharf-multicluster-sync/multicluster/pkg/reconcile"
+)
+
+// peers is a model.ClusterRegistry of a fixed list of peers
+type peers []string
+
+func (p peers) IsPeer(id string) bool {
+	for _, peer := range p {
+		if peer == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (p peers) ConversionStyleName(config istiomodel.Config) (string, error) {
+	return mcmodel.DefaultConversionStyle, nil
+}
+
+func TestWebhook(t *testing.T) {
+	store := memory.Make(mcmodel.MultiClusterConfigTypes)
+	_, err := store.Create(istiomodel.Config{
+		ConfigMeta: istiomodel.ConfigMeta{
+			Type:              mcmodel.ServiceExpositionPolicy.Type,
+			Name:              "ratings",
+			Namespace:         "default",
+			CreationTimestamp: meta_v1.Now(),
+		},
+		Spec: &v1alpha2.ServiceExpositionPolicy{
+			Exposed: []*v1alpha2.ServiceExpositionPolicy_ExposedService{{Name: "ratings", Alias: "shared"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	services := reconcile.ServiceList{{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "default"},
+		Spec:       kube_v1.ServiceSpec{Ports: []kube_v1.ServicePort{{Port: 9080}}},
+	}}
+	wh := NewWebhook(0, "", "", peers{"cluster-b"}, store, memory.Make(istiomodel.IstioConfigTypes), services)
+	server := httptest.NewServer(wh.Handler())
+	defer server.Close()
+
+	tt := []struct {
+		name      string
+		kind      string
+		operation admission.Operation
+		object    string
+		oldObject string // The config before an update
+		message   string // Expected message, the config is admitted if empty
+	}{
+		{name: "valid exposure",
+			kind: "ServiceExpositionPolicy",
+			object: `
+metadata:
+  name: reviews
+spec:
+  exposed:
+  - name: reviews
+    port: 9080`},
+		{name: "invalid name",
+			kind: "ServiceExpositionPolicy",
+			object: `
+metadata:
+  name: reviews
+spec:
+  exposed:
+  - name: Reviews_v1`,
+			message: `ServiceExpositionPolicy default/reviews is invalid: invalid name: "Reviews_v1"`},
+		{name: "alias of another service",
+			kind: "ServiceExpositionPolicy",
+			object: `
+metadata:
+  name: reviews
+spec:
+  exposed:
+  - name: reviews
+    alias: shared
+    port: 8080`,
+			message: `ServiceExpositionPolicy default/reviews: spec.exposed[0].alias: "shared" already exposes service "ratings" ` +
+				`by ServiceExpositionPolicy default/ratings; ` +
+				`ServiceExpositionPolicy default/reviews: spec.exposed[0].ports[0].number: port 8080 is not a port of K8s Service default/reviews`},
+		{name: "update keeping the alias",
+			kind:      "ServiceExpositionPolicy",
+			operation: admission.Update,
+			object: `
+metadata:
+  name: ratings
+spec:
+  exposed:
+  - name: ratings
+    alias: shared
+    clusters:
+    - cluster-b`},
+		{name: "unknown cluster",
+			kind: "RemoteServiceBinding",
+			object: `
+metadata:
+  name: details
+spec:
+  remote:
+  - cluster: cluster-b
+    services:
+    - name: details
+  - cluster: cluster-z
+    services:
+    - name: details`,
+			message: `RemoteServiceBinding default/details: spec.remote[1].cluster: cluster "cluster-z" is not a peer in the cluster configuration`},
+		{name: "status update of a binding of a former peer",
+			kind:      "RemoteServiceBinding",
+			operation: admission.Update,
+			oldObject: `
+metadata:
+  name: details
+spec:
+  remote:
+  - cluster: cluster-z
+    services:
+    - name: details`,
+			object: `
+metadata:
+  name: details
+  annotations:
+    multicluster.istio.io/status: '{"phase":"Failed","steps":[]}'
+    multicluster.istio.io/approval: Approved
+spec:
+  remote:
+  - cluster: cluster-z
+    services:
+    - name: details`},
+		{name: "update introducing an error",
+			kind:      "RemoteServiceBinding",
+			operation: admission.Update,
+			oldObject: `
+metadata:
+  name: details
+spec:
+  remote:
+  - cluster: cluster-z
+    services:
+    - name: details`,
+			object: `
+metadata:
+  name: details
+  annotations:
+    multicluster.istio.io/approval: Aproved
+spec:
+  remote:
+  - cluster: cluster-z
+    services:
+    - name: details`,
+			message: `RemoteServiceBinding default/details: metadata.annotations[multicluster.istio.io/approval]: ` +
+				`unknown approval phase "Aproved", expected Pending or Approved; the binding is not realized`},
+		{name: "deletion",
+			kind:      "RemoteServiceBinding",
+			operation: admission.Delete},
+		{name: "other kind",
+			kind:   "Deployment",
+			object: `{"metadata": {"name": "details"}}`},
+	}
+
+	for i, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			operation := tc.operation
+			if operation == "" {
+				operation = admission.Create
+			}
+			req := &admission.AdmissionRequest{
+				UID:       types.UID(fmt.Sprintf("uid-%d", i)),
+				Kind:      meta_v1.GroupVersionKind{Group: "multicluster.istio.io", Version: "v1alpha1", Kind: tc.kind},
+				Namespace: "default",
+				Operation: operation,
+			}
+			if tc.object != "" {
+				raw, err := yaml.YAMLToJSON([]byte(tc.object))
+				if err != nil {
+					t.Fatal(err)
+				}
+				req.Object = runtime.RawExtension{Raw: raw}
+			}
+			if tc.oldObject != "" {
+				raw, err := yaml.YAMLToJSON([]byte(tc.oldObject))
+				if err != nil {
+					t.Fatal(err)
+				}
+				req.OldObject = runtime.RawExtension{Raw: raw}
+			}
+
+			resp := postReview(t, server.URL+ValidatePath, &admission.AdmissionReview{Request: req})
+			if resp.UID != req.UID {
+				t.Errorf("got UID %q, want %q", resp.UID, req.UID)
+			}
+			if resp.Allowed != (tc.message == "") {
+				t.Errorf("got allowed %t, want %t", resp.Allowed, tc.message == "")
+			}
+			message := ""
+			if resp.Result != nil {
+				message = resp.Result.Message
+			}
+			if message != tc.message {
+				t.Errorf("got message\n%s\nwant\n%s", message, tc.message)
+			}
+		})
+	}
+}
+
+func TestWebhookBadRequests(t *testing.T) {
+	server := httptest.NewServer(NewWebhook(0, "", "", nil, nil, nil, nil).Handler())
+	defer server.Close()
+
+	tt := []struct {
+		contentType string
+		body        string
+		status      int
+	}{
+		{contentType: "text/plain", body: "{}", status: http.StatusUnsupportedMediaType},
+		{contentType: "application/json", body: "{", status: http.StatusBadRequest},
+		{contentType: "application/json", body: "{}", status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tt {
+		resp, err := http.Post(server.URL+ValidatePath, tc.contentType, strings.NewReader(tc.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close() // nolint: errcheck
+		if resp.StatusCode != tc.status {
+			t.Errorf("%s %q: got status %d, want %d", tc.contentType, tc.body, resp.StatusCode, tc.status)
+		}
+	}
+}
+
+func postReview(t *testing.T, url string, review *admission.AdmissionReview) *admission.AdmissionResponse {
+	t.Helper()
+	body, err := json.Marshal(review)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close() // nolint: errcheck
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d", resp.StatusCode)
+	}
+	out := admission.AdmissionReview{}
+	if err = json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Response == nil {
+		t.Fatal("the review has no response")
+	}
+	return out.Response
+}